@@ -0,0 +1,143 @@
+package dht
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc/peer"
+
+	"github.com/structx/go-dpkg/domain"
+	pbv1 "github.com/structx/go-dpkg/proto/dht/v1"
+)
+
+// ListenAddr host and port the dht service listens on
+func ListenAddr(cfg domain.Config) (string, error) {
+
+	dcfg := cfg.GetDistributedHashTable()
+	if dcfg == nil {
+		return "", errors.New("missing distributed hash table configuration")
+	}
+
+	if dcfg.Ports == nil || dcfg.Ports.GRPC <= 0 {
+		return "", errors.New("missing distributed hash table grpc port")
+	}
+
+	return net.JoinHostPort(dcfg.BindAddr, strconv.Itoa(dcfg.Ports.GRPC)), nil
+}
+
+// UDPListenAddr host and port the udp transport listens on
+func UDPListenAddr(cfg domain.Config) (string, error) {
+
+	dcfg := cfg.GetDistributedHashTable()
+	if dcfg == nil {
+		return "", errors.New("missing distributed hash table configuration")
+	}
+
+	if dcfg.Ports == nil || dcfg.Ports.UDP <= 0 {
+		return "", errors.New("missing distributed hash table udp port")
+	}
+
+	return net.JoinHostPort(dcfg.BindAddr, strconv.Itoa(dcfg.Ports.UDP)), nil
+}
+
+// AdvertiseAddr ip and port other nodes use to reach the local node
+//
+// advertise_addr may be a host or host:port, without a port the grpc
+// port is advertised. without advertise_addr the bind address is
+// advertised, an empty ip for wildcard bind addresses is learned from peers
+func AdvertiseAddr(cfg domain.Config) (string, int, error) {
+
+	dcfg := cfg.GetDistributedHashTable()
+	if dcfg == nil {
+		return "", 0, errors.New("missing distributed hash table configuration")
+	}
+
+	var port int
+	if dcfg.Ports != nil {
+		port = dcfg.Ports.GRPC
+	}
+
+	if dcfg.AdvertiseAddr == nil || *dcfg.AdvertiseAddr == "" {
+		if unspecified(dcfg.BindAddr) {
+			return "", port, nil
+		}
+		return dcfg.BindAddr, port, nil
+	}
+
+	addr := *dcfg.AdvertiseAddr
+
+	host, p, err := net.SplitHostPort(addr)
+	if err != nil {
+		// host without port
+		if unspecified(addr) {
+			return "", 0, fmt.Errorf("invalid advertise address %s", addr)
+		}
+		return addr, port, nil
+	}
+
+	port, err = strconv.Atoi(p)
+	if err != nil || port <= 0 {
+		return "", 0, fmt.Errorf("invalid advertise port %s", addr)
+	}
+
+	if unspecified(host) {
+		return "", 0, fmt.Errorf("invalid advertise address %s", addr)
+	}
+
+	return host, port, nil
+}
+
+// senderKey context key of the verified sender id
+type senderKey struct{}
+
+// verifiedSender node id of the sender verified by VerifySender
+func verifiedSender[T domain.NodeID](ctx context.Context) (T, bool) {
+	nodeID, ok := ctx.Value(senderKey{}).(T)
+	return nodeID, ok
+}
+
+// peerIP address a request was received from
+func peerIP(ctx context.Context) string {
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return ""
+	}
+
+	return host
+}
+
+// senderContact contact of verified sender at the address the request was
+// received from, the advertised ip is chosen by the sender and not trusted
+func senderContact[T domain.NodeID](ctx context.Context, s *pbv1.Sender) (*domain.Contact[T], bool) {
+
+	nodeID, ok := verifiedSender[T](ctx)
+	if !ok || s.GetAdvertisePort() <= 0 || s.GetAdvertisePort() > 65535 {
+		// unverified or not listening
+		return nil, false
+	}
+
+	ip := peerIP(ctx)
+	if ip == "" {
+		return nil, false
+	}
+
+	return &domain.Contact[T]{IP: ip, Port: int(s.GetAdvertisePort()), ID: nodeID}, true
+}
+
+// unspecified ip is empty or a wildcard listen address
+func unspecified(ip string) bool {
+	if ip == "" {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.IsUnspecified()
+}
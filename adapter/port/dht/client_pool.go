@@ -0,0 +1,364 @@
+package dht
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+type pooledClient[T domain.NodeID] struct {
+	client *Client[T]
+	// nodeID server is expected to prove, zero if unknown when dialed
+	nodeID   T
+	lastUsed time.Time
+}
+
+// Pool gRPC clients keyed by contact address
+//
+// connections are reused between calls and closed once
+// they have been idle for longer than the idle timeout
+// or the underlying connection has failed. connections are
+// secured with NodeCredentials of the node key, servers
+// must prove the node id of the contact dialed
+type Pool[T domain.NodeID] struct {
+	mtx     sync.Mutex
+	clients map[string]*pooledClient[T]
+
+	key   ed25519.PrivateKey
+	creds *NodeCredentials[T]
+
+	// advertise configured address, replaced by
+	// the contact of the attached node
+	advertise domain.Contact[T]
+	dmtx      sync.RWMutex
+	dht       domain.DHT[T]
+
+	dialTimeout    time.Duration
+	requestTimeout time.Duration
+	idleTimeout    time.Duration
+	// dialOpts additional options of every connection
+	dialOpts []grpc.DialOption
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// interface compliance
+var _ domain.Transport[domain.NodeID224] = (*Pool[domain.NodeID224])(nil)
+
+// NewPool constructor, dial options are applied to every connection
+// and grpc.WithTransportCredentials replaces the node credentials
+func NewPool[T domain.NodeID](cfg domain.Config, key ed25519.PrivateKey, opts ...grpc.DialOption) (*Pool[T], error) {
+
+	dcfg := cfg.GetDistributedHashTable()
+	if dcfg == nil {
+		return nil, errors.New("missing distributed hash table configuration")
+	}
+
+	ip, port, err := AdvertiseAddr(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := NewCredentials[T](key)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Pool[T]{
+		clients:        make(map[string]*pooledClient[T]),
+		key:            key,
+		creds:          creds,
+		advertise:      domain.Contact[T]{IP: ip, Port: port, ID: domain.NodeIDFromPublicKey[T](key.Public().(ed25519.PublicKey))},
+		dialTimeout:    DefaultDialTimeout,
+		requestTimeout: DefaultRequestTimeout,
+		idleTimeout:    DefaultIdleTimeout,
+		dialOpts:       opts,
+		done:           make(chan struct{}),
+	}
+
+	if t := dcfg.Timeouts; t != nil {
+		if t.Dial > 0 {
+			p.dialTimeout = time.Duration(t.Dial) * time.Millisecond
+		}
+		if t.Request > 0 {
+			p.requestTimeout = time.Duration(t.Request) * time.Millisecond
+		}
+		if t.Idle > 0 {
+			p.idleTimeout = time.Duration(t.Idle) * time.Millisecond
+		}
+	}
+
+	p.wg.Add(1)
+	go p.evictIdle()
+
+	return p, nil
+}
+
+// Attach node using the pool as transport, requests advertise the
+// contact of the node and external addresses reported by peers are
+// passed to the node
+func (p *Pool[T]) Attach(dht domain.DHT[T]) {
+	p.dmtx.Lock()
+	defer p.dmtx.Unlock()
+	p.dht = dht
+}
+
+// Get pooled client for address, dial new connection if none is available
+func (p *Pool[T]) Get(ctx context.Context, address string) (*Client[T], error) {
+	var zero T
+	return p.get(ctx, address, zero)
+}
+
+// client pooled client for contact, the server must prove the contact
+// node id unless it is unknown
+func (p *Pool[T]) client(ctx context.Context, c *domain.Contact[T]) (*Client[T], error) {
+	return p.get(ctx, c.Address(), c.ID)
+}
+
+func (p *Pool[T]) get(ctx context.Context, address string, nodeID T) (*Client[T], error) {
+
+	var zero T
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if pc, ok := p.clients[address]; ok {
+		if !failed(pc.client.conn) && (nodeID == zero || pc.nodeID == nodeID) {
+			pc.lastUsed = time.Now()
+			return pc.client, nil
+		}
+
+		// connection failed or was not verified for
+		// node id, drop from pool and dial again
+		_ = pc.client.Close()
+		delete(p.clients, address)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, p.dialTimeout)
+	defer cancel()
+
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(p.creds.Expect(nodeID))}, p.dialOpts...)
+	conn, err := grpc.DialContext(timeout, address, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s %v", address, err)
+	}
+
+	c := &Client[T]{
+		conn:       conn,
+		timeout:    p.requestTimeout,
+		self:       p.self,
+		observe:    p.observe,
+		peer:       nodeID,
+		coordinate: p.observeCoordinate,
+	}
+	p.clients[address] = &pooledClient[T]{
+		client:   c,
+		nodeID:   nodeID,
+		lastUsed: time.Now(),
+	}
+
+	return c, nil
+}
+
+// Evict close and remove connection to address
+func (p *Pool[T]) Evict(address string) {
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if pc, ok := p.clients[address]; ok {
+		_ = pc.client.Close()
+		delete(p.clients, address)
+	}
+}
+
+// Len number of pooled connections
+func (p *Pool[T]) Len() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return len(p.clients)
+}
+
+// Ping contact, returns node id of responder
+func (p *Pool[T]) Ping(ctx context.Context, c *domain.Contact[T]) (T, error) {
+
+	var zero T
+
+	cli, err := p.client(ctx, c)
+	if err != nil {
+		return zero, err
+	}
+
+	nodeID, err := cli.Ping(ctx, p.key)
+	p.release(c.Address(), err)
+	return nodeID, err
+}
+
+// Store record on contact
+func (p *Pool[T]) Store(ctx context.Context, c *domain.Contact[T], record *domain.Record[T]) error {
+
+	cli, err := p.client(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	err = cli.Store(ctx, record, p.key)
+	p.release(c.Address(), err)
+	return err
+}
+
+// FindNode ask contact for closest contacts to node id
+func (p *Pool[T]) FindNode(ctx context.Context, c *domain.Contact[T], nodeID T) ([]*domain.Contact[T], error) {
+
+	cli, err := p.client(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	contactSlice, err := cli.FindNode(ctx, nodeID, p.key)
+	p.release(c.Address(), err)
+	return contactSlice, err
+}
+
+// FindValue ask contact for record stored under key
+func (p *Pool[T]) FindValue(ctx context.Context, c *domain.Contact[T], key T) (*domain.Record[T], []*domain.Contact[T], error) {
+
+	cli, err := p.client(ctx, c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record, contactSlice, err := cli.FindValue(ctx, key, p.key)
+	p.release(c.Address(), err)
+	return record, contactSlice, err
+}
+
+// AddProvider store provider record on contact
+func (p *Pool[T]) AddProvider(ctx context.Context, c *domain.Contact[T], provider *domain.Provider[T]) error {
+
+	cli, err := p.client(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	err = cli.AddProvider(ctx, provider, p.key)
+	p.release(c.Address(), err)
+	return err
+}
+
+// GetProviders ask contact for providers of key
+func (p *Pool[T]) GetProviders(ctx context.Context, c *domain.Contact[T], key T) ([]*domain.Provider[T], []*domain.Contact[T], error) {
+
+	cli, err := p.client(ctx, c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	providers, contactSlice, err := cli.GetProviders(ctx, key, p.key)
+	p.release(c.Address(), err)
+	return providers, contactSlice, err
+}
+
+// Close all pooled connections
+func (p *Pool[T]) Close() error {
+
+	close(p.done)
+	p.wg.Wait()
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	var errs []error
+	for address, pc := range p.clients {
+		if err := pc.client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close connection to %s %v", address, err))
+		}
+		delete(p.clients, address)
+	}
+
+	return errors.Join(errs...)
+}
+
+// self advertised contact of attached node or configured address
+func (p *Pool[T]) self() *domain.Contact[T] {
+	p.dmtx.RLock()
+	defer p.dmtx.RUnlock()
+
+	if p.dht != nil {
+		return p.dht.Contact()
+	}
+
+	c := p.advertise
+	return &c
+}
+
+// observe pass external ip reported by peer to attached node
+func (p *Pool[T]) observe(ctx context.Context, observer T, ip string) {
+	p.dmtx.RLock()
+	defer p.dmtx.RUnlock()
+
+	if p.dht != nil {
+		p.dht.ObserveAddr(ctx, observer, ip)
+	}
+}
+
+// observeCoordinate pass network coordinate of peer to attached node
+func (p *Pool[T]) observeCoordinate(ctx context.Context, nodeID T, c *domain.Coordinate) {
+	p.dmtx.RLock()
+	defer p.dmtx.RUnlock()
+
+	if p.dht != nil {
+		p.dht.ObserveCoordinate(ctx, nodeID, c)
+	}
+}
+
+// release evict connection if call failed due to an unavailable peer
+func (p *Pool[T]) release(address string, err error) {
+	if err == nil {
+		return
+	}
+
+	if status.Code(errors.Unwrap(err)) == codes.Unavailable {
+		p.Evict(address)
+	}
+}
+
+func (p *Pool[T]) evictIdle() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.idleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+
+			p.mtx.Lock()
+			for address, pc := range p.clients {
+				if time.Since(pc.lastUsed) > p.idleTimeout || failed(pc.client.conn) {
+					_ = pc.client.Close()
+					delete(p.clients, address)
+				}
+			}
+			p.mtx.Unlock()
+		}
+	}
+}
+
+func failed(conn *grpc.ClientConn) bool {
+	state := conn.GetState()
+	return state == connectivity.TransientFailure || state == connectivity.Shutdown
+}
@@ -0,0 +1,144 @@
+package dht_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/structx/go-dpkg/adapter/port/dht"
+	"github.com/structx/go-dpkg/adapter/setup"
+	"github.com/structx/go-dpkg/domain"
+	pbv1 "github.com/structx/go-dpkg/proto/dht/v1"
+	kademlia "github.com/structx/go-dpkg/structs/dht"
+	"github.com/structx/go-dpkg/util/decode"
+)
+
+func init() {
+	_ = os.Setenv("DSERVICE_CONFIG", "./testfiles/dht.test.hcl")
+}
+
+type stubServer struct {
+	*dht.GRPCServer[domain.NodeID224]
+	contacts []*pbv1.Contact
+}
+
+func (s *stubServer) Store(_ context.Context, _ *pbv1.StoreRequest) (*pbv1.StoreResponse, error) {
+	return &pbv1.StoreResponse{}, nil
+}
+
+func (s *stubServer) FindNode(_ context.Context, _ *pbv1.FindNodeRequest) (*pbv1.FindNodeResponse, error) {
+	return &pbv1.FindNodeResponse{ContactList: s.contacts}, nil
+}
+
+type PoolSuite struct {
+	suite.Suite
+	srv     *grpc.Server
+	contact *domain.Contact[domain.NodeID224]
+	pool    *dht.Pool[domain.NodeID224]
+}
+
+func (suite *PoolSuite) SetupTest() {
+
+	assert := suite.Assert()
+
+	cfg := setup.New()
+	assert.NoError(decode.ConfigFromEnv(cfg))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	assert.NoError(err)
+	p, err := strconv.Atoi(port)
+	assert.NoError(err)
+
+	pub, key, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+
+	suite.contact = &domain.Contact[domain.NodeID224]{IP: host, Port: p, ID: domain.NodeIDFromPublicKey[domain.NodeID224](pub)}
+
+	peerID := domain.HashKey[domain.NodeID224]([]byte("peer"))
+	creds, err := dht.NewCredentials[domain.NodeID224](key)
+	assert.NoError(err)
+
+	suite.srv = grpc.NewServer(grpc.Creds(creds), grpc.UnaryInterceptor(dht.VerifySender[domain.NodeID224]))
+	pbv1.RegisterDHTServiceServer(suite.srv, &stubServer{
+		GRPCServer: dht.NewGRPCServer[domain.NodeID224](zap.NewNop(), kademlia.NewNodeWithDefault[domain.NodeID224](context.TODO(), host, p, pub), key),
+		contacts:   []*pbv1.Contact{{Ip: "10.0.0.1", Port: 50051, NodeId: peerID[:]}},
+	})
+	go func() { _ = suite.srv.Serve(listener) }()
+
+	_, self, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+
+	suite.pool, err = dht.NewPool[domain.NodeID224](cfg, self)
+	assert.NoError(err)
+}
+
+func (suite *PoolSuite) TestReuse() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	nodeID, err := suite.pool.Ping(ctx, suite.contact)
+	assert.NoError(err)
+	assert.Equal(suite.contact.ID, nodeID)
+
+	assert.NoError(suite.pool.Store(ctx, suite.contact, &domain.Record[domain.NodeID224]{
+		Key:       domain.HashKey[domain.NodeID224]([]byte("key")),
+		Value:     []byte("value"),
+		Timestamp: time.Now(),
+	}))
+
+	contactSlice, err := suite.pool.FindNode(ctx, suite.contact, domain.HashKey[domain.NodeID224]([]byte("target")))
+	assert.NoError(err)
+	assert.Len(contactSlice, 1)
+	assert.Equal("10.0.0.1", contactSlice[0].IP)
+
+	assert.Equal(1, suite.pool.Len())
+}
+
+func (suite *PoolSuite) TestEvictIdle() {
+
+	assert := suite.Assert()
+
+	_, err := suite.pool.Ping(context.TODO(), suite.contact)
+	assert.NoError(err)
+	assert.Equal(1, suite.pool.Len())
+
+	assert.Eventually(func() bool {
+		return suite.pool.Len() == 0
+	}, time.Second, time.Millisecond*20)
+}
+
+func (suite *PoolSuite) TestEvictUnavailable() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	_, err := suite.pool.Ping(ctx, suite.contact)
+	assert.NoError(err)
+	suite.srv.Stop()
+
+	_, err = suite.pool.Ping(ctx, suite.contact)
+	assert.Error(err)
+	assert.Equal(0, suite.pool.Len())
+}
+
+func (suite *PoolSuite) TearDownTest() {
+	assert := suite.Assert()
+	suite.srv.Stop()
+	assert.NoError(suite.pool.Close())
+}
+
+func TestPoolSuite(t *testing.T) {
+	suite.Run(t, new(PoolSuite))
+}
@@ -0,0 +1,199 @@
+package dht
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+// authType of channels secured with node keys
+const authType = "dht-node"
+
+var (
+	// ErrPeerIdentity peer certificate does not carry an ed25519 node key
+	ErrPeerIdentity = errors.New("peer certificate is not a node key")
+	// ErrPeerMismatch peer proved a different node id than expected
+	ErrPeerMismatch = errors.New("peer node id does not match contact")
+)
+
+// NodeAuthInfo identity of the peer proven during the handshake
+type NodeAuthInfo[T domain.NodeID] struct {
+	credentials.CommonAuthInfo
+	NodeID    T
+	PublicKey ed25519.PublicKey
+	State     tls.ConnectionState
+}
+
+// AuthType implements credentials.AuthInfo
+func (NodeAuthInfo[T]) AuthType() string {
+	return authType
+}
+
+// NodeCredentials transport credentials securing dht connections with tls 1.3
+//
+// each side presents a self signed certificate of its node key, the
+// handshake signature proves ownership of the key the node id is derived
+// from. no certificate authority is involved, clients expecting a node id
+// reject servers proving another one
+type NodeCredentials[T domain.NodeID] struct {
+	cert tls.Certificate
+	// expected node id of the server, zero accepts any node
+	expected T
+}
+
+// interface compliance
+var _ credentials.TransportCredentials = (*NodeCredentials[domain.NodeID224])(nil)
+
+// NewCredentials constructor, use with grpc.Creds on the server and
+// grpc.WithTransportCredentials on the client
+func NewCredentials[T domain.NodeID](key ed25519.PrivateKey) (*NodeCredentials[T], error) {
+
+	pub := key.Public().(ed25519.PublicKey)
+	nodeID := domain.NodeIDFromPublicKey[T](pub)
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate serial %v", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: fmt.Sprintf("%x", domain.Bytes(nodeID))},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(10, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create node certificate %v", err)
+	}
+
+	return &NodeCredentials[T]{
+		cert: tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key},
+	}, nil
+}
+
+// Expect credentials rejecting servers that do not prove node id
+func (c *NodeCredentials[T]) Expect(nodeID T) *NodeCredentials[T] {
+	return &NodeCredentials[T]{cert: c.cert, expected: nodeID}
+}
+
+// ClientHandshake implements credentials.TransportCredentials
+func (c *NodeCredentials[T]) ClientHandshake(ctx context.Context, _ string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+
+	info := &NodeAuthInfo[T]{}
+
+	cfg := c.config(info)
+	// peers are verified against their node key instead of a certificate authority
+	cfg.InsecureSkipVerify = true
+
+	conn := tls.Client(rawConn, cfg)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("failed tls handshake %w", err)
+	}
+	info.State = conn.ConnectionState()
+
+	return conn, *info, nil
+}
+
+// ServerHandshake implements credentials.TransportCredentials
+func (c *NodeCredentials[T]) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+
+	info := &NodeAuthInfo[T]{}
+
+	cfg := c.config(info)
+	cfg.ClientAuth = tls.RequireAnyClientCert
+
+	conn := tls.Server(rawConn, cfg)
+	if err := conn.Handshake(); err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("failed tls handshake %w", err)
+	}
+	info.State = conn.ConnectionState()
+
+	return conn, *info, nil
+}
+
+// Info implements credentials.TransportCredentials
+func (c *NodeCredentials[T]) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{
+		SecurityProtocol: "tls",
+		SecurityVersion:  "1.3",
+	}
+}
+
+// Clone implements credentials.TransportCredentials
+func (c *NodeCredentials[T]) Clone() credentials.TransportCredentials {
+	return c.Expect(c.expected)
+}
+
+// OverrideServerName implements credentials.TransportCredentials,
+// servers are identified by node id and not by name
+func (c *NodeCredentials[T]) OverrideServerName(string) error {
+	return nil
+}
+
+// config tls configuration filling info with the verified peer identity
+func (c *NodeCredentials[T]) config(info *NodeAuthInfo[T]) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{c.cert},
+		MinVersion:   tls.VersionTLS13,
+		NextProtos:   []string{"h2"},
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+
+			nodeID, pub, err := peerIdentity[T](rawCerts)
+			if err != nil {
+				return err
+			}
+
+			var zero T
+			if c.expected != zero && c.expected != nodeID {
+				return ErrPeerMismatch
+			}
+
+			info.NodeID = nodeID
+			info.PublicKey = pub
+			info.SecurityLevel = credentials.PrivacyAndIntegrity
+			return nil
+		},
+	}
+}
+
+// peerIdentity node id of the key in the peer certificate, tls 1.3
+// verifies the peer signed the handshake with the same key
+func peerIdentity[T domain.NodeID](rawCerts [][]byte) (T, ed25519.PublicKey, error) {
+
+	var zero T
+
+	if len(rawCerts) == 0 {
+		return zero, nil, ErrPeerIdentity
+	}
+
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return zero, nil, fmt.Errorf("failed to parse peer certificate %v", err)
+	}
+
+	pub, ok := cert.PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return zero, nil, ErrPeerIdentity
+	}
+
+	return domain.NodeIDFromPublicKey[T](pub), pub, nil
+}
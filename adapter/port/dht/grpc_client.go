@@ -3,67 +3,435 @@ package dht
 
 import (
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/structx/go-dpkg/domain"
 	pbv1 "github.com/structx/go-dpkg/proto/dht/v1"
 )
 
+var (
+	// ErrMissingResult response contains neither value nor contacts
+	ErrMissingResult = errors.New("find value response without result")
+	// ErrMalformedResponse response contains invalid contacts, records or providers
+	ErrMalformedResponse = errors.New("malformed response")
+)
+
+const (
+	// DefaultDialTimeout maximum time to establish a connection
+	DefaultDialTimeout = time.Second
+	// DefaultRequestTimeout maximum time to wait for a response
+	DefaultRequestTimeout = time.Millisecond * 200
+	// DefaultIdleTimeout maximum time a pooled connection may go unused
+	DefaultIdleTimeout = time.Minute * 5
+)
+
 // Client implementation
-type Client struct {
-	conn *grpc.ClientConn
+type Client[T domain.NodeID] struct {
+	conn    *grpc.ClientConn
+	timeout time.Duration
+
+	// self advertised contact included in requests, nil advertises none
+	self func() *domain.Contact[T]
+	// observe external ip reported by ping responder, nil ignores it
+	observe func(ctx context.Context, observer T, ip string)
+	// peer node id proven by the server, zero if unknown
+	peer T
+	// coordinate network coordinate reported by responder, nil ignores it
+	coordinate func(ctx context.Context, nodeID T, c *domain.Coordinate)
 }
 
-// NewClient constructor
-func NewClient(address string) (*Client, error) {
+// NewClient constructor, connections are plaintext unless
+// options include grpc.WithTransportCredentials
+func NewClient[T domain.NodeID](address string, opts ...grpc.DialOption) (*Client[T], error) {
 
-	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(address, append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial client %v", err)
 	}
 
-	return &Client{
-		conn: conn,
+	return &Client[T]{
+		conn:    conn,
+		timeout: DefaultRequestTimeout,
 	}, nil
 }
 
+// Ping gRPC client call, returns node id of responder
+func (c *Client[T]) Ping(ctx context.Context, key ed25519.PrivateKey) (T, error) {
+	var zero T
+
+	c.conn.Connect()
+
+	timeout, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	in := &pbv1.PingRequest{
+		Sender: c.sender(key),
+	}
+	if err := sign(key, in, in.Sender); err != nil {
+		return zero, err
+	}
+
+	cli := pbv1.NewDHTServiceClient(c.conn)
+	response, err := cli.Ping(timeout, in)
+	if err != nil {
+		return zero, fmt.Errorf("failed to send ping request %w", err)
+	}
+
+	nodeID, err := pingResult(ctx, response, c.observe)
+	if err != nil {
+		return zero, err
+	}
+	c.echo(ctx, nodeID, response.GetEcho())
+
+	return nodeID, nil
+}
+
+// Store gRPC client call
+func (c *Client[T]) Store(ctx context.Context, record *domain.Record[T], key ed25519.PrivateKey) error {
+	c.conn.Connect()
+
+	timeout, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	in := newStoreRequest(c.sender(key), record)
+	if err := sign(key, in, in.Sender); err != nil {
+		return err
+	}
+
+	cli := pbv1.NewDHTServiceClient(c.conn)
+	response, err := cli.Store(timeout, in)
+	if err != nil {
+		return fmt.Errorf("failed to send store request %w", err)
+	}
+
+	c.echo(ctx, c.peer, response.GetEcho())
+
+	return storeResult(response)
+}
+
 // FindNode gRPC client call
-func (c *Client) FindNode(ctx context.Context, nodeID, sender domain.NodeID224) ([]*domain.Contact, error) {
+func (c *Client[T]) FindNode(ctx context.Context, nodeID T, key ed25519.PrivateKey) ([]*domain.Contact[T], error) {
 	c.conn.Connect()
 
-	timeout, cancel := context.WithTimeout(ctx, time.Millisecond*200)
+	timeout, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
+	in := &pbv1.FindNodeRequest{
+		Sender: c.sender(key),
+		NodeId: domain.Bytes(nodeID),
+	}
+	if err := sign(key, in, in.Sender); err != nil {
+		return nil, err
+	}
+
 	cli := pbv1.NewDHTServiceClient(c.conn)
-	response, err := cli.FindNode(timeout, &pbv1.FindNodeRequest{
-		Sender: &pbv1.Sender{
-			SenderId:    sender[:],
-			RequestedAt: timestamppb.Now(),
-		},
-		NodeId: nodeID[:],
-	})
+	response, err := cli.FindNode(timeout, in)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send find node request %v", err)
+		return nil, fmt.Errorf("failed to send find node request %w", err)
 	}
 
-	contactSlice := make([]*domain.Contact, 0)
-	for _, c := range response.ContactList {
-		contactSlice = append(contactSlice, &domain.Contact{
-			IP:   c.Ip,
+	c.echo(ctx, c.peer, response.GetEcho())
+
+	return findNodeResult[T](response)
+}
+
+// FindValue gRPC client call
+func (c *Client[T]) FindValue(ctx context.Context, recordKey T, key ed25519.PrivateKey) (*domain.Record[T], []*domain.Contact[T], error) {
+	c.conn.Connect()
+
+	timeout, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	in := &pbv1.FindValueRequest{
+		Sender: c.sender(key),
+		Key:    domain.Bytes(recordKey),
+	}
+	if err := sign(key, in, in.Sender); err != nil {
+		return nil, nil, err
+	}
+
+	cli := pbv1.NewDHTServiceClient(c.conn)
+	response, err := cli.FindValue(timeout, in)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to send find value request %w", err)
+	}
+
+	c.echo(ctx, c.peer, response.GetEcho())
+
+	return findValueResult[T](response)
+}
+
+// AddProvider gRPC client call
+func (c *Client[T]) AddProvider(ctx context.Context, provider *domain.Provider[T], key ed25519.PrivateKey) error {
+	c.conn.Connect()
+
+	timeout, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	in := &pbv1.AddProviderRequest{
+		Sender:   c.sender(key),
+		Key:      domain.Bytes(provider.Key),
+		Provider: providerToProto(provider),
+	}
+	if err := sign(key, in, in.Sender); err != nil {
+		return err
+	}
+
+	cli := pbv1.NewDHTServiceClient(c.conn)
+	response, err := cli.AddProvider(timeout, in)
+	if err != nil {
+		return fmt.Errorf("failed to send add provider request %w", err)
+	}
+
+	c.echo(ctx, c.peer, response.GetEcho())
+
+	return addProviderResult(response)
+}
+
+// GetProviders gRPC client call
+func (c *Client[T]) GetProviders(ctx context.Context, providerKey T, key ed25519.PrivateKey) ([]*domain.Provider[T], []*domain.Contact[T], error) {
+	c.conn.Connect()
+
+	timeout, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	in := &pbv1.GetProvidersRequest{
+		Sender: c.sender(key),
+		Key:    domain.Bytes(providerKey),
+	}
+	if err := sign(key, in, in.Sender); err != nil {
+		return nil, nil, err
+	}
+
+	cli := pbv1.NewDHTServiceClient(c.conn)
+	response, err := cli.GetProviders(timeout, in)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to send get providers request %w", err)
+	}
+
+	c.echo(ctx, c.peer, response.GetEcho())
+
+	return getProvidersResult(providerKey, response)
+}
+
+// sender unsigned sender advertising the local contact
+func (c *Client[T]) sender(key ed25519.PrivateKey) *pbv1.Sender {
+	if c.self == nil {
+		return newSender[T](key, nil)
+	}
+	return newSender(key, c.self())
+}
+
+// echo pass network coordinate of responder to observer
+func (c *Client[T]) echo(ctx context.Context, nodeID T, echo *pbv1.Echo) {
+
+	var zero T
+	if c.coordinate == nil || nodeID == zero || echo.GetCoordinate() == nil {
+		return
+	}
+
+	c.coordinate(ctx, nodeID, coordinateFromProto(echo.GetCoordinate()))
+}
+
+// Close client connection
+func (c *Client[T]) Close() error {
+	return c.conn.Close()
+}
+
+// newStoreRequest unsigned store request of record
+func newStoreRequest[T domain.NodeID](sender *pbv1.Sender, record *domain.Record[T]) *pbv1.StoreRequest {
+	return &pbv1.StoreRequest{
+		Sender:      sender,
+		Key:         domain.Bytes(record.Key),
+		Value:       record.Value,
+		Publisher:   domain.Bytes(record.Publisher),
+		PublishedAt: timestamppb.New(record.Timestamp),
+		Seq:         record.Seq,
+		PublicKey:   record.PublicKey,
+		Salt:        record.Salt,
+		Signature:   record.Signature,
+	}
+}
+
+// pingResult verify signed ping response, returns node id of responder
+// and passes the observed external ip to observe
+func pingResult[T domain.NodeID](ctx context.Context, response *pbv1.PingResponse, observe func(ctx context.Context, observer T, ip string)) (T, error) {
+
+	var zero T
+
+	nodeID, err := verify[T](response, response.GetResponder())
+	if err != nil {
+		return zero, fmt.Errorf("invalid ping response %w", err)
+	}
+
+	if observe != nil {
+		observe(ctx, nodeID, response.GetObservedIp())
+	}
+
+	return nodeID, nil
+}
+
+// storeResult error of store response status
+func storeResult(response *pbv1.StoreResponse) error {
+	switch response.GetStatus() {
+	case pbv1.StoreStatus_STORE_STATUS_UNSPECIFIED, pbv1.StoreStatus_STORE_STATUS_STORED:
+		return nil
+	case pbv1.StoreStatus_STORE_STATUS_OUTDATED:
+		return domain.ErrRecordOutdated
+	case pbv1.StoreStatus_STORE_STATUS_EXPIRED:
+		return domain.ErrRecordExpired
+	default:
+		return domain.ErrRecordRejected
+	}
+}
+
+// addProviderResult error of add provider response status
+func addProviderResult(response *pbv1.AddProviderResponse) error {
+	switch response.GetStatus() {
+	case pbv1.StoreStatus_STORE_STATUS_STORED:
+		return nil
+	case pbv1.StoreStatus_STORE_STATUS_EXPIRED:
+		return domain.ErrRecordExpired
+	default:
+		return domain.ErrRecordRejected
+	}
+}
+
+// findNodeResult closest contacts of find node response
+func findNodeResult[T domain.NodeID](response *pbv1.FindNodeResponse) ([]*domain.Contact[T], error) {
+
+	contactSlice, err := contactsFromProto[T](response.GetContactList())
+	if err != nil {
+		return nil, fmt.Errorf("%w %v", ErrMalformedResponse, err)
+	}
+
+	return contactSlice, nil
+}
+
+// findValueResult record or closest contacts of find value response
+func findValueResult[T domain.NodeID](response *pbv1.FindValueResponse) (*domain.Record[T], []*domain.Contact[T], error) {
+	switch result := response.GetResult().(type) {
+	case *pbv1.FindValueResponse_Value:
+		record, err := recordFromProto[T](result.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w %v", ErrMalformedResponse, err)
+		}
+		return record, nil, nil
+	case *pbv1.FindValueResponse_ContactList:
+		contactSlice, err := contactsFromProto[T](result.ContactList.GetContacts())
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w %v", ErrMalformedResponse, err)
+		}
+		return nil, contactSlice, nil
+	default:
+		return nil, nil, ErrMissingResult
+	}
+}
+
+// getProvidersResult providers and closest contacts of get providers response
+func getProvidersResult[T domain.NodeID](providerKey T, response *pbv1.GetProvidersResponse) ([]*domain.Provider[T], []*domain.Contact[T], error) {
+
+	providers := make([]*domain.Provider[T], 0, len(response.GetProviders()))
+	for _, p := range response.GetProviders() {
+		provider, err := providerFromProto(providerKey, p)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w %v", ErrMalformedResponse, err)
+		}
+		providers = append(providers, provider)
+	}
+
+	contactSlice, err := contactsFromProto[T](response.GetContactList())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w %v", ErrMalformedResponse, err)
+	}
+
+	return providers, contactSlice, nil
+}
+
+func contactsFromProto[T domain.NodeID](contactList []*pbv1.Contact) ([]*domain.Contact[T], error) {
+
+	contactSlice := make([]*domain.Contact[T], 0, len(contactList))
+	for _, c := range contactList {
+
+		nodeID, err := domain.NodeIDFromBytes[T](c.GetNodeId())
+		if err != nil {
+			return nil, fmt.Errorf("invalid contact %v", err)
+		}
+
+		contactSlice = append(contactSlice, &domain.Contact[T]{
+			IP:   c.GetIp(),
 			Port: int(c.GetPort()),
-			ID:   domain.NodeID224(c.NodeId),
+			ID:   nodeID,
 		})
 	}
 
 	return contactSlice, nil
 }
 
-// Close client connection
-func (c *Client) Close() error {
-	return c.conn.Close()
+func recordFromProto[T domain.NodeID](v *pbv1.Value) (*domain.Record[T], error) {
+
+	key, err := domain.NodeIDFromBytes[T](v.GetKey())
+	if err != nil {
+		return nil, fmt.Errorf("invalid record key %v", err)
+	}
+
+	publisher, err := domain.NodeIDFromBytes[T](v.GetPublisher())
+	if err != nil {
+		return nil, fmt.Errorf("invalid record publisher %v", err)
+	}
+
+	return &domain.Record[T]{
+		Key:       key,
+		Value:     v.GetValue(),
+		Publisher: publisher,
+		Timestamp: v.GetPublishedAt().AsTime(),
+		Seq:       v.GetSeq(),
+		PublicKey: v.GetPublicKey(),
+		Salt:      v.GetSalt(),
+		Signature: v.GetSignature(),
+	}, nil
+}
+
+func recordToProto[T domain.NodeID](record *domain.Record[T]) *pbv1.Value {
+	return &pbv1.Value{
+		Key:         domain.Bytes(record.Key),
+		Value:       record.Value,
+		Publisher:   domain.Bytes(record.Publisher),
+		PublishedAt: timestamppb.New(record.Timestamp),
+		Seq:         record.Seq,
+		PublicKey:   record.PublicKey,
+		Salt:        record.Salt,
+		Signature:   record.Signature,
+	}
+}
+
+func providerFromProto[T domain.NodeID](key T, p *pbv1.Provider) (*domain.Provider[T], error) {
+
+	contactSlice, err := contactsFromProto[T]([]*pbv1.Contact{p.GetContact()})
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider %v", err)
+	}
+
+	return &domain.Provider[T]{
+		Key:       key,
+		Contact:   *contactSlice[0],
+		TTL:       p.GetTtl().AsDuration(),
+		PublicKey: p.GetPublicKey(),
+	}, nil
+}
+
+func providerToProto[T domain.NodeID](provider *domain.Provider[T]) *pbv1.Provider {
+	return &pbv1.Provider{
+		Contact:   contactsToProto([]*domain.Contact[T]{&provider.Contact})[0],
+		Ttl:       durationpb.New(provider.TTL),
+		PublicKey: provider.PublicKey,
+	}
 }
@@ -1,4 +1,294 @@
 package dht
 
-// GRPCServer todo
-type GRPCServer struct{}
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/structx/go-dpkg/domain"
+	pbv1 "github.com/structx/go-dpkg/proto/dht/v1"
+)
+
+// GRPCServer dht service implementation
+type GRPCServer[T domain.NodeID] struct {
+	pbv1.UnimplementedDHTServiceServer
+
+	log *zap.SugaredLogger
+	dht domain.DHT[T]
+	key ed25519.PrivateKey
+}
+
+// interface compliance
+var _ pbv1.DHTServiceServer = (*GRPCServer[domain.NodeID224])(nil)
+
+// NewGRPCServer constructor, handlers reject requests whose
+// sender was not verified by the VerifySender interceptor
+func NewGRPCServer[T domain.NodeID](logger *zap.Logger, dht domain.DHT[T], key ed25519.PrivateKey) *GRPCServer[T] {
+	return &GRPCServer[T]{
+		log: logger.Sugar().Named("DHTServer"),
+		dht: dht,
+		key: key,
+	}
+}
+
+// Ping liveness check, response is signed with node key and
+// reports the address the request was received from
+func (g *GRPCServer[T]) Ping(ctx context.Context, in *pbv1.PingRequest) (*pbv1.PingResponse, error) {
+
+	if err := g.learn(ctx, in.GetSender()); err != nil {
+		return nil, err
+	}
+
+	response := &pbv1.PingResponse{
+		Echo:       g.echo(),
+		Responder:  newSender(g.key, g.dht.Contact()),
+		ObservedIp: peerIP(ctx),
+	}
+	if err := sign(g.key, response, response.Responder); err != nil {
+		g.log.Errorf("failed to sign ping response %v", err)
+		return nil, status.Error(codes.Internal, "failed to sign response")
+	}
+
+	return response, nil
+}
+
+// Store record received from another node
+func (g *GRPCServer[T]) Store(ctx context.Context, in *pbv1.StoreRequest) (*pbv1.StoreResponse, error) {
+
+	key, err := domain.NodeIDFromBytes[T](in.GetKey())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid key %v", err)
+	}
+
+	publisher, err := domain.NodeIDFromBytes[T](in.GetPublisher())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid publisher %v", err)
+	}
+
+	if err := g.learn(ctx, in.GetSender()); err != nil {
+		return nil, err
+	}
+
+	err = g.dht.StoreRecord(ctx, &domain.Record[T]{
+		Key:       key,
+		Value:     in.GetValue(),
+		Publisher: publisher,
+		Timestamp: in.GetPublishedAt().AsTime(),
+		Seq:       in.GetSeq(),
+		PublicKey: in.GetPublicKey(),
+		Salt:      in.GetSalt(),
+		Signature: in.GetSignature(),
+	})
+
+	return &pbv1.StoreResponse{
+		Echo:   g.echo(),
+		Status: g.storeStatus(err),
+	}, nil
+}
+
+// FindValue return record stored under key or closest known contacts
+func (g *GRPCServer[T]) FindValue(ctx context.Context, in *pbv1.FindValueRequest) (*pbv1.FindValueResponse, error) {
+
+	key, err := domain.NodeIDFromBytes[T](in.GetKey())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid key %v", err)
+	}
+
+	if err := g.learn(ctx, in.GetSender()); err != nil {
+		return nil, err
+	}
+
+	record, err := g.dht.FindRecord(ctx, key)
+	if err == nil {
+		return &pbv1.FindValueResponse{
+			Echo:   g.echo(),
+			Result: &pbv1.FindValueResponse_Value{Value: recordToProto(record)},
+		}, nil
+	} else if !errors.Is(err, domain.ErrRecordNotFound) {
+		g.log.Errorf("failed to find record %v", err)
+	}
+
+	contactSlice := g.closest(ctx, key)
+
+	return &pbv1.FindValueResponse{
+		Echo: g.echo(),
+		Result: &pbv1.FindValueResponse_ContactList{
+			ContactList: &pbv1.ContactList{Contacts: contactsToProto(contactSlice)},
+		},
+	}, nil
+}
+
+func (g *GRPCServer[T]) storeStatus(err error) pbv1.StoreStatus {
+	switch {
+	case err == nil:
+		return pbv1.StoreStatus_STORE_STATUS_STORED
+	case errors.Is(err, domain.ErrRecordOutdated):
+		return pbv1.StoreStatus_STORE_STATUS_OUTDATED
+	case errors.Is(err, domain.ErrRecordExpired):
+		return pbv1.StoreStatus_STORE_STATUS_EXPIRED
+	case errors.Is(err, domain.ErrRecordRejected):
+		g.log.Debugf("rejected record %v", err)
+		return pbv1.StoreStatus_STORE_STATUS_REJECTED
+	default:
+		g.log.Errorf("failed to store record %v", err)
+		return pbv1.StoreStatus_STORE_STATUS_REJECTED
+	}
+}
+
+// FindNode return closest known contacts to node id
+func (g *GRPCServer[T]) FindNode(ctx context.Context, in *pbv1.FindNodeRequest) (*pbv1.FindNodeResponse, error) {
+
+	nodeID, err := domain.NodeIDFromBytes[T](in.GetNodeId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid node id %v", err)
+	}
+
+	if err := g.learn(ctx, in.GetSender()); err != nil {
+		return nil, err
+	}
+
+	contactSlice := g.closest(ctx, nodeID)
+
+	return &pbv1.FindNodeResponse{
+		Echo:        g.echo(),
+		ContactList: contactsToProto(contactSlice),
+	}, nil
+}
+
+// AddProvider provider record announced by the sending node
+func (g *GRPCServer[T]) AddProvider(ctx context.Context, in *pbv1.AddProviderRequest) (*pbv1.AddProviderResponse, error) {
+
+	key, err := domain.NodeIDFromBytes[T](in.GetKey())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid key %v", err)
+	}
+
+	if in.GetProvider() == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing provider")
+	}
+
+	provider, err := providerFromProto(key, in.GetProvider())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := g.learn(ctx, in.GetSender()); err != nil {
+		return nil, err
+	}
+
+	// nodes only announce themselves as providers
+	if sender, _ := verifiedSender[T](ctx); provider.Contact.ID != sender {
+		return nil, status.Error(codes.PermissionDenied, "provider is not the sender")
+	}
+
+	err = g.dht.AddProvider(ctx, provider)
+
+	return &pbv1.AddProviderResponse{
+		Echo:   g.echo(),
+		Status: g.storeStatus(err),
+	}, nil
+}
+
+// GetProviders return providers of key with closest known contacts
+func (g *GRPCServer[T]) GetProviders(ctx context.Context, in *pbv1.GetProvidersRequest) (*pbv1.GetProvidersResponse, error) {
+
+	key, err := domain.NodeIDFromBytes[T](in.GetKey())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid key %v", err)
+	}
+
+	if err := g.learn(ctx, in.GetSender()); err != nil {
+		return nil, err
+	}
+
+	providers, err := g.dht.Providers(ctx, key)
+	if err != nil {
+		g.log.Errorf("failed to read providers %v", err)
+	}
+
+	providerList := make([]*pbv1.Provider, 0, len(providers))
+	for _, p := range providers {
+		providerList = append(providerList, providerToProto(p))
+	}
+
+	return &pbv1.GetProvidersResponse{
+		Echo:        g.echo(),
+		Providers:   providerList,
+		ContactList: contactsToProto(g.closest(ctx, key)),
+	}, nil
+}
+
+// learn add verified sender to the routing table, requests served
+// without VerifySender carry no verified sender and are rejected
+func (g *GRPCServer[T]) learn(ctx context.Context, s *pbv1.Sender) error {
+
+	if _, ok := verifiedSender[T](ctx); !ok {
+		return status.Error(codes.Unauthenticated, "sender not verified")
+	}
+
+	if c, ok := senderContact[T](ctx, s); ok {
+		g.dht.AddOrUpdateRoutingTable(ctx, c)
+	}
+
+	return nil
+}
+
+// closest known contacts to key without the requesting node
+func (g *GRPCServer[T]) closest(ctx context.Context, key T) []*domain.Contact[T] {
+
+	k := g.dht.ReplicationFactor()
+
+	// handlers only get here with a verified sender
+	sender, _ := verifiedSender[T](ctx)
+
+	contactSlice := g.dht.ClosestContacts(key, k+1)
+	for i, c := range contactSlice {
+		if c.ID == sender {
+			return append(contactSlice[:i], contactSlice[i+1:]...)
+		}
+	}
+
+	return contactSlice[:min(len(contactSlice), k)]
+}
+
+// echo response echo with the network coordinate of the node
+func (g *GRPCServer[T]) echo() *pbv1.Echo {
+	return &pbv1.Echo{
+		CompletedAt: timestamppb.Now(),
+		Coordinate:  coordinateToProto(g.dht.Coordinate()),
+	}
+}
+
+func coordinateToProto(c *domain.Coordinate) *pbv1.Coordinate {
+	if c == nil {
+		return nil
+	}
+	return &pbv1.Coordinate{Vec: c.Vec, Height: c.Height, Error: c.Error}
+}
+
+func coordinateFromProto(c *pbv1.Coordinate) *domain.Coordinate {
+	if c == nil {
+		return nil
+	}
+	return &domain.Coordinate{Vec: c.GetVec(), Height: c.GetHeight(), Error: c.GetError()}
+}
+
+func contactsToProto[T domain.NodeID](contactSlice []*domain.Contact[T]) []*pbv1.Contact {
+
+	contactList := make([]*pbv1.Contact, 0, len(contactSlice))
+	for _, c := range contactSlice {
+		nodeID := c.ID
+		contactList = append(contactList, &pbv1.Contact{
+			Ip:     c.IP,
+			Port:   int64(c.Port),
+			NodeId: domain.Bytes(nodeID),
+		})
+	}
+
+	return contactList
+}
@@ -0,0 +1,715 @@
+package dht_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/structx/go-dpkg/adapter/logging"
+	"github.com/structx/go-dpkg/adapter/port/dht"
+	"github.com/structx/go-dpkg/adapter/setup"
+	"github.com/structx/go-dpkg/domain"
+	pbv1 "github.com/structx/go-dpkg/proto/dht/v1"
+	kademlia "github.com/structx/go-dpkg/structs/dht"
+	"github.com/structx/go-dpkg/util/decode"
+)
+
+type GRPCServerSuite struct {
+	suite.Suite
+	srv     *grpc.Server
+	node    *kademlia.Node[domain.NodeID224]
+	contact *domain.Contact[domain.NodeID224]
+	cfg     domain.Config
+	key     ed25519.PrivateKey
+}
+
+func (suite *GRPCServerSuite) SetupTest() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	_ = os.Mkdir("./testfiles/log", os.ModePerm)
+
+	cfg := setup.New()
+	assert.NoError(decode.ConfigFromEnv(cfg))
+	suite.cfg = cfg
+
+	logger, err := logging.New(cfg)
+	assert.NoError(err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	assert.NoError(err)
+	p, err := strconv.Atoi(port)
+	assert.NoError(err)
+
+	key, err := dht.LoadIdentity(cfg)
+	assert.NoError(err)
+	suite.key = key
+
+	pub := key.Public().(ed25519.PublicKey)
+	suite.contact = &domain.Contact[domain.NodeID224]{IP: host, Port: p, ID: domain.NodeIDFromPublicKey[domain.NodeID224](pub)}
+
+	suite.node = kademlia.NewNode[domain.NodeID224](ctx, host, p, domain.DefaultReplicationFactor, pub)
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		c := &domain.Contact[domain.NodeID224]{IP: ip, Port: 50051}
+		c.ID = domain.HashKey[domain.NodeID224]([]byte(c.Address()))
+		suite.node.AddOrUpdateRoutingTable(ctx, c)
+	}
+
+	creds, err := dht.NewCredentials[domain.NodeID224](key)
+	assert.NoError(err)
+
+	suite.srv = grpc.NewServer(grpc.Creds(creds), grpc.UnaryInterceptor(dht.VerifySender[domain.NodeID224]))
+	pbv1.RegisterDHTServiceServer(suite.srv, dht.NewGRPCServer[domain.NodeID224](logger, suite.node, key))
+	go func() { _ = suite.srv.Serve(listener) }()
+}
+
+// newPool client pool with a new node key
+func (suite *GRPCServerSuite) newPool() (*dht.Pool[domain.NodeID224], ed25519.PrivateKey) {
+
+	assert := suite.Assert()
+
+	_, key, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+
+	pool, err := dht.NewPool[domain.NodeID224](suite.cfg, key)
+	assert.NoError(err)
+
+	return pool, key
+}
+
+func (suite *GRPCServerSuite) TestLoadIdentity() {
+
+	assert := suite.Assert()
+
+	key, err := dht.LoadIdentity(suite.cfg)
+	assert.NoError(err)
+	assert.Equal(suite.key, key)
+}
+
+func (suite *GRPCServerSuite) TestVerifySender() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	pub, key, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+	senderID := domain.NodeIDFromPublicKey[domain.NodeID224](pub)
+
+	creds, err := dht.NewCredentials[domain.NodeID224](key)
+	assert.NoError(err)
+
+	conn, err := grpc.Dial(suite.contact.Address(), grpc.WithTransportCredentials(creds))
+	assert.NoError(err)
+	defer func() { assert.NoError(conn.Close()) }()
+	cli := pbv1.NewDHTServiceClient(conn)
+
+	target := domain.HashKey[domain.NodeID224]([]byte("target"))
+
+	_, err = cli.FindNode(ctx, &pbv1.FindNodeRequest{NodeId: target[:]})
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+
+	newRequest := func() *pbv1.FindNodeRequest {
+		in := &pbv1.FindNodeRequest{
+			Sender: &pbv1.Sender{
+				SenderId:    senderID[:],
+				RequestedAt: timestamppb.Now(),
+				PublicKey:   pub,
+			},
+			NodeId: target[:],
+		}
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(in)
+		assert.NoError(err)
+		in.Sender.Signature = ed25519.Sign(key, b)
+		return in
+	}
+
+	_, err = cli.FindNode(ctx, newRequest())
+	assert.NoError(err)
+
+	// request modified after signing
+	in := newRequest()
+	in.NodeId = senderID[:]
+	_, err = cli.FindNode(ctx, in)
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+
+	// claimed id not derived from public key
+	in = newRequest()
+	in.Sender.SenderId = suite.contact.ID[:]
+	_, err = cli.FindNode(ctx, in)
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+
+	// request signed by another node than the one that completed the handshake
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+	creds, err = dht.NewCredentials[domain.NodeID224](otherKey)
+	assert.NoError(err)
+
+	other, err := grpc.Dial(suite.contact.Address(), grpc.WithTransportCredentials(creds))
+	assert.NoError(err)
+	defer func() { assert.NoError(other.Close()) }()
+
+	_, err = pbv1.NewDHTServiceClient(other).FindNode(ctx, newRequest())
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func (suite *GRPCServerSuite) TestCredentials() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	pool, poolKey := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	nodeID, err := pool.Ping(ctx, suite.contact)
+	assert.NoError(err)
+	assert.Equal(suite.contact.ID, nodeID)
+
+	// server proves a different node id than the contact dialed
+	impostor := *suite.contact
+	impostor.ID = domain.HashKey[domain.NodeID224]([]byte("impostor"))
+	_, err = pool.Ping(ctx, &impostor)
+	assert.Error(err)
+
+	// unknown node id accepts any node
+	client, err := pool.Get(ctx, suite.contact.Address())
+	assert.NoError(err)
+	_, err = client.Ping(ctx, poolKey)
+	assert.NoError(err)
+
+	// server identity is exposed to callers
+	_, key, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+	creds, err := dht.NewCredentials[domain.NodeID224](key)
+	assert.NoError(err)
+
+	client, err = dht.NewClient[domain.NodeID224](suite.contact.Address(), grpc.WithTransportCredentials(creds))
+	assert.NoError(err)
+	defer func() { assert.NoError(client.Close()) }()
+	_, err = client.Ping(ctx, key)
+	assert.NoError(err)
+
+	conn, err := grpc.Dial(suite.contact.Address(), grpc.WithTransportCredentials(creds.Expect(suite.contact.ID)))
+	assert.NoError(err)
+	defer func() { assert.NoError(conn.Close()) }()
+
+	var p peer.Peer
+	_, err = pbv1.NewDHTServiceClient(conn).FindNode(ctx, &pbv1.FindNodeRequest{}, grpc.Peer(&p))
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+	info, ok := p.AuthInfo.(dht.NodeAuthInfo[domain.NodeID224])
+	assert.True(ok)
+	assert.Equal(suite.contact.ID, info.NodeID)
+
+	// plaintext connections are refused
+	plain, err := grpc.Dial(suite.contact.Address(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(err)
+	defer func() { assert.NoError(plain.Close()) }()
+	_, err = pbv1.NewDHTServiceClient(plain).FindNode(ctx, &pbv1.FindNodeRequest{})
+	assert.Equal(codes.Unavailable, status.Code(err))
+}
+
+func (suite *GRPCServerSuite) TestFindNode() {
+
+	assert := suite.Assert()
+
+	pool, _ := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	contactSlice, err := pool.FindNode(context.TODO(), suite.contact, domain.HashKey[domain.NodeID224]([]byte("target")))
+	assert.NoError(err)
+	assert.Len(contactSlice, 2)
+}
+
+func (suite *GRPCServerSuite) TestFindNodeReplicationFactor() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+
+	// node configured with a bucket size above the default
+	addr := listener.Addr().(*net.TCPAddr)
+	n := kademlia.NewNode[domain.NodeID224](ctx, addr.IP.String(), addr.Port, 5, suite.key.Public().(ed25519.PublicKey))
+	for i := range 8 {
+		c := &domain.Contact[domain.NodeID224]{IP: fmt.Sprintf("10.0.%d.1", i), Port: 50051}
+		c.ID = domain.HashKey[domain.NodeID224]([]byte(c.Address()))
+		n.AddOrUpdateRoutingTable(ctx, c)
+	}
+
+	creds, err := dht.NewCredentials[domain.NodeID224](suite.key)
+	assert.NoError(err)
+
+	srv := grpc.NewServer(grpc.Creds(creds), grpc.UnaryInterceptor(dht.VerifySender[domain.NodeID224]))
+	pbv1.RegisterDHTServiceServer(srv, dht.NewGRPCServer[domain.NodeID224](zap.NewNop(), n, suite.key))
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Stop()
+
+	pool, _ := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	contactSlice, err := pool.FindNode(ctx, n.Contact(), domain.HashKey[domain.NodeID224]([]byte("target")))
+	assert.NoError(err)
+	assert.Len(contactSlice, 5)
+}
+
+func (suite *GRPCServerSuite) TestUnverifiedSender() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+
+	creds, err := dht.NewCredentials[domain.NodeID224](suite.key)
+	assert.NoError(err)
+
+	// server without the VerifySender interceptor
+	srv := grpc.NewServer(grpc.Creds(creds))
+	pbv1.RegisterDHTServiceServer(srv, dht.NewGRPCServer[domain.NodeID224](zap.NewNop(), suite.node, suite.key))
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Stop()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	contact := &domain.Contact[domain.NodeID224]{IP: addr.IP.String(), Port: addr.Port, ID: suite.contact.ID}
+
+	pool, _ := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	_, err = pool.Ping(ctx, contact)
+	assert.ErrorContains(err, "sender not verified")
+
+	_, err = pool.FindNode(ctx, contact, suite.contact.ID)
+	assert.Error(err)
+	assert.Equal(2, suite.node.RoutingTable().Len())
+}
+
+func (suite *GRPCServerSuite) TestStore() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	pool, key := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	publisher := domain.NodeIDFromPublicKey[domain.NodeID224](key.Public().(ed25519.PublicKey))
+
+	record := &domain.Record[domain.NodeID224]{
+		Key:       domain.HashKey[domain.NodeID224]([]byte("key")),
+		Value:     []byte("value"),
+		Publisher: publisher,
+		Timestamp: time.Now(),
+	}
+	assert.NoError(pool.Store(ctx, suite.contact, record))
+
+	stored, err := suite.node.FindRecord(ctx, record.Key)
+	assert.NoError(err)
+	assert.Equal(record.Value, stored.Value)
+	assert.Equal(publisher, stored.Publisher)
+	assert.True(record.Timestamp.Equal(stored.Timestamp))
+
+	outdated := *record
+	outdated.Timestamp = record.Timestamp.Add(-time.Minute)
+	assert.ErrorIs(pool.Store(ctx, suite.contact, &outdated), domain.ErrRecordOutdated)
+}
+
+func (suite *GRPCServerSuite) TestStoreMutable() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	pool, _ := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	_, owner, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+
+	record := &domain.Record[domain.NodeID224]{
+		Value:     []byte("endpoint"),
+		Salt:      []byte("service"),
+		Seq:       1,
+		Timestamp: time.Now(),
+	}
+	domain.SignRecord(owner, record)
+	assert.NoError(pool.Store(ctx, suite.contact, record))
+
+	found, _, err := pool.FindValue(ctx, suite.contact, record.Key)
+	assert.NoError(err)
+	assert.NoError(domain.VerifyRecord(found))
+	assert.Equal(record.Salt, found.Salt)
+	assert.Equal(record.Signature, found.Signature)
+
+	forged := *record
+	forged.Seq = 2
+	forged.Value = []byte("hijacked")
+	assert.ErrorIs(pool.Store(ctx, suite.contact, &forged), domain.ErrRecordRejected)
+}
+
+func (suite *GRPCServerSuite) TestFindValue() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	pool, _ := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	record := &domain.Record[domain.NodeID224]{
+		Key:       domain.HashKey[domain.NodeID224]([]byte("key")),
+		Value:     []byte("value"),
+		Publisher: suite.node.ID,
+		Timestamp: time.Now(),
+	}
+	assert.NoError(suite.node.StoreRecord(ctx, record))
+
+	found, contactSlice, err := pool.FindValue(ctx, suite.contact, record.Key)
+	assert.NoError(err)
+	assert.Empty(contactSlice)
+	assert.Equal(record.Value, found.Value)
+	assert.Equal(record.Publisher, found.Publisher)
+
+	found, contactSlice, err = pool.FindValue(ctx, suite.contact, domain.HashKey[domain.NodeID224]([]byte("missing")))
+	assert.NoError(err)
+	assert.Nil(found)
+	assert.Len(contactSlice, 2)
+}
+
+func (suite *GRPCServerSuite) TestProviders() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	pool, priv := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	pub := priv.Public().(ed25519.PublicKey)
+
+	key := domain.HashKey[domain.NodeID224]([]byte("content"))
+	provider := &domain.Provider[domain.NodeID224]{
+		Key:       key,
+		Contact:   domain.Contact[domain.NodeID224]{IP: "10.0.0.1", Port: 50051, ID: domain.NodeIDFromPublicKey[domain.NodeID224](pub)},
+		TTL:       time.Hour,
+		PublicKey: pub,
+	}
+	assert.NoError(pool.AddProvider(ctx, suite.contact, provider))
+
+	provider.TTL = 0
+	assert.ErrorIs(pool.AddProvider(ctx, suite.contact, provider), domain.ErrRecordExpired)
+
+	// providers other than the sender are rejected
+	other, _, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+	forged := &domain.Provider[domain.NodeID224]{
+		Key:       key,
+		Contact:   domain.Contact[domain.NodeID224]{IP: "10.0.0.2", Port: 50051, ID: domain.NodeIDFromPublicKey[domain.NodeID224](other)},
+		TTL:       time.Hour,
+		PublicKey: other,
+	}
+	assert.ErrorContains(pool.AddProvider(ctx, suite.contact, forged), "provider is not the sender")
+
+	providers, contactSlice, err := pool.GetProviders(ctx, suite.contact, key)
+	assert.NoError(err)
+	assert.Len(contactSlice, 2)
+	assert.Len(providers, 1)
+	assert.Equal(provider.Contact, providers[0].Contact)
+	assert.Equal(pub, providers[0].PublicKey)
+	assert.Greater(providers[0].TTL, time.Minute*59)
+}
+
+func (suite *GRPCServerSuite) TestLookup() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	pool, key := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	n := kademlia.NewNode[domain.NodeID224](ctx, "127.0.0.1", 1, domain.DefaultReplicationFactor, key.Public().(ed25519.PublicKey),
+		kademlia.WithTransport[domain.NodeID224](pool))
+	n.AddOrUpdateRoutingTable(ctx, suite.contact)
+
+	// contacts returned by the server are unreachable
+	contactSlice, err := n.Lookup(ctx, domain.HashKey[domain.NodeID224]([]byte("target")))
+	assert.NoError(err)
+	assert.Len(contactSlice, 1)
+	assert.Equal(suite.contact.ID, contactSlice[0].ID)
+}
+
+func (suite *GRPCServerSuite) TestBootstrap() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	pool, key := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	seeds, err := dht.SeedContacts[domain.NodeID224](suite.cfg)
+	assert.NoError(err)
+	assert.Len(seeds, 2)
+
+	n := kademlia.NewNode[domain.NodeID224](ctx, "127.0.0.1", 1, domain.DefaultReplicationFactor, key.Public().(ed25519.PublicKey),
+		kademlia.WithTransport[domain.NodeID224](pool))
+
+	// configured seeds are not listening
+	assert.ErrorIs(n.Bootstrap(ctx, seeds), kademlia.ErrNoSeeds)
+
+	suite.cfg.GetDistributedHashTable().Seeds = []string{suite.contact.Address(), "127.0.0.1:50053"}
+	seeds, err = dht.SeedContacts[domain.NodeID224](suite.cfg)
+	assert.NoError(err)
+
+	// seed ids are learned from the signed ping response
+	assert.Zero(seeds[0].ID)
+	assert.NoError(n.Bootstrap(ctx, seeds))
+	assert.Equal([]*domain.Contact[domain.NodeID224]{suite.contact}, n.ClosestContacts(suite.contact.ID, 1))
+}
+
+func (suite *GRPCServerSuite) TestAdvertise() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	pool, key := suite.newPool()
+	defer func() { assert.NoError(pool.Close()) }()
+
+	n := kademlia.NewNodeWithDefault[domain.NodeID224](ctx, "0.0.0.0", 50051, key.Public().(ed25519.PublicKey),
+		kademlia.WithTransport[domain.NodeID224](pool),
+		kademlia.WithAdvertiseAddr[domain.NodeID224]("203.0.113.7", 4000))
+	pool.Attach(n)
+
+	// server learns the advertised port at the observed address, the
+	// advertised ip is not trusted
+	contactSlice, err := pool.FindNode(ctx, suite.contact, n.ID)
+	assert.NoError(err)
+	assert.Len(contactSlice, 2)
+	assert.Equal([]*domain.Contact[domain.NodeID224]{{IP: "127.0.0.1", Port: 4000, ID: n.ID}},
+		suite.node.ClosestContacts(n.ID, 1))
+
+	// requester is not returned to itself
+	contactSlice, err = pool.FindNode(ctx, suite.contact, n.ID)
+	assert.NoError(err)
+	assert.Len(contactSlice, 2)
+	assert.NotContains(contactIDs(contactSlice), n.ID)
+
+	unknown, key := suite.newPool()
+	defer func() { assert.NoError(unknown.Close()) }()
+
+	n = kademlia.NewNodeWithDefault[domain.NodeID224](ctx, "0.0.0.0", 50051, key.Public().(ed25519.PublicKey),
+		kademlia.WithTransport[domain.NodeID224](unknown))
+	unknown.Attach(n)
+
+	// unknown ip is the address the request was received from
+	nodeID, err := unknown.Ping(ctx, suite.contact)
+	assert.NoError(err)
+	assert.Equal(suite.contact.ID, nodeID)
+	// the bucket of the contact may already be full
+	assert.Contains(learnedContacts(suite.node), domain.Contact[domain.NodeID224]{IP: "127.0.0.1", Port: 50051, ID: n.ID})
+
+	// a single peer is not enough to change the advertised address
+	assert.Equal("0.0.0.0", n.Contact().IP)
+}
+
+func (suite *GRPCServerSuite) TestAdvertiseAddr() {
+
+	assert := suite.Assert()
+
+	dcfg := suite.cfg.GetDistributedHashTable()
+	advertise := func(addr string) *string { return &addr }
+
+	listen, err := dht.ListenAddr(suite.cfg)
+	assert.NoError(err)
+	assert.Equal("127.0.0.1:50052", listen)
+
+	cases := []struct {
+		bind      string
+		advertise *string
+		ip        string
+		port      int
+		err       bool
+	}{
+		{bind: "127.0.0.1", ip: "127.0.0.1", port: 50052},
+		{bind: "0.0.0.0", ip: "", port: 50052},
+		{bind: "0.0.0.0", advertise: advertise("203.0.113.7"), ip: "203.0.113.7", port: 50052},
+		{bind: "0.0.0.0", advertise: advertise("203.0.113.7:4000"), ip: "203.0.113.7", port: 4000},
+		{bind: "::", advertise: advertise("[2001:db8::1]:4000"), ip: "2001:db8::1", port: 4000},
+		{bind: "0.0.0.0", advertise: advertise("0.0.0.0:4000"), err: true},
+		{bind: "0.0.0.0", advertise: advertise("203.0.113.7:port"), err: true},
+	}
+	for _, c := range cases {
+		dcfg.BindAddr, dcfg.AdvertiseAddr = c.bind, c.advertise
+
+		ip, port, err := dht.AdvertiseAddr(suite.cfg)
+		if c.err {
+			assert.Error(err)
+			continue
+		}
+		assert.NoError(err)
+		assert.Equal(c.ip, ip)
+		assert.Equal(c.port, port)
+	}
+}
+
+func (suite *GRPCServerSuite) TestMetrics() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	reg := prometheus.NewRegistry()
+	m, err := dht.NewMetrics[domain.NodeID224](reg)
+	assert.NoError(err)
+	m.Attach(suite.node)
+
+	_, err = dht.NewMetrics[domain.NodeID224](reg)
+	assert.Error(err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+
+	logger, err := logging.New(suite.cfg)
+	assert.NoError(err)
+
+	creds, err := dht.NewCredentials[domain.NodeID224](suite.key)
+	assert.NoError(err)
+
+	srv := grpc.NewServer(grpc.Creds(creds), grpc.ChainUnaryInterceptor(m.UnaryServerInterceptor, dht.VerifySender[domain.NodeID224]))
+	pbv1.RegisterDHTServiceServer(srv, dht.NewGRPCServer[domain.NodeID224](logger, suite.node, suite.key))
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Stop()
+
+	contact := *suite.contact
+	contact.Port = listener.Addr().(*net.TCPAddr).Port
+
+	_, key, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+
+	pool, err := dht.NewPool[domain.NodeID224](suite.cfg, key, grpc.WithChainUnaryInterceptor(m.UnaryClientInterceptor))
+	assert.NoError(err)
+	defer func() { assert.NoError(pool.Close()) }()
+
+	n := kademlia.NewNodeWithDefault[domain.NodeID224](ctx, "127.0.0.1", 1, key.Public().(ed25519.PublicKey),
+		kademlia.WithTransport[domain.NodeID224](pool),
+		kademlia.WithMetrics[domain.NodeID224](m))
+	n.AddOrUpdateRoutingTable(ctx, &contact)
+
+	_, err = n.Lookup(ctx, domain.HashKey[domain.NodeID224]([]byte("target")))
+	assert.NoError(err)
+
+	// unreachable contact is evicted after failing repeatedly
+	unreachable := &domain.Contact[domain.NodeID224]{IP: "127.0.0.1", Port: 1, ID: domain.HashKey[domain.NodeID224]([]byte("unreachable"))}
+	n.AddOrUpdateRoutingTable(ctx, unreachable)
+	for i := 0; i < 3; i++ {
+		_, _ = n.Lookup(ctx, unreachable.ID)
+	}
+
+	creds, err = dht.NewCredentials[domain.NodeID224](key)
+	assert.NoError(err)
+	conn, err := grpc.Dial(contact.Address(), grpc.WithTransportCredentials(creds))
+	assert.NoError(err)
+	defer func() { assert.NoError(conn.Close()) }()
+	_, err = pbv1.NewDHTServiceClient(conn).FindNode(ctx, &pbv1.FindNodeRequest{})
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+
+	// stored records are reported by the node on every change
+	for _, v := range []string{"value", "longer value"} {
+		assert.NoError(n.StoreRecord(ctx, &domain.Record[domain.NodeID224]{
+			Key:       domain.HashKey[domain.NodeID224]([]byte("key")),
+			Value:     []byte(v),
+			Publisher: n.ID,
+			Timestamp: time.Now(),
+		}))
+	}
+
+	families, err := reg.Gather()
+	assert.NoError(err)
+
+	value := func(name string, labels ...string) float64 {
+		var sum float64
+		for _, f := range families {
+			if f.GetName() != name {
+				continue
+			}
+		metrics:
+			for _, metric := range f.GetMetric() {
+				for i := 0; i < len(labels); i += 2 {
+					found := false
+					for _, l := range metric.GetLabel() {
+						found = found || (l.GetName() == labels[i] && l.GetValue() == labels[i+1])
+					}
+					if !found {
+						continue metrics
+					}
+				}
+				switch {
+				case metric.GetCounter() != nil:
+					sum += metric.GetCounter().GetValue()
+				case metric.GetGauge() != nil:
+					sum += metric.GetGauge().GetValue()
+				case metric.GetHistogram() != nil:
+					sum += float64(metric.GetHistogram().GetSampleCount())
+				}
+			}
+		}
+		return sum
+	}
+
+	assert.Positive(value("dht_rpc_outbound_total", "method", "FindNode", "code", "OK"))
+	assert.Positive(value("dht_rpc_inbound_total", "method", "FindNode", "code", "OK"))
+	assert.Equal(1.0, value("dht_rpc_inbound_total", "method", "FindNode", "code", "Unauthenticated"))
+	assert.Equal(value("dht_rpc_outbound_total", "method", "FindNode"), value("dht_rpc_outbound_duration_seconds", "method", "FindNode"))
+	assert.Positive(value("dht_lookups_total", "status", "ok"))
+	assert.Equal(value("dht_lookups_total", "status", "ok"), value("dht_lookup_hops"))
+	assert.Positive(value("dht_routing_table_evictions_total", "reason", kademlia.EvictedUnresponsive))
+	assert.Positive(value("dht_routing_table_contacts"))
+	assert.Equal(1.0, value("dht_stored_records"))
+	assert.Equal(12.0, value("dht_stored_record_bytes"))
+
+	assert.NoError(suite.node.Republish(ctx))
+	assert.Equal(1, testutil.CollectAndCount(m, "dht_republish_runs_total"))
+}
+
+func contactIDs(contactSlice []*domain.Contact[domain.NodeID224]) []domain.NodeID224 {
+	ids := make([]domain.NodeID224, 0, len(contactSlice))
+	for _, c := range contactSlice {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+// learnedContacts contacts of node in buckets and replacement caches
+func learnedContacts(n *kademlia.Node[domain.NodeID224]) []domain.Contact[domain.NodeID224] {
+	var contactSlice []domain.Contact[domain.NodeID224]
+	rt := n.RoutingTable()
+	for i := range domain.Bits[domain.NodeID224]() {
+		for _, c := range append(rt.Bucket(i), rt.Replacements(i)...) {
+			contactSlice = append(contactSlice, *c)
+		}
+	}
+	return contactSlice
+}
+
+func (suite *GRPCServerSuite) TearDownTest() {
+	suite.srv.Stop()
+}
+
+func TestGRPCServerSuite(t *testing.T) {
+	suite.Run(t, new(GRPCServerSuite))
+}
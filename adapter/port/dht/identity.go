@@ -0,0 +1,236 @@
+package dht
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/structx/go-dpkg/domain"
+	pbv1 "github.com/structx/go-dpkg/proto/dht/v1"
+)
+
+const (
+	// keyFile name of node key in data dir
+	keyFile = "node.key"
+	// MaxClockSkew maximum age of a signed request
+	MaxClockSkew = time.Minute
+)
+
+var (
+	// ErrMissingSender message is not signed
+	ErrMissingSender = errors.New("missing sender")
+	// ErrInvalidSignature signature does not match public key
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrIdentityMismatch claimed node id is not derived from public key
+	ErrIdentityMismatch = errors.New("node id does not match public key")
+)
+
+// LoadIdentity read node key from data dir, a new key
+// is generated and persisted if none exists yet
+func LoadIdentity(cfg domain.Config) (ed25519.PrivateKey, error) {
+
+	dcfg := cfg.GetDistributedHashTable()
+	if dcfg == nil {
+		return nil, errors.New("missing distributed hash table configuration")
+	}
+
+	if dcfg.DataDir == "" {
+		return nil, errors.New("missing distributed hash table data dir")
+	}
+
+	path := filepath.Join(dcfg.DataDir, keyFile)
+
+	b, err := os.ReadFile(path)
+	if err == nil {
+		return parseKey(b)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read node key %v", err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate node key %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal node key %v", err)
+	}
+
+	err = os.MkdirAll(dcfg.DataDir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data dir %v", err)
+	}
+
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write node key %v", err)
+	}
+
+	return key, nil
+}
+
+func parseKey(b []byte) (ed25519.PrivateKey, error) {
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("node key is not pem encoded")
+	}
+
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse node key %v", err)
+	}
+
+	key, ok := k.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("node key is not an ed25519 key")
+	}
+
+	return key, nil
+}
+
+// verifiedKey context key of the signature verification of a request
+type verifiedKey struct{}
+
+// verification result of verifying the signature of req
+type verification[T domain.NodeID] struct {
+	req    any
+	nodeID T
+	err    error
+}
+
+// signedRequest request containing a signed sender
+type signedRequest interface {
+	proto.Message
+	GetSender() *pbv1.Sender
+}
+
+// VerifySender unary interceptor rejecting dht requests
+// without a valid signature from the claimed node id,
+// only verified senders are added to the routing table.
+// on channels secured with NodeCredentials the sender
+// must be the node that completed the handshake
+func VerifySender[T domain.NodeID](ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+
+	m, ok := req.(signedRequest)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	nodeID, err := verifyRequest[T](ctx, m)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "%v", err)
+	}
+
+	if age := time.Since(m.GetSender().GetRequestedAt().AsTime()); age > MaxClockSkew || age < -MaxClockSkew {
+		return nil, status.Error(codes.Unauthenticated, "request timestamp outside allowed clock skew")
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(NodeAuthInfo[T]); ok && info.NodeID != nodeID {
+			return nil, status.Error(codes.Unauthenticated, "sender does not match channel identity")
+		}
+	}
+
+	return handler(context.WithValue(ctx, senderKey{}, nodeID), req)
+}
+
+// verifyRequest verify signature of request, the result of an
+// earlier interceptor verifying the same request is reused
+func verifyRequest[T domain.NodeID](ctx context.Context, m signedRequest) (T, error) {
+
+	if v, ok := ctx.Value(verifiedKey{}).(verification[T]); ok && v.req == any(m) {
+		return v.nodeID, v.err
+	}
+
+	return verify[T](m, m.GetSender())
+}
+
+// newSender unsigned sender of node key advertising the address
+// of self, nil self advertises no address and a wildcard ip is left
+// to the receiver
+func newSender[T domain.NodeID](key ed25519.PrivateKey, self *domain.Contact[T]) *pbv1.Sender {
+
+	pub := key.Public().(ed25519.PublicKey)
+	nodeID := domain.NodeIDFromPublicKey[T](pub)
+
+	s := &pbv1.Sender{
+		SenderId:    domain.Bytes(nodeID),
+		RequestedAt: timestamppb.Now(),
+		PublicKey:   pub,
+	}
+	if self != nil {
+		if !unspecified(self.IP) {
+			s.AdvertiseIp = self.IP
+		}
+		s.AdvertisePort = int64(self.Port)
+	}
+
+	return s
+}
+
+// sign message containing sender
+func sign(key ed25519.PrivateKey, m proto.Message, s *pbv1.Sender) error {
+
+	s.Signature = nil
+
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message %v", err)
+	}
+
+	s.Signature = ed25519.Sign(key, b)
+
+	return nil
+}
+
+// verify signature of message containing sender,
+// returns node id derived from public key
+func verify[T domain.NodeID](m proto.Message, s *pbv1.Sender) (T, error) {
+
+	var zero T
+
+	if s == nil {
+		return zero, ErrMissingSender
+	}
+
+	pub := ed25519.PublicKey(s.GetPublicKey())
+	if len(pub) != ed25519.PublicKeySize {
+		return zero, ErrInvalidSignature
+	}
+
+	nodeID := domain.NodeIDFromPublicKey[T](pub)
+	if !bytes.Equal(domain.Bytes(nodeID), s.GetSenderId()) {
+		return zero, ErrIdentityMismatch
+	}
+
+	signature := s.Signature
+	s.Signature = nil
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	s.Signature = signature
+	if err != nil {
+		return zero, fmt.Errorf("failed to marshal message %v", err)
+	}
+
+	if !ed25519.Verify(pub, b, signature) {
+		return zero, ErrInvalidSignature
+	}
+
+	return nodeID, nil
+}
@@ -0,0 +1,246 @@
+package dht
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+// metricsNamespace prefix of every dht metric
+const metricsNamespace = "dht"
+
+// Metrics prometheus collectors of the dht subsystem
+//
+// rpcs are counted by the grpc interceptors, lookups, evictions,
+// republishing and stored records are reported by nodes created
+// with kademlia.WithMetrics. routing table gauges are read from
+// the attached node on every scrape
+type Metrics[T domain.NodeID] struct {
+	inbound          *prometheus.CounterVec
+	inboundDuration  *prometheus.HistogramVec
+	outbound         *prometheus.CounterVec
+	outboundDuration *prometheus.HistogramVec
+
+	lookups        *prometheus.CounterVec
+	lookupDuration prometheus.Histogram
+	lookupHops     prometheus.Histogram
+
+	evictions   *prometheus.CounterVec
+	republishes prometheus.Counter
+	republished *prometheus.CounterVec
+
+	records     prometheus.Gauge
+	recordBytes prometheus.Gauge
+
+	bucketContacts *prometheus.Desc
+
+	mtx       sync.RWMutex
+	inspector domain.Inspector[T]
+}
+
+// interface compliance
+var _ domain.DHTMetrics = (*Metrics[domain.NodeID224])(nil)
+var _ prometheus.Collector = (*Metrics[domain.NodeID224])(nil)
+
+// NewMetrics constructor registers collectors with reg,
+// use prometheus.DefaultRegisterer to export on /metrics
+func NewMetrics[T domain.NodeID](reg prometheus.Registerer) (*Metrics[T], error) {
+
+	m := &Metrics[T]{
+		inbound: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "rpc_inbound_total",
+			Help:      "Inbound dht rpcs by method and status code.",
+		}, []string{"method", "code"}),
+		inboundDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "rpc_inbound_duration_seconds",
+			Help:      "Time to handle inbound dht rpcs by method.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+		}, []string{"method"}),
+		outbound: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "rpc_outbound_total",
+			Help:      "Outbound dht rpcs by method and status code.",
+		}, []string{"method", "code"}),
+		outboundDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "rpc_outbound_duration_seconds",
+			Help:      "Round trip time of outbound dht rpcs by method.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 3, 9),
+		}, []string{"method"}),
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "lookups_total",
+			Help:      "Iterative lookups by status.",
+		}, []string{"status"}),
+		lookupDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "lookup_duration_seconds",
+			Help:      "Duration of iterative lookups.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 3, 9),
+		}),
+		lookupHops: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "lookup_hops",
+			Help:      "Responses on the path to the closest contact of successful lookups.",
+			Buckets:   prometheus.LinearBuckets(1, 1, 10),
+		}),
+		evictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "routing_table_evictions_total",
+			Help:      "Contacts removed from the routing table by reason.",
+		}, []string{"reason"}),
+		republishes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "republish_runs_total",
+			Help:      "Runs republishing stored records.",
+		}),
+		republished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "republished_records_total",
+			Help:      "Records republished by status.",
+		}, []string{"status"}),
+		records: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "stored_records",
+			Help:      "Records in local store.",
+		}),
+		recordBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "stored_record_bytes",
+			Help:      "Bytes of record values in local store.",
+		}),
+		bucketContacts: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "routing_table", "contacts"),
+			"Contacts in routing table by bucket index.",
+			[]string{"bucket"}, nil,
+		),
+	}
+
+	if err := reg.Register(m); err != nil {
+		return nil, fmt.Errorf("failed to register dht metrics %v", err)
+	}
+
+	return m, nil
+}
+
+// Attach node whose routing table is reported
+func (m *Metrics[T]) Attach(inspector domain.Inspector[T]) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.inspector = inspector
+}
+
+// ObserveLookup completed lookup with hops to the closest contact
+func (m *Metrics[T]) ObserveLookup(duration time.Duration, hops int, err error) {
+
+	if err != nil {
+		m.lookups.WithLabelValues("error").Inc()
+		return
+	}
+
+	m.lookups.WithLabelValues("ok").Inc()
+	m.lookupDuration.Observe(duration.Seconds())
+	m.lookupHops.Observe(float64(hops))
+}
+
+// ObserveEviction contact removed from the routing table
+func (m *Metrics[T]) ObserveEviction(reason string) {
+	m.evictions.WithLabelValues(reason).Inc()
+}
+
+// ObserveRepublish records republished and failed during one run
+func (m *Metrics[T]) ObserveRepublish(published, failed int) {
+	m.republishes.Inc()
+	m.republished.WithLabelValues("ok").Add(float64(published))
+	m.republished.WithLabelValues("error").Add(float64(failed))
+}
+
+// ObserveRecords number of stored records and bytes of their values
+func (m *Metrics[T]) ObserveRecords(count, size int) {
+	m.records.Set(float64(count))
+	m.recordBytes.Set(float64(size))
+}
+
+// UnaryServerInterceptor count inbound rpcs, chain before
+// VerifySender so rejected requests are counted as well
+func (m *Metrics[T]) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	method := path.Base(info.FullMethod)
+	m.inbound.WithLabelValues(method, status.Code(err).String()).Inc()
+	m.inboundDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
+
+// UnaryClientInterceptor count outbound rpcs, pass
+// to NewPool with grpc.WithChainUnaryInterceptor
+func (m *Metrics[T]) UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	name := path.Base(method)
+	m.outbound.WithLabelValues(name, status.Code(err).String()).Inc()
+	m.outboundDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// Describe prometheus collector
+func (m *Metrics[T]) Describe(ch chan<- *prometheus.Desc) {
+	m.inbound.Describe(ch)
+	m.inboundDuration.Describe(ch)
+	m.outbound.Describe(ch)
+	m.outboundDuration.Describe(ch)
+	m.lookups.Describe(ch)
+	m.lookupDuration.Describe(ch)
+	m.lookupHops.Describe(ch)
+	m.evictions.Describe(ch)
+	m.republishes.Describe(ch)
+	m.republished.Describe(ch)
+	m.records.Describe(ch)
+	m.recordBytes.Describe(ch)
+	ch <- m.bucketContacts
+}
+
+// Collect prometheus collector
+func (m *Metrics[T]) Collect(ch chan<- prometheus.Metric) {
+	m.inbound.Collect(ch)
+	m.inboundDuration.Collect(ch)
+	m.outbound.Collect(ch)
+	m.outboundDuration.Collect(ch)
+	m.lookups.Collect(ch)
+	m.lookupDuration.Collect(ch)
+	m.lookupHops.Collect(ch)
+	m.evictions.Collect(ch)
+	m.republishes.Collect(ch)
+	m.republished.Collect(ch)
+	m.records.Collect(ch)
+	m.recordBytes.Collect(ch)
+
+	m.mtx.RLock()
+	inspector := m.inspector
+	m.mtx.RUnlock()
+
+	if inspector == nil {
+		return
+	}
+
+	for _, b := range inspector.Buckets() {
+		ch <- prometheus.MustNewConstMetric(m.bucketContacts, prometheus.GaugeValue, float64(len(b.Contacts)), strconv.Itoa(b.Index))
+	}
+}
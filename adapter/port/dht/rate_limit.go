@@ -0,0 +1,213 @@
+package dht
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+// maxBuckets token buckets per key kind before idle ones are dropped
+const maxBuckets = 8192
+
+// tokenBucket refilled with rate tokens per second up to burst
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limit rate and burst of one kind of key, zero rate disables it
+type limit struct {
+	rate  float64
+	burst float64
+}
+
+// RateLimiter token buckets limiting inbound rpcs per ip and sender id
+//
+// the sender of a request is the node proven by the channel handshake,
+// requests without channel identity, e.g. received over udp, are
+// attributed to their signed sender, requests whose signature does not
+// verify only count against the limit of their ip. banned senders are
+// rejected when a reputation is given
+type RateLimiter[T domain.NodeID] struct {
+	reputation *Reputation[T]
+
+	sender limit
+	ip     limit
+
+	mtx     sync.Mutex
+	senders map[T]*tokenBucket
+	ips     map[string]*tokenBucket
+}
+
+// NewRateLimiter constructor, reputation may be nil
+func NewRateLimiter[T domain.NodeID](cfg domain.Config, reputation *Reputation[T]) (*RateLimiter[T], error) {
+
+	dcfg := cfg.GetDistributedHashTable()
+	if dcfg == nil {
+		return nil, errors.New("missing distributed hash table configuration")
+	}
+
+	l := &RateLimiter[T]{
+		reputation: reputation,
+		senders:    make(map[T]*tokenBucket),
+		ips:        make(map[string]*tokenBucket),
+	}
+
+	if rcfg := dcfg.RateLimit; rcfg != nil {
+
+		var err error
+
+		l.sender, err = newLimit(rcfg.SenderRate, rcfg.SenderBurst)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sender rate limit %v", err)
+		}
+
+		l.ip, err = newLimit(rcfg.IPRate, rcfg.IPBurst)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ip rate limit %v", err)
+		}
+	}
+
+	return l, nil
+}
+
+// newLimit rate per second and burst, burst defaults to one second of requests
+func newLimit(rate float64, burst int) (limit, error) {
+
+	if rate < 0 || burst < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return limit{}, errors.New("rate and burst must not be negative")
+	}
+
+	if rate > 0 && burst == 0 {
+		return limit{rate: rate, burst: math.Max(1, math.Ceil(rate))}, nil
+	}
+
+	return limit{rate: rate, burst: float64(burst)}, nil
+}
+
+// UnaryServerInterceptor reject requests exceeding the limits of their
+// ip or sender and requests of banned senders, chain before VerifySender.
+// the signature of a request is verified once and the result passed on
+// to VerifySender. invalid signatures count against the channel identity,
+// without one the claimed node id is not charged since anyone can claim it
+func (l *RateLimiter[T]) UnaryServerInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+
+	if ip := peerIP(ctx); ip != "" && !allow(l, l.ips, ip, l.ip) {
+		return nil, status.Error(codes.ResourceExhausted, "rate limit of ip exceeded")
+	}
+
+	nodeID, channel := channelIdentity[T](ctx)
+
+	m, signed := req.(signedRequest)
+	var verr error
+	if signed {
+		var sender T
+		sender, verr = verify[T](m, m.GetSender())
+		ctx = context.WithValue(ctx, verifiedKey{}, verification[T]{req: req, nodeID: sender, err: verr})
+
+		if !channel {
+			nodeID = sender
+		}
+	}
+
+	var zero T
+	if nodeID != zero {
+		if l.reputation != nil && l.reputation.Banned(nodeID) {
+			return nil, status.Error(codes.PermissionDenied, ErrPeerBanned.Error())
+		}
+		if !allow(l, l.senders, nodeID, l.sender) {
+			return nil, status.Error(codes.ResourceExhausted, "rate limit of sender exceeded")
+		}
+	}
+
+	if verr != nil && channel && l.reputation != nil {
+		l.reputation.Record(nodeID, EventInvalidSignature)
+	}
+
+	return handler(ctx, req)
+}
+
+// allow take a token from the bucket of key, buckets are created full
+func allow[T domain.NodeID, K comparable](l *RateLimiter[T], buckets map[K]*tokenBucket, key K, lim limit) bool {
+
+	if lim.rate <= 0 {
+		return true
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := time.Now()
+
+	b, ok := buckets[key]
+	if !ok {
+		if len(buckets) >= maxBuckets {
+			prune(buckets, now, lim)
+		}
+		b = &tokenBucket{tokens: lim.burst, last: now}
+		buckets[key] = b
+	}
+
+	b.tokens = math.Min(lim.burst, b.tokens+now.Sub(b.last).Seconds()*lim.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// prune drop buckets that have refilled completely, they are
+// equivalent to new ones. the least recently used bucket is
+// dropped if all of them are in use
+func prune[K comparable](buckets map[K]*tokenBucket, now time.Time, lim limit) {
+
+	var (
+		oldest K
+		last   time.Time
+	)
+
+	for key, b := range buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*lim.rate >= lim.burst {
+			delete(buckets, key)
+			continue
+		}
+		if last.IsZero() || b.last.Before(last) {
+			oldest, last = key, b.last
+		}
+	}
+
+	if len(buckets) >= maxBuckets {
+		delete(buckets, oldest)
+	}
+}
+
+// channelIdentity node id proven by the channel handshake
+func channelIdentity[T domain.NodeID](ctx context.Context) (T, bool) {
+
+	var zero T
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return zero, false
+	}
+
+	info, ok := p.AuthInfo.(NodeAuthInfo[T])
+	if !ok || info.NodeID == zero {
+		return zero, false
+	}
+
+	return info.NodeID, true
+}
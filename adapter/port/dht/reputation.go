@@ -0,0 +1,368 @@
+package dht
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+const (
+	// DefaultBanThreshold score at which a peer is banned
+	DefaultBanThreshold = -20.0
+	// DefaultBanDuration time a banned peer is rejected
+	DefaultBanDuration = time.Minute * 10
+	// maxScore highest score of well behaved peers, bounds
+	// how much good behaviour offsets later offenses
+	maxScore = 10.0
+	// maxReputations peers whose score is remembered
+	maxReputations = 4096
+)
+
+// ErrPeerBanned peer is temporarily banned for misbehaving
+var ErrPeerBanned = errors.New("peer is banned")
+
+// Event outcome of an exchange with a peer
+type Event int
+
+const (
+	// EventResponse peer responded to a request
+	EventResponse Event = iota
+	// EventTimeout peer did not respond in time or was unreachable
+	EventTimeout
+	// EventMalformed peer sent an invalid or unexpected message
+	EventMalformed
+	// EventInvalidSignature peer sent a message or record with an invalid signature
+	EventInvalidSignature
+)
+
+// eventScores change of score per event
+var eventScores = map[Event]float64{
+	EventResponse:         1,
+	EventTimeout:          -2,
+	EventMalformed:        -5,
+	EventInvalidSignature: -20,
+}
+
+// String name of event
+func (e Event) String() string {
+	switch e {
+	case EventResponse:
+		return "response"
+	case EventTimeout:
+		return "timeout"
+	case EventMalformed:
+		return "malformed"
+	case EventInvalidSignature:
+		return "invalid signature"
+	default:
+		return "unknown"
+	}
+}
+
+type standing struct {
+	score       float64
+	bannedUntil time.Time
+}
+
+// Reputation scores of peers by node id
+//
+// responses raise the score up to a small maximum, timeouts, malformed
+// responses and invalid signatures lower it. peers whose score drops to
+// the ban threshold are banned for the ban duration and start over with
+// a neutral score afterwards
+type Reputation[T domain.NodeID] struct {
+	log *zap.SugaredLogger
+
+	mtx   sync.Mutex
+	peers map[T]*standing
+	onBan func(nodeID T)
+
+	threshold   float64
+	banDuration time.Duration
+}
+
+// NewReputation constructor
+func NewReputation[T domain.NodeID](logger *zap.Logger, cfg domain.Config) (*Reputation[T], error) {
+
+	dcfg := cfg.GetDistributedHashTable()
+	if dcfg == nil {
+		return nil, errors.New("missing distributed hash table configuration")
+	}
+
+	r := &Reputation[T]{
+		log:         logger.Sugar().Named("DHTReputation"),
+		peers:       make(map[T]*standing),
+		threshold:   DefaultBanThreshold,
+		banDuration: DefaultBanDuration,
+	}
+
+	if rcfg := dcfg.Reputation; rcfg != nil {
+		if rcfg.BanThreshold > 0 {
+			return nil, fmt.Errorf("invalid ban threshold %v must be negative", rcfg.BanThreshold)
+		} else if rcfg.BanThreshold < 0 {
+			r.threshold = rcfg.BanThreshold
+		}
+		if rcfg.BanDuration > 0 {
+			r.banDuration = time.Duration(rcfg.BanDuration) * time.Second
+		}
+	}
+
+	return r, nil
+}
+
+// OnBan call fn with every peer that gets banned, e.g. to
+// remove it from the routing table of the node
+func (r *Reputation[T]) OnBan(fn func(nodeID T)) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.onBan = fn
+}
+
+// Record event of peer, events of banned peers and
+// of contacts whose node id is not known yet are ignored
+func (r *Reputation[T]) Record(nodeID T, e Event) {
+
+	var zero T
+	if nodeID == zero {
+		return
+	}
+
+	r.mtx.Lock()
+	banned := r.record(nodeID, e)
+	onBan := r.onBan
+	r.mtx.Unlock()
+
+	if banned && onBan != nil {
+		onBan(nodeID)
+	}
+}
+
+// record event of peer, true if the peer got banned
+func (r *Reputation[T]) record(nodeID T, e Event) bool {
+
+	now := time.Now()
+
+	s, ok := r.peers[nodeID]
+	if !ok {
+		if len(r.peers) >= maxReputations {
+			r.forget(now)
+		}
+		s = &standing{}
+		r.peers[nodeID] = s
+	}
+
+	if now.Before(s.bannedUntil) {
+		return false
+	}
+
+	s.score = min(s.score+eventScores[e], maxScore)
+	if s.score > r.threshold {
+		return false
+	}
+
+	r.log.Warnf("ban peer %x for %v after %v", domain.Bytes(nodeID), r.banDuration, e)
+	s.score = 0
+	s.bannedUntil = now.Add(r.banDuration)
+
+	return true
+}
+
+// Observe record the outcome of a request to peer, errors
+// not caused by the peer leave its score unchanged
+func (r *Reputation[T]) Observe(nodeID T, err error) {
+	if e, ok := classify(err); ok {
+		r.Record(nodeID, e)
+	}
+}
+
+// Banned peer is currently banned
+func (r *Reputation[T]) Banned(nodeID T) bool {
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	s, ok := r.peers[nodeID]
+	return ok && time.Now().Before(s.bannedUntil)
+}
+
+// Score current score of peer, zero if unknown
+func (r *Reputation[T]) Score(nodeID T) float64 {
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if s, ok := r.peers[nodeID]; ok {
+		return s.score
+	}
+
+	return 0
+}
+
+// Transport wrap transport so requests to banned peers fail with
+// ErrPeerBanned and the outcome of every request is recorded
+func (r *Reputation[T]) Transport(t domain.Transport[T]) domain.Transport[T] {
+	return &reputationTransport[T]{Transport: t, reputation: r}
+}
+
+// forget peers with neutral standing, an arbitrary peer
+// that is not banned if all of them have been scored
+func (r *Reputation[T]) forget(now time.Time) {
+
+	var fallback *T
+	for nodeID, s := range r.peers {
+		if now.Before(s.bannedUntil) {
+			continue
+		}
+		if s.score == 0 {
+			delete(r.peers, nodeID)
+			continue
+		}
+		if fallback == nil {
+			id := nodeID
+			fallback = &id
+		}
+	}
+
+	if len(r.peers) >= maxReputations && fallback != nil {
+		delete(r.peers, *fallback)
+	}
+}
+
+// classify event of request error, false if the peer is not to blame
+func classify(err error) (Event, bool) {
+	switch {
+	case err == nil:
+		return EventResponse, true
+	case errors.Is(err, ErrPeerBanned), errors.Is(err, context.Canceled):
+		return 0, false
+	case errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrIdentityMismatch),
+		errors.Is(err, ErrMissingSender), errors.Is(err, ErrPeerIdentity),
+		errors.Is(err, ErrPeerMismatch), errors.Is(err, domain.ErrRecordSignature):
+		return EventInvalidSignature, true
+	case errors.Is(err, ErrMalformedResponse), errors.Is(err, ErrMissingResult),
+		errors.Is(err, ErrUnexpectedResponse):
+		return EventMalformed, true
+	case errors.Is(err, domain.ErrRecordOutdated), errors.Is(err, domain.ErrRecordExpired),
+		errors.Is(err, domain.ErrRecordRejected):
+		// peer responded and declined the record
+		return EventResponse, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return EventTimeout, true
+	}
+
+	switch statusCode(err) {
+	case codes.DeadlineExceeded, codes.Unavailable:
+		return EventTimeout, true
+	}
+
+	return 0, false
+}
+
+// statusCode grpc status code of wrapped error
+func statusCode(err error) codes.Code {
+	var s interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &s) {
+		return s.GRPCStatus().Code()
+	}
+	return codes.Unknown
+}
+
+// reputationTransport transport recording the outcome of requests
+type reputationTransport[T domain.NodeID] struct {
+	domain.Transport[T]
+	reputation *Reputation[T]
+}
+
+// Ping implements domain.Transport
+func (t *reputationTransport[T]) Ping(ctx context.Context, c *domain.Contact[T]) (T, error) {
+
+	var zero T
+
+	if t.reputation.Banned(c.ID) {
+		return zero, ErrPeerBanned
+	}
+
+	nodeID, err := t.Transport.Ping(ctx, c)
+	t.reputation.Observe(c.ID, err)
+	return nodeID, err
+}
+
+// Store implements domain.Transport
+func (t *reputationTransport[T]) Store(ctx context.Context, c *domain.Contact[T], record *domain.Record[T]) error {
+
+	if t.reputation.Banned(c.ID) {
+		return ErrPeerBanned
+	}
+
+	err := t.Transport.Store(ctx, c, record)
+	t.reputation.Observe(c.ID, err)
+	return err
+}
+
+// FindNode implements domain.Transport
+func (t *reputationTransport[T]) FindNode(ctx context.Context, c *domain.Contact[T], nodeID T) ([]*domain.Contact[T], error) {
+
+	if t.reputation.Banned(c.ID) {
+		return nil, ErrPeerBanned
+	}
+
+	contactSlice, err := t.Transport.FindNode(ctx, c, nodeID)
+	t.reputation.Observe(c.ID, err)
+	return contactSlice, err
+}
+
+// FindValue implements domain.Transport, returned records
+// with invalid signatures count against the peer
+func (t *reputationTransport[T]) FindValue(ctx context.Context, c *domain.Contact[T], key T) (*domain.Record[T], []*domain.Contact[T], error) {
+
+	if t.reputation.Banned(c.ID) {
+		return nil, nil, ErrPeerBanned
+	}
+
+	record, contactSlice, err := t.Transport.FindValue(ctx, c, key)
+	if err == nil && record != nil {
+		if record.Key != key {
+			t.reputation.Record(c.ID, EventMalformed)
+			return record, contactSlice, nil
+		}
+		if verr := domain.VerifyRecord(record); verr != nil {
+			t.reputation.Record(c.ID, EventInvalidSignature)
+			return record, contactSlice, nil
+		}
+	}
+
+	t.reputation.Observe(c.ID, err)
+	return record, contactSlice, err
+}
+
+// AddProvider implements domain.Transport
+func (t *reputationTransport[T]) AddProvider(ctx context.Context, c *domain.Contact[T], provider *domain.Provider[T]) error {
+
+	if t.reputation.Banned(c.ID) {
+		return ErrPeerBanned
+	}
+
+	err := t.Transport.AddProvider(ctx, c, provider)
+	t.reputation.Observe(c.ID, err)
+	return err
+}
+
+// GetProviders implements domain.Transport
+func (t *reputationTransport[T]) GetProviders(ctx context.Context, c *domain.Contact[T], key T) ([]*domain.Provider[T], []*domain.Contact[T], error) {
+
+	if t.reputation.Banned(c.ID) {
+		return nil, nil, ErrPeerBanned
+	}
+
+	providers, contactSlice, err := t.Transport.GetProviders(ctx, c, key)
+	t.reputation.Observe(c.ID, err)
+	return providers, contactSlice, err
+}
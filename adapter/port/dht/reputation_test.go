@@ -0,0 +1,372 @@
+package dht_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/structx/go-dpkg/adapter/port/dht"
+	"github.com/structx/go-dpkg/adapter/setup"
+	"github.com/structx/go-dpkg/domain"
+	pbv1 "github.com/structx/go-dpkg/proto/dht/v1"
+	kademlia "github.com/structx/go-dpkg/structs/dht"
+	"github.com/structx/go-dpkg/util/decode"
+)
+
+// stubTransport answers every request with err and record
+type stubTransport struct {
+	domain.Transport[domain.NodeID224]
+	calls  int
+	err    error
+	record *domain.Record[domain.NodeID224]
+}
+
+func (s *stubTransport) Ping(_ context.Context, c *domain.Contact[domain.NodeID224]) (domain.NodeID224, error) {
+	s.calls++
+	return c.ID, s.err
+}
+
+func (s *stubTransport) FindValue(context.Context, *domain.Contact[domain.NodeID224], domain.NodeID224) (*domain.Record[domain.NodeID224], []*domain.Contact[domain.NodeID224], error) {
+	s.calls++
+	return s.record, nil, s.err
+}
+
+type ReputationSuite struct {
+	suite.Suite
+	cfg domain.Config
+}
+
+func (suite *ReputationSuite) SetupTest() {
+
+	cfg := setup.New()
+	suite.Assert().NoError(decode.ConfigFromEnv(cfg))
+	suite.cfg = cfg
+}
+
+func (suite *ReputationSuite) TestReputation() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	r, err := dht.NewReputation[domain.NodeID224](zap.NewNop(), suite.cfg)
+	assert.NoError(err)
+
+	stub := &stubTransport{}
+	t := r.Transport(stub)
+
+	c := &domain.Contact[domain.NodeID224]{IP: "127.0.0.1", Port: 50051}
+	c.ID = domain.HashKey[domain.NodeID224]([]byte(c.Address()))
+
+	// responses raise the score up to the maximum
+	for range 20 {
+		_, err = t.Ping(ctx, c)
+		assert.NoError(err)
+	}
+	assert.Equal(10.0, r.Score(c.ID))
+
+	// cancelled requests are not the fault of the peer
+	stub.err = fmt.Errorf("failed to send ping request %w", context.Canceled)
+	_, _ = t.Ping(ctx, c)
+	assert.Equal(10.0, r.Score(c.ID))
+
+	stub.err = fmt.Errorf("failed to send ping request %w", status.Error(codes.DeadlineExceeded, "deadline exceeded"))
+	_, _ = t.Ping(ctx, c)
+	assert.Equal(8.0, r.Score(c.ID))
+
+	stub.err = fmt.Errorf("invalid ping response %w", dht.ErrInvalidSignature)
+	_, _ = t.Ping(ctx, c)
+	assert.Equal(-12.0, r.Score(c.ID))
+	assert.False(r.Banned(c.ID))
+
+	stub.err = dht.ErrMalformedResponse
+	for range 2 {
+		_, _ = t.Ping(ctx, c)
+	}
+	assert.True(r.Banned(c.ID))
+
+	// banned peers are not contacted
+	calls := stub.calls
+	_, err = t.Ping(ctx, c)
+	assert.ErrorIs(err, dht.ErrPeerBanned)
+	assert.Equal(calls, stub.calls)
+
+	// records with invalid signatures count against the peer that returned them
+	_, key, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+
+	record := &domain.Record[domain.NodeID224]{Value: []byte("value"), Seq: 1, Timestamp: time.Now()}
+	domain.SignRecord(key, record)
+	record.Value = []byte("forged")
+
+	other := &domain.Contact[domain.NodeID224]{IP: "127.0.0.2", Port: 50051}
+	other.ID = domain.HashKey[domain.NodeID224]([]byte(other.Address()))
+	stub.err, stub.record = nil, record
+
+	found, _, err := t.FindValue(ctx, other, record.Key)
+	assert.NoError(err)
+	assert.Equal(record, found)
+	assert.True(r.Banned(other.ID))
+}
+
+func (suite *ReputationSuite) TestBanExpires() {
+
+	assert := suite.Assert()
+
+	suite.cfg.GetDistributedHashTable().Reputation = &domain.Reputation{BanThreshold: -1, BanDuration: 1}
+
+	r, err := dht.NewReputation[domain.NodeID224](zap.NewNop(), suite.cfg)
+	assert.NoError(err)
+
+	nodeID := domain.HashKey[domain.NodeID224]([]byte("peer"))
+	r.Record(nodeID, dht.EventTimeout)
+	assert.True(r.Banned(nodeID))
+
+	// events during the ban are ignored
+	r.Record(nodeID, dht.EventResponse)
+	assert.Equal(0.0, r.Score(nodeID))
+
+	assert.Eventually(func() bool { return !r.Banned(nodeID) }, time.Second*3, time.Millisecond*50)
+
+	suite.cfg.GetDistributedHashTable().Reputation = &domain.Reputation{BanThreshold: 1}
+	_, err = dht.NewReputation[domain.NodeID224](zap.NewNop(), suite.cfg)
+	assert.Error(err)
+}
+
+func (suite *ReputationSuite) TestRateLimit() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	// refills are too slow to matter during the test
+	suite.cfg.GetDistributedHashTable().RateLimit = &domain.RateLimit{SenderRate: 0.01, SenderBurst: 3, IPRate: 0.01, IPBurst: 6}
+
+	r, err := dht.NewReputation[domain.NodeID224](zap.NewNop(), suite.cfg)
+	assert.NoError(err)
+
+	limiter, err := dht.NewRateLimiter(suite.cfg, r)
+	assert.NoError(err)
+
+	newTransport := func() (*dht.UDPTransport[domain.NodeID224], *domain.Contact[domain.NodeID224]) {
+
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		assert.NoError(err)
+
+		pub, key, err := ed25519.GenerateKey(nil)
+		assert.NoError(err)
+
+		u, err := dht.NewUDPTransport[domain.NodeID224](zap.NewNop(), suite.cfg, key, conn, limiter.UnaryServerInterceptor)
+		assert.NoError(err)
+
+		n := kademlia.NewNode[domain.NodeID224](ctx, "127.0.0.1", conn.LocalAddr().(*net.UDPAddr).Port, domain.DefaultReplicationFactor, pub,
+			kademlia.WithTransport[domain.NodeID224](u))
+		u.Attach(n)
+
+		go func() { _ = u.Serve() }()
+
+		return u, n.Contact()
+	}
+
+	server, contact := newTransport()
+	defer func() { assert.NoError(server.Close()) }()
+
+	first, firstContact := newTransport()
+	defer func() { assert.NoError(first.Close()) }()
+
+	second, _ := newTransport()
+	defer func() { assert.NoError(second.Close()) }()
+
+	for range 3 {
+		_, err = first.Ping(ctx, contact)
+		assert.NoError(err)
+	}
+	_, err = first.Ping(ctx, contact)
+	assert.ErrorContains(err, "rate limit of sender exceeded")
+
+	// every sender shares the limit of their ip, requests
+	// rejected for their sender count against it as well
+	for range 2 {
+		_, err = second.Ping(ctx, contact)
+		assert.NoError(err)
+	}
+	_, err = second.Ping(ctx, contact)
+	assert.ErrorContains(err, "rate limit of ip exceeded")
+
+	// banned senders are rejected without limits
+	suite.cfg.GetDistributedHashTable().RateLimit = nil
+	limiter, err = dht.NewRateLimiter(suite.cfg, r)
+	assert.NoError(err)
+
+	unlimited, unlimitedContact := newTransport()
+	defer func() { assert.NoError(unlimited.Close()) }()
+
+	_, err = first.Ping(ctx, unlimitedContact)
+	assert.NoError(err)
+
+	r.Record(firstContact.ID, dht.EventInvalidSignature)
+	_, err = first.Ping(ctx, unlimitedContact)
+	assert.ErrorContains(err, dht.ErrPeerBanned.Error())
+
+	suite.cfg.GetDistributedHashTable().RateLimit = &domain.RateLimit{SenderRate: -1}
+	_, err = dht.NewRateLimiter(suite.cfg, r)
+	assert.Error(err)
+}
+
+// signedFindNode find node request of key, the signature is broken if forged
+func signedFindNode(key ed25519.PrivateKey, forged bool) *pbv1.FindNodeRequest {
+
+	pub := key.Public().(ed25519.PublicKey)
+	senderID := domain.NodeIDFromPublicKey[domain.NodeID224](pub)
+	target := domain.HashKey[domain.NodeID224]([]byte("target"))
+
+	in := &pbv1.FindNodeRequest{
+		Sender: &pbv1.Sender{
+			SenderId:    senderID[:],
+			RequestedAt: timestamppb.Now(),
+			PublicKey:   pub,
+		},
+		NodeId: target[:],
+	}
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(in)
+	in.Sender.Signature = ed25519.Sign(key, b)
+
+	if forged {
+		in.NodeId = senderID[:]
+	}
+
+	return in
+}
+
+func (suite *ReputationSuite) TestRateLimitGRPC() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	suite.cfg.GetDistributedHashTable().RateLimit = &domain.RateLimit{SenderRate: 0.01, SenderBurst: 2}
+
+	// newServer grpc server behind the rate limiter, without
+	// node credentials requests have no channel identity
+	newServer := func(tls bool) (*domain.Contact[domain.NodeID224], *kademlia.Node[domain.NodeID224], *dht.Reputation[domain.NodeID224]) {
+
+		r, err := dht.NewReputation[domain.NodeID224](zap.NewNop(), suite.cfg)
+		assert.NoError(err)
+
+		limiter, err := dht.NewRateLimiter(suite.cfg, r)
+		assert.NoError(err)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(err)
+		port := listener.Addr().(*net.TCPAddr).Port
+
+		pub, key, err := ed25519.GenerateKey(nil)
+		assert.NoError(err)
+
+		n := kademlia.NewNode[domain.NodeID224](ctx, "127.0.0.1", port, domain.DefaultReplicationFactor, pub)
+		r.OnBan(n.RoutingTable().Remove)
+
+		opts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(limiter.UnaryServerInterceptor, dht.VerifySender[domain.NodeID224])}
+		if tls {
+			creds, err := dht.NewCredentials[domain.NodeID224](key)
+			assert.NoError(err)
+			opts = append(opts, grpc.Creds(creds))
+		}
+
+		srv := grpc.NewServer(opts...)
+		pbv1.RegisterDHTServiceServer(srv, dht.NewGRPCServer[domain.NodeID224](zap.NewNop(), n, key))
+		go func() { _ = srv.Serve(listener) }()
+		suite.T().Cleanup(srv.Stop)
+
+		return n.Contact(), n, r
+	}
+
+	for _, tls := range []bool{true, false} {
+
+		contact, n, r := newServer(tls)
+
+		pub, key, err := ed25519.GenerateKey(nil)
+		assert.NoError(err)
+		senderID := domain.NodeIDFromPublicKey[domain.NodeID224](pub)
+
+		dial := grpc.WithTransportCredentials(insecure.NewCredentials())
+		if tls {
+			creds, err := dht.NewCredentials[domain.NodeID224](key)
+			assert.NoError(err)
+			dial = grpc.WithTransportCredentials(creds)
+		}
+
+		conn, err := grpc.Dial(contact.Address(), dial)
+		assert.NoError(err)
+		cli := pbv1.NewDHTServiceClient(conn)
+
+		_, err = cli.FindNode(ctx, signedFindNode(key, false))
+		assert.NoError(err)
+
+		sender := &domain.Contact[domain.NodeID224]{IP: "10.0.0.1", Port: 50051, ID: senderID}
+		n.AddOrUpdateRoutingTable(ctx, sender)
+		assert.Contains(n.ClosestContacts(senderID, 1), sender)
+
+		if tls {
+
+			// invalid signature on the channel of the sender bans it with the defaults
+			_, err = cli.FindNode(ctx, signedFindNode(key, true))
+			assert.Equal(codes.Unauthenticated, status.Code(err))
+			assert.True(r.Banned(senderID))
+
+			// banned peers are no longer routed to
+			assert.NotContains(n.ClosestContacts(senderID, 1), sender)
+
+			_, err = cli.FindNode(ctx, signedFindNode(key, false))
+			assert.Equal(codes.PermissionDenied, status.Code(err))
+		} else {
+
+			// forged requests claiming the sender id neither
+			// penalize the sender nor drain its bucket
+			for range 3 {
+				_, err = cli.FindNode(ctx, signedFindNode(key, true))
+				assert.Equal(codes.Unauthenticated, status.Code(err))
+			}
+			assert.False(r.Banned(senderID))
+			assert.Zero(r.Score(senderID))
+			assert.Contains(n.ClosestContacts(senderID, 1), sender)
+
+			_, err = cli.FindNode(ctx, signedFindNode(key, false))
+			assert.NoError(err)
+		}
+
+		// senders are limited by their own bucket
+		_, other, err := ed25519.GenerateKey(nil)
+		assert.NoError(err)
+		if tls {
+			creds, err := dht.NewCredentials[domain.NodeID224](other)
+			assert.NoError(err)
+			assert.NoError(conn.Close())
+			conn, err = grpc.Dial(contact.Address(), grpc.WithTransportCredentials(creds))
+			assert.NoError(err)
+			cli = pbv1.NewDHTServiceClient(conn)
+		}
+
+		for range 2 {
+			_, err = cli.FindNode(ctx, signedFindNode(other, false))
+			assert.NoError(err)
+		}
+		_, err = cli.FindNode(ctx, signedFindNode(other, false))
+		assert.Equal(codes.ResourceExhausted, status.Code(err))
+
+		assert.NoError(conn.Close())
+	}
+}
+
+func TestReputationSuite(t *testing.T) {
+	suite.Run(t, new(ReputationSuite))
+}
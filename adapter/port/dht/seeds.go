@@ -0,0 +1,40 @@
+package dht
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+// SeedContacts contacts of seed nodes in distributed hash table configuration
+func SeedContacts[T domain.NodeID](cfg domain.Config) ([]*domain.Contact[T], error) {
+
+	dhtCfg := cfg.GetDistributedHashTable()
+	if dhtCfg == nil {
+		return nil, errors.New("missing distributed hash table configuration")
+	}
+
+	contactSlice := make([]*domain.Contact[T], 0, len(dhtCfg.Seeds))
+	for _, seed := range dhtCfg.Seeds {
+
+		host, port, err := net.SplitHostPort(seed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seed address %s %v", seed, err)
+		}
+
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seed port %s %v", seed, err)
+		}
+
+		// seed ids are learned from their signed ping response
+		c := &domain.Contact[T]{IP: host, Port: p}
+
+		contactSlice = append(contactSlice, c)
+	}
+
+	return contactSlice, nil
+}
@@ -0,0 +1,33 @@
+
+server {
+    bind_addr = "0.0.0.0"
+    default_timeout = 15
+    ports {
+        http = 8080
+        grpc = 50051
+    }
+}
+
+logger {
+    log_path = "./testfiles/log/test.log"
+    log_level = "DEBUG"
+    raft_log_path = "./testfiles/log"
+}
+
+dht {
+    bind_addr = "127.0.0.1"
+    data_dir = "./testfiles/data"
+
+    ports {
+        grpc = 50052
+    }
+
+    seeds = ["127.0.0.1:50053", "127.0.0.1:50054"]
+    refresh_interval = 3600
+
+    timeouts {
+        dial = 500
+        request = 200
+        idle = 100
+    }
+}
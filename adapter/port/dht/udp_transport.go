@@ -0,0 +1,881 @@
+package dht
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/structx/go-dpkg/domain"
+	pbv1 "github.com/structx/go-dpkg/proto/dht/v1"
+)
+
+const (
+	// DefaultMaxPacketSize largest udp datagram sent, fits the minimum
+	// ipv6 mtu of 1280 bytes after ip and udp headers
+	DefaultMaxPacketSize = 1232
+	// DefaultUDPRetries retransmissions of a request without response
+	DefaultUDPRetries = 2
+	// maxFragments fragments a packet may be split into, larger
+	// messages must be sent over the grpc transport
+	maxFragments = 64
+	// fragmentOverhead bytes of a fragment besides its chunk
+	fragmentOverhead = 32
+	// maxInflight inbound requests handled concurrently,
+	// requests arriving while all are busy are dropped
+	maxInflight = 256
+	// readBufferSize largest udp payload
+	readBufferSize = 65535
+	// methodPrefix full method name of requests passed to interceptors
+	methodPrefix = "/dht.v1.DHTService/"
+)
+
+var (
+	// ErrPacketTooLarge message does not fit into the maximum number of
+	// fragments, callers fall back to the grpc transport
+	ErrPacketTooLarge = errors.New("message exceeds maximum packet size")
+	// ErrUnexpectedResponse response does not match the request
+	ErrUnexpectedResponse = errors.New("unexpected response")
+)
+
+// assembly chunks of a fragmented packet received so far
+type assembly struct {
+	chunks   [][]byte
+	received int
+	expires  time.Time
+}
+
+// fragmentKey fragmented request of a remote address
+type fragmentKey struct {
+	addr string
+	id   uint64
+}
+
+// call request waiting for its response
+type call struct {
+	// addr remote address responses must come from
+	addr      string
+	responses chan *pbv1.Packet
+}
+
+// UDPTransport dht transport exchanging protobuf packets over udp
+//
+// the socket sends requests and serves requests of other nodes with
+// the handlers of GRPCServer behind the same interceptors. requests are
+// retransmitted until a response arrives, requests and responses larger
+// than the maximum packet size are split into fragments. nodes using the
+// udp transport advertise the udp port as their contact port
+type UDPTransport[T domain.NodeID] struct {
+	logger *zap.Logger
+	log    *zap.SugaredLogger
+	conn   *net.UDPConn
+	key    ed25519.PrivateKey
+
+	// advertise configured address, replaced by
+	// the contact of the attached node
+	advertise domain.Contact[T]
+	dmtx      sync.RWMutex
+	dht       domain.DHT[T]
+	server    *GRPCServer[T]
+
+	// interceptors run before the sender is verified
+	interceptors []grpc.UnaryServerInterceptor
+
+	mtx   sync.Mutex
+	calls map[uint64]*call
+
+	// requests still missing fragments, only used by Serve
+	partial map[fragmentKey]*assembly
+
+	// timeout per attempt of a request
+	timeout   time.Duration
+	retries   int
+	maxPacket int
+
+	inflight chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// interface compliance
+var _ domain.Transport[domain.NodeID224] = (*UDPTransport[domain.NodeID224])(nil)
+
+// ListenUDP socket on the udp address of the dht configuration
+func ListenUDP(cfg domain.Config) (*net.UDPConn, error) {
+
+	addr, err := UDPListenAddr(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	laddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve udp address %v", err)
+	}
+
+	conn, err := net.ListenUDP("udp", laddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on udp address %v", err)
+	}
+
+	return conn, nil
+}
+
+// NewUDPTransport constructor sending and serving requests on conn,
+// interceptors run in order before the sender of a request is verified
+func NewUDPTransport[T domain.NodeID](logger *zap.Logger, cfg domain.Config, key ed25519.PrivateKey, conn *net.UDPConn, interceptors ...grpc.UnaryServerInterceptor) (*UDPTransport[T], error) {
+
+	dcfg := cfg.GetDistributedHashTable()
+	if dcfg == nil {
+		return nil, errors.New("missing distributed hash table configuration")
+	}
+
+	ip, _, err := AdvertiseAddr(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &UDPTransport[T]{
+		logger:       logger,
+		log:          logger.Sugar().Named("DHTUDPTransport"),
+		conn:         conn,
+		key:          key,
+		advertise:    domain.Contact[T]{IP: ip, Port: conn.LocalAddr().(*net.UDPAddr).Port, ID: domain.NodeIDFromPublicKey[T](key.Public().(ed25519.PublicKey))},
+		interceptors: interceptors,
+		calls:        make(map[uint64]*call),
+		partial:      make(map[fragmentKey]*assembly),
+		timeout:      DefaultRequestTimeout,
+		retries:      DefaultUDPRetries,
+		maxPacket:    DefaultMaxPacketSize,
+		inflight:     make(chan struct{}, maxInflight),
+	}
+
+	if t := dcfg.Timeouts; t != nil && t.Request > 0 {
+		u.timeout = time.Duration(t.Request) * time.Millisecond
+	}
+	if dcfg.MaxPacketSize > 0 {
+		u.maxPacket = dcfg.MaxPacketSize
+	}
+
+	u.ctx, u.cancel = context.WithCancel(context.Background())
+
+	return u, nil
+}
+
+// Attach node serving inbound requests, requests advertise the contact
+// of the node and external addresses reported by peers are passed to it
+func (u *UDPTransport[T]) Attach(dht domain.DHT[T]) {
+	u.dmtx.Lock()
+	defer u.dmtx.Unlock()
+	u.dht = dht
+	u.server = NewGRPCServer(u.logger, dht, u.key)
+}
+
+// Serve read packets until the transport is closed, requests
+// received before a node is attached are dropped
+func (u *UDPTransport[T]) Serve() error {
+
+	buf := make([]byte, readBufferSize)
+	for {
+		n, addr, err := u.conn.ReadFromUDP(buf)
+		if err != nil {
+			if u.ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			u.log.Debugf("failed to read packet %v", err)
+			continue
+		}
+
+		p := &pbv1.Packet{}
+		if err := proto.Unmarshal(bytes.Clone(buf[:n]), p); err != nil {
+			u.log.Debugf("malformed packet from %s %v", addr, err)
+			continue
+		}
+
+		if isResponse(p) {
+			u.deliver(addr, p)
+			continue
+		}
+
+		if p.GetRequestChunk() != nil {
+			if p = u.reassemble(addr, p); p == nil {
+				continue
+			}
+		}
+
+		select {
+		case u.inflight <- struct{}{}:
+			u.wg.Add(1)
+			go func() {
+				defer func() { <-u.inflight; u.wg.Done() }()
+				u.handle(addr, p)
+			}()
+		default:
+			u.log.Debugf("dropped request from %s", addr)
+		}
+	}
+}
+
+// Close stop serving and wait for running handlers
+func (u *UDPTransport[T]) Close() error {
+
+	u.cancel()
+	err := u.conn.Close()
+	u.wg.Wait()
+
+	if err != nil {
+		return fmt.Errorf("failed to close udp socket %v", err)
+	}
+
+	return nil
+}
+
+// Ping contact, returns node id of responder
+func (u *UDPTransport[T]) Ping(ctx context.Context, c *domain.Contact[T]) (T, error) {
+
+	var zero T
+
+	in := &pbv1.PingRequest{Sender: u.sender()}
+	if err := sign(u.key, in, in.Sender); err != nil {
+		return zero, err
+	}
+
+	p, err := u.call(ctx, c, &pbv1.Packet{Body: &pbv1.Packet_PingRequest{PingRequest: in}})
+	if err != nil {
+		return zero, fmt.Errorf("failed to send ping request %w", err)
+	}
+
+	response := p.GetPingResponse()
+	if response == nil {
+		return zero, ErrUnexpectedResponse
+	}
+
+	nodeID, err := responder(c, response)
+	if err != nil {
+		return zero, err
+	}
+	u.observe(ctx, nodeID, response.GetObservedIp())
+	u.echo(ctx, nodeID, response.GetEcho())
+
+	return nodeID, nil
+}
+
+// Store record on contact
+func (u *UDPTransport[T]) Store(ctx context.Context, c *domain.Contact[T], record *domain.Record[T]) error {
+
+	in := newStoreRequest(u.sender(), record)
+	if err := sign(u.key, in, in.Sender); err != nil {
+		return err
+	}
+
+	p, err := u.call(ctx, c, &pbv1.Packet{Body: &pbv1.Packet_StoreRequest{StoreRequest: in}})
+	if err != nil {
+		return fmt.Errorf("failed to send store request %w", err)
+	}
+
+	response := p.GetStoreResponse()
+	if response == nil {
+		return ErrUnexpectedResponse
+	}
+
+	responderID, err := responder(c, response)
+	if err != nil {
+		return err
+	}
+	u.echo(ctx, responderID, response.GetEcho())
+
+	return storeResult(response)
+}
+
+// FindNode ask contact for closest contacts to node id
+func (u *UDPTransport[T]) FindNode(ctx context.Context, c *domain.Contact[T], nodeID T) ([]*domain.Contact[T], error) {
+
+	in := &pbv1.FindNodeRequest{
+		Sender: u.sender(),
+		NodeId: domain.Bytes(nodeID),
+	}
+	if err := sign(u.key, in, in.Sender); err != nil {
+		return nil, err
+	}
+
+	p, err := u.call(ctx, c, &pbv1.Packet{Body: &pbv1.Packet_FindNodeRequest{FindNodeRequest: in}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send find node request %w", err)
+	}
+
+	response := p.GetFindNodeResponse()
+	if response == nil {
+		return nil, ErrUnexpectedResponse
+	}
+
+	responderID, err := responder(c, response)
+	if err != nil {
+		return nil, err
+	}
+	u.echo(ctx, responderID, response.GetEcho())
+
+	return findNodeResult[T](response)
+}
+
+// FindValue ask contact for record stored under key
+func (u *UDPTransport[T]) FindValue(ctx context.Context, c *domain.Contact[T], key T) (*domain.Record[T], []*domain.Contact[T], error) {
+
+	in := &pbv1.FindValueRequest{
+		Sender: u.sender(),
+		Key:    domain.Bytes(key),
+	}
+	if err := sign(u.key, in, in.Sender); err != nil {
+		return nil, nil, err
+	}
+
+	p, err := u.call(ctx, c, &pbv1.Packet{Body: &pbv1.Packet_FindValueRequest{FindValueRequest: in}})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to send find value request %w", err)
+	}
+
+	response := p.GetFindValueResponse()
+	if response == nil {
+		return nil, nil, ErrUnexpectedResponse
+	}
+
+	responderID, err := responder(c, response)
+	if err != nil {
+		return nil, nil, err
+	}
+	u.echo(ctx, responderID, response.GetEcho())
+
+	return findValueResult[T](response)
+}
+
+// AddProvider store provider record on contact
+func (u *UDPTransport[T]) AddProvider(ctx context.Context, c *domain.Contact[T], provider *domain.Provider[T]) error {
+
+	in := &pbv1.AddProviderRequest{
+		Sender:   u.sender(),
+		Key:      domain.Bytes(provider.Key),
+		Provider: providerToProto(provider),
+	}
+	if err := sign(u.key, in, in.Sender); err != nil {
+		return err
+	}
+
+	p, err := u.call(ctx, c, &pbv1.Packet{Body: &pbv1.Packet_AddProviderRequest{AddProviderRequest: in}})
+	if err != nil {
+		return fmt.Errorf("failed to send add provider request %w", err)
+	}
+
+	response := p.GetAddProviderResponse()
+	if response == nil {
+		return ErrUnexpectedResponse
+	}
+
+	responderID, err := responder(c, response)
+	if err != nil {
+		return err
+	}
+	u.echo(ctx, responderID, response.GetEcho())
+
+	return addProviderResult(response)
+}
+
+// GetProviders ask contact for providers of key
+func (u *UDPTransport[T]) GetProviders(ctx context.Context, c *domain.Contact[T], key T) ([]*domain.Provider[T], []*domain.Contact[T], error) {
+
+	in := &pbv1.GetProvidersRequest{
+		Sender: u.sender(),
+		Key:    domain.Bytes(key),
+	}
+	if err := sign(u.key, in, in.Sender); err != nil {
+		return nil, nil, err
+	}
+
+	p, err := u.call(ctx, c, &pbv1.Packet{Body: &pbv1.Packet_GetProvidersRequest{GetProvidersRequest: in}})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to send get providers request %w", err)
+	}
+
+	response := p.GetGetProvidersResponse()
+	if response == nil {
+		return nil, nil, ErrUnexpectedResponse
+	}
+
+	responderID, err := responder(c, response)
+	if err != nil {
+		return nil, nil, err
+	}
+	u.echo(ctx, responderID, response.GetEcho())
+
+	return getProvidersResult(key, response)
+}
+
+// call send request to contact and wait for the response,
+// the request is retransmitted after every timeout
+func (u *UDPTransport[T]) call(ctx context.Context, c *domain.Contact[T], request *pbv1.Packet) (*pbv1.Packet, error) {
+
+	addr, err := net.ResolveUDPAddr("udp", c.Address())
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to resolve %s %v", c.Address(), err)
+	}
+
+	id, cl := u.register(addr.String())
+	defer u.unregister(id)
+
+	request.RequestId = id
+	datagrams, err := split(request, u.maxPacket, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var fragments *assembly
+	for attempt := 0; attempt <= u.retries; attempt++ {
+
+		for _, b := range datagrams {
+			if _, err := u.conn.WriteToUDP(b, addr); err != nil {
+				return nil, status.Errorf(codes.Unavailable, "failed to send packet %v", err)
+			}
+		}
+
+		response, err := u.await(ctx, cl, &fragments)
+		if err != nil || response != nil {
+			return response, err
+		}
+	}
+
+	return nil, status.Error(codes.DeadlineExceeded, "no response to udp request")
+}
+
+// await response or all of its fragments, nil without error
+// if nothing complete arrived before the attempt timed out
+func (u *UDPTransport[T]) await(ctx context.Context, cl *call, fragments **assembly) (*pbv1.Packet, error) {
+
+	timer := time.NewTimer(u.timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		case <-u.ctx.Done():
+			return nil, status.Error(codes.Unavailable, "udp transport closed")
+		case <-timer.C:
+			return nil, nil
+		case p := <-cl.responses:
+
+			if p.GetResponseChunk() != nil {
+				if !validFragment(p) {
+					continue
+				}
+				if *fragments == nil {
+					*fragments = newAssembly(p.GetFragments(), time.Time{})
+				}
+				if !(*fragments).add(p) {
+					continue
+				}
+
+				merged, err := (*fragments).packet()
+				if err != nil || merged.GetRequestId() != p.GetRequestId() || !isResponse(merged) || merged.GetResponseChunk() != nil {
+					*fragments = nil
+					continue
+				}
+				p = merged
+			}
+
+			if e := p.GetError(); e != nil {
+				return nil, status.Error(codes.Code(e.GetCode()), e.GetMessage())
+			}
+
+			return p, nil
+		}
+	}
+}
+
+// register request to address under an unused random id
+func (u *UDPTransport[T]) register(addr string) (uint64, *call) {
+
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+
+	for {
+		id := rand.Uint64()
+		if _, ok := u.calls[id]; ok || id == 0 {
+			continue
+		}
+
+		cl := &call{addr: addr, responses: make(chan *pbv1.Packet, maxFragments)}
+		u.calls[id] = cl
+		return id, cl
+	}
+}
+
+func (u *UDPTransport[T]) unregister(id uint64) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	delete(u.calls, id)
+}
+
+// deliver response to waiting request, responses from
+// other addresses than the request was sent to are dropped
+func (u *UDPTransport[T]) deliver(addr *net.UDPAddr, p *pbv1.Packet) {
+
+	u.mtx.Lock()
+	cl, ok := u.calls[p.GetRequestId()]
+	u.mtx.Unlock()
+
+	if !ok || cl.addr != addr.String() {
+		return
+	}
+
+	select {
+	case cl.responses <- p:
+	default:
+	}
+}
+
+// reassemble fragment of request from addr, returns the request once
+// all of its fragments arrived. fragments of at most maxInflight
+// requests are held until the requester would have given up
+func (u *UDPTransport[T]) reassemble(addr *net.UDPAddr, p *pbv1.Packet) *pbv1.Packet {
+
+	if !validFragment(p) {
+		return nil
+	}
+
+	now := time.Now()
+	key := fragmentKey{addr: addr.String(), id: p.GetRequestId()}
+
+	a, ok := u.partial[key]
+	if !ok || now.After(a.expires) {
+
+		if len(u.partial) >= maxInflight {
+			for k, a := range u.partial {
+				if now.After(a.expires) {
+					delete(u.partial, k)
+				}
+			}
+		}
+		if len(u.partial) >= maxInflight {
+			u.log.Debugf("dropped fragment from %s", addr)
+			return nil
+		}
+
+		a = newAssembly(p.GetFragments(), now.Add(u.timeout*time.Duration(u.retries+1)))
+		u.partial[key] = a
+	}
+
+	if !a.add(p) {
+		return nil
+	}
+	delete(u.partial, key)
+
+	request, err := a.packet()
+	if err != nil || request.GetRequestId() != p.GetRequestId() || isResponse(request) || request.GetRequestChunk() != nil {
+		u.log.Debugf("malformed fragmented request from %s", addr)
+		return nil
+	}
+
+	return request
+}
+
+// handle request and send the response in as many fragments as needed
+func (u *UDPTransport[T]) handle(addr *net.UDPAddr, p *pbv1.Packet) {
+
+	u.dmtx.RLock()
+	srv := u.server
+	u.dmtx.RUnlock()
+
+	if srv == nil {
+		return
+	}
+
+	ctx := peer.NewContext(u.ctx, &peer.Peer{Addr: addr})
+
+	response, err := u.serve(ctx, srv, p)
+	if err != nil {
+		response = errorPacket(err)
+	}
+	response.RequestId = p.GetRequestId()
+
+	datagrams, err := split(response, u.maxPacket, true)
+	if err != nil {
+		u.log.Errorf("failed to split response to %s %v", addr, err)
+
+		response = errorPacket(status.Error(codes.ResourceExhausted, "response exceeds maximum packet size"))
+		response.RequestId = p.GetRequestId()
+		if datagrams, err = split(response, u.maxPacket, true); err != nil {
+			return
+		}
+	}
+
+	for _, b := range datagrams {
+		if _, err := u.conn.WriteToUDP(b, addr); err != nil {
+			u.log.Debugf("failed to send response to %s %v", addr, err)
+			return
+		}
+	}
+}
+
+// serve request with the grpc handler of its type
+func (u *UDPTransport[T]) serve(ctx context.Context, srv *GRPCServer[T], p *pbv1.Packet) (*pbv1.Packet, error) {
+
+	var (
+		req     any
+		method  string
+		handler grpc.UnaryHandler
+	)
+
+	switch body := p.GetBody().(type) {
+	case *pbv1.Packet_PingRequest:
+		req, method = body.PingRequest, "Ping"
+		handler = func(ctx context.Context, req any) (any, error) {
+			return srv.Ping(ctx, req.(*pbv1.PingRequest))
+		}
+	case *pbv1.Packet_StoreRequest:
+		req, method = body.StoreRequest, "Store"
+		handler = func(ctx context.Context, req any) (any, error) {
+			return srv.Store(ctx, req.(*pbv1.StoreRequest))
+		}
+	case *pbv1.Packet_FindNodeRequest:
+		req, method = body.FindNodeRequest, "FindNode"
+		handler = func(ctx context.Context, req any) (any, error) {
+			return srv.FindNode(ctx, req.(*pbv1.FindNodeRequest))
+		}
+	case *pbv1.Packet_FindValueRequest:
+		req, method = body.FindValueRequest, "FindValue"
+		handler = func(ctx context.Context, req any) (any, error) {
+			return srv.FindValue(ctx, req.(*pbv1.FindValueRequest))
+		}
+	case *pbv1.Packet_AddProviderRequest:
+		req, method = body.AddProviderRequest, "AddProvider"
+		handler = func(ctx context.Context, req any) (any, error) {
+			return srv.AddProvider(ctx, req.(*pbv1.AddProviderRequest))
+		}
+	case *pbv1.Packet_GetProvidersRequest:
+		req, method = body.GetProvidersRequest, "GetProviders"
+		handler = func(ctx context.Context, req any) (any, error) {
+			return srv.GetProviders(ctx, req.(*pbv1.GetProvidersRequest))
+		}
+	default:
+		return nil, status.Error(codes.Unimplemented, "unknown request")
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodPrefix + method}
+
+	chain := append(append([]grpc.UnaryServerInterceptor{}, u.interceptors...), VerifySender[T])
+	for i := len(chain) - 1; i >= 0; i-- {
+		interceptor, next := chain[i], handler
+		handler = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+
+	response, err := handler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// ping responses are signed by the handler, other responses
+	// are signed here since udp has no authenticated channel
+	var packet *pbv1.Packet
+	switch r := response.(type) {
+	case *pbv1.PingResponse:
+		return &pbv1.Packet{Body: &pbv1.Packet_PingResponse{PingResponse: r}}, nil
+	case *pbv1.StoreResponse:
+		r.Responder = u.sender()
+		packet = &pbv1.Packet{Body: &pbv1.Packet_StoreResponse{StoreResponse: r}}
+	case *pbv1.FindNodeResponse:
+		r.Responder = u.sender()
+		packet = &pbv1.Packet{Body: &pbv1.Packet_FindNodeResponse{FindNodeResponse: r}}
+	case *pbv1.FindValueResponse:
+		r.Responder = u.sender()
+		packet = &pbv1.Packet{Body: &pbv1.Packet_FindValueResponse{FindValueResponse: r}}
+	case *pbv1.AddProviderResponse:
+		r.Responder = u.sender()
+		packet = &pbv1.Packet{Body: &pbv1.Packet_AddProviderResponse{AddProviderResponse: r}}
+	case *pbv1.GetProvidersResponse:
+		r.Responder = u.sender()
+		packet = &pbv1.Packet{Body: &pbv1.Packet_GetProvidersResponse{GetProvidersResponse: r}}
+	default:
+		return nil, status.Error(codes.Internal, "unknown response")
+	}
+
+	m := response.(signedResponse)
+	if err := sign(u.key, m, m.GetResponder()); err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	return packet, nil
+}
+
+// sender unsigned sender advertising the local contact
+func (u *UDPTransport[T]) sender() *pbv1.Sender {
+	return newSender(u.key, u.self())
+}
+
+// self advertised contact of attached node or configured address
+func (u *UDPTransport[T]) self() *domain.Contact[T] {
+	u.dmtx.RLock()
+	defer u.dmtx.RUnlock()
+
+	if u.dht != nil {
+		return u.dht.Contact()
+	}
+
+	c := u.advertise
+	return &c
+}
+
+// observe pass external ip reported by peer to attached node
+func (u *UDPTransport[T]) observe(ctx context.Context, observer T, ip string) {
+	u.dmtx.RLock()
+	defer u.dmtx.RUnlock()
+
+	if u.dht != nil {
+		u.dht.ObserveAddr(ctx, observer, ip)
+	}
+}
+
+// echo pass network coordinate of responder to attached node
+func (u *UDPTransport[T]) echo(ctx context.Context, nodeID T, echo *pbv1.Echo) {
+	u.dmtx.RLock()
+	defer u.dmtx.RUnlock()
+
+	var zero T
+	if u.dht != nil && nodeID != zero && echo.GetCoordinate() != nil {
+		u.dht.ObserveCoordinate(ctx, nodeID, coordinateFromProto(echo.GetCoordinate()))
+	}
+}
+
+// signedResponse response signed by the responding node
+type signedResponse interface {
+	proto.Message
+	GetResponder() *pbv1.Sender
+}
+
+// responder verify signature of response, returns node id of responder.
+// responses of contacts with known id must be signed by that node
+func responder[T domain.NodeID](c *domain.Contact[T], response signedResponse) (T, error) {
+
+	var zero T
+
+	nodeID, err := verify[T](response, response.GetResponder())
+	if err != nil {
+		return zero, fmt.Errorf("invalid response %w", err)
+	}
+
+	if c.ID != zero && nodeID != c.ID {
+		return zero, fmt.Errorf("invalid response %w", ErrIdentityMismatch)
+	}
+
+	return nodeID, nil
+}
+
+func isResponse(p *pbv1.Packet) bool {
+	switch p.GetBody().(type) {
+	case *pbv1.Packet_PingResponse, *pbv1.Packet_StoreResponse, *pbv1.Packet_FindNodeResponse,
+		*pbv1.Packet_FindValueResponse, *pbv1.Packet_AddProviderResponse, *pbv1.Packet_GetProvidersResponse,
+		*pbv1.Packet_Error, *pbv1.Packet_ResponseChunk:
+		return true
+	default:
+		return false
+	}
+}
+
+func errorPacket(err error) *pbv1.Packet {
+	s := status.Convert(err)
+	return &pbv1.Packet{Body: &pbv1.Packet_Error{Error: &pbv1.PacketError{Code: uint32(s.Code()), Message: s.Message()}}}
+}
+
+// split packet into datagrams of at most size bytes, larger packets
+// are marshalled and their bytes sent in chunks of request or response
+// fragments, ErrPacketTooLarge if more than maxFragments are needed
+func split(p *pbv1.Packet, size int, response bool) ([][]byte, error) {
+
+	b, err := proto.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal packet %v", err)
+	}
+
+	if len(b) <= size {
+		return [][]byte{b}, nil
+	}
+
+	chunk := size - fragmentOverhead
+	if chunk <= 0 || (len(b)+chunk-1)/chunk > maxFragments {
+		return nil, ErrPacketTooLarge
+	}
+
+	n := (len(b) + chunk - 1) / chunk
+	datagrams := make([][]byte, 0, n)
+	for i := range n {
+
+		f := &pbv1.Packet{RequestId: p.GetRequestId(), Fragment: uint32(i), Fragments: uint32(n)}
+		part := b[i*chunk : min((i+1)*chunk, len(b))]
+		if response {
+			f.Body = &pbv1.Packet_ResponseChunk{ResponseChunk: part}
+		} else {
+			f.Body = &pbv1.Packet_RequestChunk{RequestChunk: part}
+		}
+
+		d, err := proto.Marshal(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal fragment %v", err)
+		}
+		datagrams = append(datagrams, d)
+	}
+
+	return datagrams, nil
+}
+
+// validFragment fragment index and count within bounds
+func validFragment(p *pbv1.Packet) bool {
+	n := p.GetFragments()
+	return n > 1 && n <= maxFragments && p.GetFragment() < n
+}
+
+func newAssembly(n uint32, expires time.Time) *assembly {
+	return &assembly{chunks: make([][]byte, n), expires: expires}
+}
+
+// add chunk of fragment p, true once all fragments arrived, fragments
+// disagreeing on the count or received twice are ignored
+func (a *assembly) add(p *pbv1.Packet) bool {
+
+	i := p.GetFragment()
+	if int(p.GetFragments()) != len(a.chunks) || a.chunks[i] != nil {
+		return false
+	}
+
+	chunk := p.GetRequestChunk()
+	if chunk == nil {
+		chunk = p.GetResponseChunk()
+	}
+
+	a.chunks[i] = chunk
+	a.received++
+
+	return a.received == len(a.chunks)
+}
+
+// packet reassembled from the chunks of all fragments
+func (a *assembly) packet() (*pbv1.Packet, error) {
+
+	p := &pbv1.Packet{}
+	if err := proto.Unmarshal(bytes.Join(a.chunks, nil), p); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fragmented packet %v", err)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,244 @@
+package dht_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/structx/go-dpkg/adapter/port/dht"
+	"github.com/structx/go-dpkg/adapter/setup"
+	"github.com/structx/go-dpkg/domain"
+	pbv1 "github.com/structx/go-dpkg/proto/dht/v1"
+	kademlia "github.com/structx/go-dpkg/structs/dht"
+	"github.com/structx/go-dpkg/util/decode"
+)
+
+type UDPTransportSuite struct {
+	suite.Suite
+	cfg        domain.Config
+	transports []*dht.UDPTransport[domain.NodeID224]
+	nodes      []*kademlia.Node[domain.NodeID224]
+}
+
+func (suite *UDPTransportSuite) SetupTest() {
+
+	assert := suite.Assert()
+
+	cfg := setup.New()
+	assert.NoError(decode.ConfigFromEnv(cfg))
+	suite.cfg = cfg
+
+	suite.transports, suite.nodes = nil, nil
+	for range 2 {
+		u, n := suite.newTransport()
+		suite.transports = append(suite.transports, u)
+		suite.nodes = append(suite.nodes, n)
+	}
+}
+
+// newTransport udp transport serving a new node on a random port
+func (suite *UDPTransportSuite) newTransport() (*dht.UDPTransport[domain.NodeID224], *kademlia.Node[domain.NodeID224]) {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NoError(err)
+
+	pub, key, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+
+	u, err := dht.NewUDPTransport[domain.NodeID224](zap.NewNop(), suite.cfg, key, conn)
+	assert.NoError(err)
+
+	n := kademlia.NewNode[domain.NodeID224](ctx, "127.0.0.1", conn.LocalAddr().(*net.UDPAddr).Port, domain.DefaultReplicationFactor, pub,
+		kademlia.WithTransport[domain.NodeID224](u))
+	u.Attach(n)
+
+	go func() { _ = u.Serve() }()
+
+	return u, n
+}
+
+func (suite *UDPTransportSuite) TestPing() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	id, err := suite.transports[0].Ping(ctx, suite.nodes[1].Contact())
+	assert.NoError(err)
+	assert.Equal(suite.nodes[1].ID, id)
+
+	// server learned the client from the signed sender
+	contactSlice := suite.nodes[1].ClosestContacts(suite.nodes[0].ID, 1)
+	assert.Len(contactSlice, 1)
+	assert.Equal(*suite.nodes[0].Contact(), *contactSlice[0])
+}
+
+func (suite *UDPTransportSuite) TestStoreFindValue() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	key := domain.HashKey[domain.NodeID224]([]byte("key"))
+	record := &domain.Record[domain.NodeID224]{Key: key, Value: []byte("value"), Publisher: suite.nodes[0].ID, Seq: 1, Timestamp: time.Now()}
+	assert.NoError(suite.transports[0].Store(ctx, suite.nodes[1].Contact(), record))
+
+	found, _, err := suite.transports[0].FindValue(ctx, suite.nodes[1].Contact(), key)
+	assert.NoError(err)
+	assert.Equal([]byte("value"), found.Value)
+	assert.Equal(uint64(1), found.Seq)
+
+	found, contactSlice, err := suite.transports[0].FindValue(ctx, suite.nodes[1].Contact(), domain.HashKey[domain.NodeID224]([]byte("missing")))
+	assert.NoError(err)
+	assert.Nil(found)
+	assert.Empty(contactSlice)
+}
+
+func (suite *UDPTransportSuite) TestFragmentedValue() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	value := make([]byte, 5000)
+	for i := range value {
+		value[i] = byte(i)
+	}
+
+	// request and response exceed the maximum packet size
+	key := domain.HashKey[domain.NodeID224]([]byte("large"))
+	record := &domain.Record[domain.NodeID224]{Key: key, Value: value, Publisher: suite.nodes[0].ID, Seq: 1, Timestamp: time.Now()}
+	assert.NoError(suite.transports[0].Store(ctx, suite.nodes[1].Contact(), record))
+
+	found, _, err := suite.transports[0].FindValue(ctx, suite.nodes[1].Contact(), key)
+	assert.NoError(err)
+	assert.Equal(value, found.Value)
+
+	// messages larger than the maximum number of fragments are rejected
+	record.Value = make([]byte, 100000)
+	assert.ErrorIs(suite.transports[0].Store(ctx, suite.nodes[1].Contact(), record), dht.ErrPacketTooLarge)
+}
+
+func (suite *UDPTransportSuite) TestFragments() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	key := domain.HashKey[domain.NodeID224]([]byte("content"))
+	for i := range 20 {
+		provider := &domain.Provider[domain.NodeID224]{
+			Key: key,
+			Contact: domain.Contact[domain.NodeID224]{
+				IP:   fmt.Sprintf("2001:db8:85a3:8d3:1319:8a2e:370:%x", i),
+				Port: 50051,
+				ID:   domain.HashKey[domain.NodeID224]([]byte(fmt.Sprintf("provider-%d", i))),
+			},
+			TTL: time.Hour,
+		}
+		assert.NoError(suite.nodes[1].AddProvider(ctx, provider))
+	}
+
+	// response exceeds the maximum packet size
+	providers, _, err := suite.transports[0].GetProviders(ctx, suite.nodes[1].Contact(), key)
+	assert.NoError(err)
+	assert.Len(providers, 20)
+}
+
+func (suite *UDPTransportSuite) TestLookup() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	u, n := suite.newTransport()
+	defer func() { assert.NoError(u.Close()) }()
+
+	assert.NoError(suite.nodes[0].Bootstrap(ctx, []*domain.Contact[domain.NodeID224]{suite.nodes[1].Contact()}))
+	assert.NoError(n.Bootstrap(ctx, []*domain.Contact[domain.NodeID224]{suite.nodes[1].Contact()}))
+
+	contactSlice, err := n.Lookup(ctx, suite.nodes[0].ID)
+	assert.NoError(err)
+	assert.Contains(contactSlice, suite.nodes[0].Contact())
+}
+
+func (suite *UDPTransportSuite) TestTimeout() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	// nothing listens on the address of a closed socket
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NoError(err)
+	c := &domain.Contact[domain.NodeID224]{IP: "127.0.0.1", Port: conn.LocalAddr().(*net.UDPAddr).Port}
+	c.ID = domain.HashKey[domain.NodeID224]([]byte(c.Address()))
+	assert.NoError(conn.Close())
+
+	_, err = suite.transports[0].Ping(ctx, c)
+	assert.ErrorContains(err, "no response to udp request")
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = suite.transports[0].Ping(ctx, c)
+	assert.ErrorContains(err, context.Canceled.Error())
+}
+
+func (suite *UDPTransportSuite) TestResponder() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	// contact claims the id of another node than the one answering
+	c := *suite.nodes[1].Contact()
+	c.ID = suite.nodes[0].ID
+
+	_, err := suite.transports[0].Ping(ctx, &c)
+	assert.ErrorIs(err, dht.ErrIdentityMismatch)
+
+	_, err = suite.transports[0].FindNode(ctx, &c, suite.nodes[0].ID)
+	assert.ErrorIs(err, dht.ErrIdentityMismatch)
+
+	_, _, err = suite.transports[0].FindValue(ctx, &c, domain.HashKey[domain.NodeID224]([]byte("key")))
+	assert.ErrorIs(err, dht.ErrIdentityMismatch)
+
+	// unsigned responses are rejected
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NoError(err)
+	defer func() { assert.NoError(conn.Close()) }()
+
+	go func() {
+		buf := make([]byte, 65535)
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		request := &pbv1.Packet{}
+		if proto.Unmarshal(buf[:n], request) != nil {
+			return
+		}
+		b, _ := proto.Marshal(&pbv1.Packet{
+			RequestId: request.GetRequestId(),
+			Body:      &pbv1.Packet_FindNodeResponse{FindNodeResponse: &pbv1.FindNodeResponse{}},
+		})
+		_, _ = conn.WriteToUDP(b, addr)
+	}()
+
+	forged := &domain.Contact[domain.NodeID224]{IP: "127.0.0.1", Port: conn.LocalAddr().(*net.UDPAddr).Port}
+	_, err = suite.transports[0].FindNode(ctx, forged, suite.nodes[0].ID)
+	assert.ErrorIs(err, dht.ErrMissingSender)
+}
+
+func (suite *UDPTransportSuite) TearDownTest() {
+	for _, u := range suite.transports {
+		suite.Assert().NoError(u.Close())
+	}
+}
+
+func TestUDPTransportSuite(t *testing.T) {
+	suite.Run(t, new(UDPTransportSuite))
+}
@@ -6,12 +6,16 @@ import (
 	"os"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/structx/go-dpkg/adapter/logging"
+	"github.com/structx/go-dpkg/adapter/port/dht"
 	"github.com/structx/go-dpkg/adapter/port/http/controller"
 	"github.com/structx/go-dpkg/adapter/setup"
+	"github.com/structx/go-dpkg/domain"
 	"github.com/structx/go-dpkg/util/decode"
 )
 
@@ -64,6 +68,24 @@ func (suite *BundleControllerSuite) TestHealth() {
 	}
 }
 
+func (suite *BundleControllerSuite) TestMetrics() {
+
+	assert := assert.New(suite.T())
+
+	m, err := dht.NewMetrics[domain.NodeID224](prometheus.DefaultRegisterer)
+	assert.NoError(err)
+	defer prometheus.DefaultRegisterer.Unregister(m)
+
+	r := chi.NewRouter()
+	suite.bc.RegisterRoutesV0(r)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Contains(rr.Body.String(), "dht_republish_runs_total")
+}
+
 func TestBundleControllerSuite(t *testing.T) {
 	suite.Run(t, new(BundleControllerSuite))
 }
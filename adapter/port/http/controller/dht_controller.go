@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+// DHT debug controller inspecting a dht node
+type DHT[T domain.NodeID] struct {
+	log       *zap.SugaredLogger
+	inspector domain.Inspector[T]
+}
+
+// interface compliance
+var _ V0 = (*DHT[domain.NodeID224])(nil)
+
+// NewDHT constructor
+func NewDHT[T domain.NodeID](logger *zap.Logger, inspector domain.Inspector[T]) *DHT[T] {
+	return &DHT[T]{
+		log:       logger.Sugar().Named("DHTController"),
+		inspector: inspector,
+	}
+}
+
+// RegisterRoutesV0 create handler from exposed routes
+func (d *DHT[T]) RegisterRoutesV0(r chi.Router) {
+
+	rr := chi.NewRouter()
+
+	rr.Get("/routing", d.RoutingTable)
+	rr.Get("/lookup", d.Lookup)
+	rr.Get("/values", d.Values)
+
+	r.Mount("/debug/dht", rr)
+}
+
+// ContactResponse contact of a dht node
+type ContactResponse struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+}
+
+// EntryResponse routing table entry of a contact
+type EntryResponse struct {
+	ContactResponse
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	RTT       float64   `json:"rtt_ms"`
+	Failures  int       `json:"failures"`
+}
+
+// BucketResponse routing table bucket
+type BucketResponse struct {
+	Index        int             `json:"index"`
+	LastLookup   time.Time       `json:"last_lookup"`
+	Contacts     []EntryResponse `json:"contacts"`
+	Replacements []EntryResponse `json:"replacements"`
+}
+
+// RoutingTableResponse non empty buckets of routing table
+type RoutingTableResponse struct {
+	Contacts int              `json:"contacts"`
+	Buckets  []BucketResponse `json:"buckets"`
+}
+
+// Render routing table response
+func (rtr *RoutingTableResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// QueryResponse query sent during a traced lookup
+type QueryResponse struct {
+	ContactResponse
+	Hop      int               `json:"hop"`
+	Sent     float64           `json:"sent_ms"`
+	RTT      float64           `json:"rtt_ms"`
+	Returned []ContactResponse `json:"returned"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// LookupResponse trace of a lookup
+type LookupResponse struct {
+	Target   string            `json:"target"`
+	Started  time.Time         `json:"started"`
+	Duration float64           `json:"duration_ms"`
+	Hops     int               `json:"hops"`
+	Queries  []QueryResponse   `json:"queries"`
+	Contacts []ContactResponse `json:"contacts"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// Render lookup response
+func (lr *LookupResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// ValueResponse record in local store
+type ValueResponse struct {
+	Key       string    `json:"key"`
+	Size      int       `json:"size"`
+	Seq       uint64    `json:"seq"`
+	Publisher string    `json:"publisher"`
+	Timestamp time.Time `json:"timestamp"`
+	StoredAt  time.Time `json:"stored_at"`
+	Expires   time.Time `json:"expires"`
+	Expired   bool      `json:"expired"`
+}
+
+// ValuesResponse records in local store
+type ValuesResponse struct {
+	Values []ValueResponse `json:"values"`
+}
+
+// Render values response
+func (vr *ValuesResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// RoutingTable handler buckets with contacts, last seen times and rtt
+func (d *DHT[T]) RoutingTable(w http.ResponseWriter, r *http.Request) {
+
+	response := &RoutingTableResponse{Buckets: make([]BucketResponse, 0)}
+	for _, b := range d.inspector.Buckets() {
+		response.Contacts += len(b.Contacts)
+		response.Buckets = append(response.Buckets, BucketResponse{
+			Index:        b.Index,
+			LastLookup:   b.LastLookup,
+			Contacts:     newEntryResponses(b.Contacts),
+			Replacements: newEntryResponses(b.Replacements),
+		})
+	}
+
+	d.render(w, r, response)
+}
+
+// Lookup handler run lookup for ?key= hashed into the id space
+// or ?id= hex encoded node id and return the query trace
+func (d *DHT[T]) Lookup(w http.ResponseWriter, r *http.Request) {
+
+	target, err := lookupTarget[T](r)
+	if err != nil {
+		d.render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	trace, err := d.inspector.TraceLookup(r.Context(), target)
+	if trace == nil {
+		d.log.Errorf("failed to trace lookup %v", err)
+		d.render(w, r, ErrInternalServerError)
+		return
+	}
+
+	response := &LookupResponse{
+		Target:   hex.EncodeToString(domain.Bytes(target)),
+		Started:  trace.Started,
+		Duration: milliseconds(trace.Duration),
+		Queries:  make([]QueryResponse, 0, len(trace.Queries)),
+		Contacts: newContactResponses(trace.Contacts),
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+
+	for _, q := range trace.Queries {
+		qr := QueryResponse{
+			ContactResponse: newContactResponse(&q.Contact),
+			Hop:             q.Hop,
+			Sent:            milliseconds(q.Sent),
+			RTT:             milliseconds(q.RTT),
+			Returned:        newContactResponses(q.Returned),
+		}
+		if q.Err != nil {
+			qr.Error = q.Err.Error()
+		}
+		response.Hops = max(response.Hops, q.Hop)
+		response.Queries = append(response.Queries, qr)
+	}
+
+	d.render(w, r, response)
+}
+
+// Values handler locally stored records with expiry
+func (d *DHT[T]) Values(w http.ResponseWriter, r *http.Request) {
+
+	records, err := d.inspector.Records(r.Context())
+	if err != nil {
+		d.log.Errorf("failed to read records %v", err)
+		d.render(w, r, ErrInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	response := &ValuesResponse{Values: make([]ValueResponse, 0, len(records))}
+	for _, sr := range records {
+		response.Values = append(response.Values, ValueResponse{
+			Key:       hex.EncodeToString(domain.Bytes(sr.Key)),
+			Size:      len(sr.Value),
+			Seq:       sr.Seq,
+			Publisher: hex.EncodeToString(domain.Bytes(sr.Publisher)),
+			Timestamp: sr.Timestamp,
+			StoredAt:  sr.StoredAt,
+			Expires:   sr.Expires,
+			Expired:   !sr.Expires.After(now),
+		})
+	}
+
+	d.render(w, r, response)
+}
+
+func (d *DHT[T]) render(w http.ResponseWriter, r *http.Request, v render.Renderer) {
+	if err := render.Render(w, r, v); err != nil {
+		d.log.Errorf("failed to render response %v", err)
+		http.Error(w, "unable to write response", http.StatusInternalServerError)
+	}
+}
+
+func lookupTarget[T domain.NodeID](r *http.Request) (T, error) {
+
+	var zero T
+
+	if key := r.URL.Query().Get("key"); key != "" {
+		return domain.HashKey[T]([]byte(key)), nil
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return zero, errors.New("missing key or id query parameter")
+	}
+
+	b, err := hex.DecodeString(id)
+	if err != nil {
+		return zero, fmt.Errorf("invalid id %v", err)
+	}
+
+	return domain.NodeIDFromBytes[T](b)
+}
+
+func newContactResponse[T domain.NodeID](c *domain.Contact[T]) ContactResponse {
+	return ContactResponse{
+		ID:      hex.EncodeToString(domain.Bytes(c.ID)),
+		Address: c.Address(),
+	}
+}
+
+func newContactResponses[T domain.NodeID](contactSlice []*domain.Contact[T]) []ContactResponse {
+	responses := make([]ContactResponse, 0, len(contactSlice))
+	for _, c := range contactSlice {
+		responses = append(responses, newContactResponse(c))
+	}
+	return responses
+}
+
+func newEntryResponses[T domain.NodeID](infos []domain.ContactInfo[T]) []EntryResponse {
+	responses := make([]EntryResponse, 0, len(infos))
+	for _, info := range infos {
+		responses = append(responses, EntryResponse{
+			ContactResponse: newContactResponse(&info.Contact),
+			FirstSeen:       info.FirstSeen,
+			LastSeen:        info.LastSeen,
+			RTT:             milliseconds(info.RTT),
+			Failures:        info.Failures,
+		})
+	}
+	return responses
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
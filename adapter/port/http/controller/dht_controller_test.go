@@ -0,0 +1,100 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/structx/go-dpkg/adapter/port/http/controller"
+	"github.com/structx/go-dpkg/domain"
+)
+
+type stubInspector struct {
+	contact domain.Contact[domain.NodeID224]
+	target  domain.NodeID224
+}
+
+func (s *stubInspector) Buckets() []domain.BucketInfo[domain.NodeID224] {
+	return []domain.BucketInfo[domain.NodeID224]{{
+		Index:    3,
+		Contacts: []domain.ContactInfo[domain.NodeID224]{{Contact: s.contact, RTT: time.Millisecond * 5}},
+	}}
+}
+
+func (s *stubInspector) TraceLookup(_ context.Context, target domain.NodeID224) (*domain.LookupTrace[domain.NodeID224], error) {
+	s.target = target
+	return &domain.LookupTrace[domain.NodeID224]{
+		Target: target,
+		Queries: []domain.QueryTrace[domain.NodeID224]{
+			{Contact: s.contact, Hop: 1, RTT: time.Millisecond, Returned: []*domain.Contact[domain.NodeID224]{&s.contact}},
+			{Contact: s.contact, Hop: 2, Err: errors.New("timeout")},
+		},
+		Contacts: []*domain.Contact[domain.NodeID224]{&s.contact},
+	}, nil
+}
+
+func (s *stubInspector) Records(_ context.Context) ([]*domain.StoredRecord[domain.NodeID224], error) {
+	return []*domain.StoredRecord[domain.NodeID224]{{
+		Record:  domain.Record[domain.NodeID224]{Key: s.target, Value: []byte("value")},
+		Expires: time.Now().Add(-time.Second),
+	}}, nil
+}
+
+func Test_DHTController(t *testing.T) {
+
+	assert := assert.New(t)
+
+	inspector := &stubInspector{
+		contact: domain.Contact[domain.NodeID224]{IP: "10.0.0.1", Port: 50051, ID: domain.HashKey[domain.NodeID224]([]byte("contact"))},
+	}
+
+	r := chi.NewRouter()
+	controller.NewDHT[domain.NodeID224](zap.NewNop(), inspector).RegisterRoutesV0(r)
+
+	get := func(path string, v any) int {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code == http.StatusOK {
+			assert.NoError(json.Unmarshal(rr.Body.Bytes(), v))
+		}
+		return rr.Code
+	}
+
+	var routing controller.RoutingTableResponse
+	assert.Equal(http.StatusOK, get("/debug/dht/routing", &routing))
+	assert.Equal(1, routing.Contacts)
+	assert.Len(routing.Buckets, 1)
+	assert.Equal(3, routing.Buckets[0].Index)
+	assert.Equal("10.0.0.1:50051", routing.Buckets[0].Contacts[0].Address)
+	assert.Equal(5.0, routing.Buckets[0].Contacts[0].RTT)
+
+	var lookup controller.LookupResponse
+	assert.Equal(http.StatusOK, get("/debug/dht/lookup?key=content", &lookup))
+	assert.Equal(domain.HashKey[domain.NodeID224]([]byte("content")), inspector.target)
+	assert.Equal(2, lookup.Hops)
+	assert.Len(lookup.Queries, 2)
+	assert.Len(lookup.Queries[0].Returned, 1)
+	assert.Equal("timeout", lookup.Queries[1].Error)
+	assert.Equal(hex.EncodeToString(inspector.contact.ID[:]), lookup.Contacts[0].ID)
+
+	assert.Equal(http.StatusOK, get("/debug/dht/lookup?id="+hex.EncodeToString(inspector.contact.ID[:]), &lookup))
+	assert.Equal(inspector.contact.ID, inspector.target)
+
+	assert.Equal(http.StatusBadRequest, get("/debug/dht/lookup", nil))
+	assert.Equal(http.StatusBadRequest, get("/debug/dht/lookup?id=abcd", nil))
+
+	var values controller.ValuesResponse
+	assert.Equal(http.StatusOK, get("/debug/dht/values", &values))
+	assert.Len(values.Values, 1)
+	assert.Equal(5, values.Values[0].Size)
+	assert.True(values.Values[0].Expired)
+}
@@ -0,0 +1,446 @@
+// Package registry service discovery backed by the dht
+package registry
+
+import (
+	"cmp"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+const (
+	// DefaultServiceTTL lifetime of registrations without ttl
+	DefaultServiceTTL = time.Second * 30
+	// maxProviders registries of a service type queried during resolution
+	maxProviders = 64
+	// resolveInterval age of resolved instances reused by Pick
+	resolveInterval = time.Second * 5
+	// minBackoff time an instance is avoided after its first reported failure
+	minBackoff = time.Second
+	// maxBackoff longest time an instance is avoided after repeated failures
+	maxBackoff = time.Minute
+	// checkTimeout maximum time of a health check
+	checkTimeout = time.Second * 5
+)
+
+type registration struct {
+	instance *domain.ServiceInstance
+	check    domain.HealthCheck
+	cancel   context.CancelFunc
+}
+
+type resolution struct {
+	instances []*domain.ServiceInstance
+	at        time.Time
+	// next round robin position
+	next int
+}
+
+type health struct {
+	failures int
+	until    time.Time
+}
+
+// Registry service registry storing registrations as signed records in the dht
+//
+// the registry announces itself as provider of every service type it
+// registers instances of and stores the instances of each type in a
+// mutable record of the node key salted with the type. records are
+// renewed by heartbeats, resolvers look up the record of every provider
+// by its public key and ignore instances past their ttl. registration
+// changes are published on the joined network topic
+type Registry[T domain.NodeID] struct {
+	log    *zap.SugaredLogger
+	dht    domain.DHT[T]
+	key    ed25519.PrivateKey
+	broker domain.MessageBroker
+
+	mtx           sync.Mutex
+	registrations map[domain.ServiceType]map[string]*registration
+	resolved      map[domain.ServiceType]*resolution
+	health        map[string]*health
+
+	// pmtx serializes record updates so versions are written in order
+	pmtx sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// interface compliance
+var _ domain.ServiceRegistry = (*Registry[domain.NodeID224])(nil)
+
+// New constructor, key must be the key of the dht node. broker may
+// be nil to skip publishing registration changes
+func New[T domain.NodeID](logger *zap.Logger, dht domain.DHT[T], key ed25519.PrivateKey, broker domain.MessageBroker) (*Registry[T], error) {
+
+	if domain.NodeIDFromPublicKey[T](key.Public().(ed25519.PublicKey)) != dht.Contact().ID {
+		return nil, errors.New("registry key is not the key of the dht node")
+	}
+
+	r := &Registry[T]{
+		log:           logger.Sugar().Named("ServiceRegistry"),
+		dht:           dht,
+		key:           key,
+		broker:        broker,
+		registrations: make(map[domain.ServiceType]map[string]*registration),
+		resolved:      make(map[domain.ServiceType]*resolution),
+		health:        make(map[string]*health),
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+
+	return r, nil
+}
+
+// Register announce instance and renew it every third of its ttl until
+// deregistered, instances without health check are always healthy
+func (r *Registry[T]) Register(ctx context.Context, instance *domain.ServiceInstance, check domain.HealthCheck) error {
+
+	if instance.Type == "" || instance.ID == "" {
+		return errors.New("service instance requires type and id")
+	}
+
+	i := *instance
+	i.Endpoints = slices.Clone(instance.Endpoints)
+	if i.TTL <= 0 {
+		i.TTL = DefaultServiceTTL
+	}
+	i.Healthy = healthy(ctx, check)
+	i.Expires = time.Now().Add(i.TTL)
+
+	r.mtx.Lock()
+	if _, ok := r.registrations[i.Type][i.ID]; ok {
+		r.mtx.Unlock()
+		return fmt.Errorf("service instance %s of %s already registered", i.ID, i.Type)
+	}
+	if r.registrations[i.Type] == nil {
+		r.registrations[i.Type] = make(map[string]*registration)
+	}
+	hctx, cancel := context.WithCancel(r.ctx)
+	reg := &registration{instance: &i, check: check, cancel: cancel}
+	r.registrations[i.Type][i.ID] = reg
+	r.mtx.Unlock()
+
+	if err := r.dht.Provide(ctx, serviceKey(i.Type)); err != nil {
+		r.remove(i.Type, i.ID)
+		return fmt.Errorf("failed to announce service type %v", err)
+	}
+
+	if err := r.publish(ctx, i.Type); err != nil {
+		r.remove(i.Type, i.ID)
+		return err
+	}
+
+	r.notify(ctx, &i, domain.Registered)
+
+	r.wg.Add(1)
+	go r.heartbeat(hctx, reg)
+
+	return nil
+}
+
+// Deregister stop heartbeats and withdraw instance, the provider
+// announcement of the registry expires on its own
+func (r *Registry[T]) Deregister(ctx context.Context, serviceType domain.ServiceType, id string) error {
+
+	reg, ok := r.remove(serviceType, id)
+	if !ok {
+		return fmt.Errorf("service instance %s of %s is not registered", id, serviceType)
+	}
+
+	if err := r.publish(ctx, serviceType); err != nil {
+		return err
+	}
+
+	r.notify(ctx, reg.instance, domain.Deregistered)
+
+	return nil
+}
+
+// Resolve live instances of service type registered with any registry
+func (r *Registry[T]) Resolve(ctx context.Context, serviceType domain.ServiceType) ([]*domain.ServiceInstance, error) {
+
+	providers, err := r.dht.FindProviders(ctx, serviceKey(serviceType), maxProviders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find registries of %s %v", serviceType, err)
+	}
+
+	now := time.Now()
+	seen := make(map[T]struct{}, len(providers))
+
+	var instances []*domain.ServiceInstance
+	for _, p := range providers {
+
+		owner := p.Contact.ID
+		if _, ok := seen[owner]; ok {
+			continue
+		}
+		seen[owner] = struct{}{}
+
+		if len(p.PublicKey) != ed25519.PublicKeySize || domain.NodeIDFromPublicKey[T](p.PublicKey) != owner {
+			r.log.Debugf("ignore registry of %s %x without matching public key", serviceType, domain.Bytes(owner))
+			continue
+		}
+
+		record, err := r.dht.GetMutable(ctx, p.PublicKey, salt(serviceType), domain.GetOptions{})
+		if err != nil {
+			r.log.Debugf("failed to get registrations of %s from %x %v", serviceType, domain.Bytes(owner), err)
+			continue
+		}
+
+		var registered []*domain.ServiceInstance
+		if err := json.Unmarshal(record.Value, &registered); err != nil {
+			r.log.Warnf("ignore registrations of %s from %x %v", serviceType, domain.Bytes(owner), err)
+			continue
+		}
+
+		for _, i := range registered {
+			if i.Type == serviceType && i.Expires.After(now) {
+				instances = append(instances, i)
+			}
+		}
+	}
+
+	slices.SortFunc(instances, func(a, b *domain.ServiceInstance) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	r.mtx.Lock()
+	if res, ok := r.resolved[serviceType]; ok {
+		res.instances, res.at = instances, now
+	} else {
+		r.resolved[serviceType] = &resolution{instances: instances, at: now}
+	}
+	r.mtx.Unlock()
+
+	return instances, nil
+}
+
+// Pick healthy instance of service type in round robin order, instances
+// recently reported as failing are skipped unless no other is healthy
+func (r *Registry[T]) Pick(ctx context.Context, serviceType domain.ServiceType) (*domain.ServiceInstance, error) {
+
+	r.mtx.Lock()
+	res, ok := r.resolved[serviceType]
+	fresh := ok && time.Since(res.at) < resolveInterval
+	r.mtx.Unlock()
+
+	if !fresh {
+		if _, err := r.Resolve(ctx, serviceType); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	res = r.resolved[serviceType]
+
+	now := time.Now()
+	var candidates, preferred []*domain.ServiceInstance
+	for _, i := range res.instances {
+		if !i.Healthy || !i.Expires.After(now) {
+			continue
+		}
+		candidates = append(candidates, i)
+		if h, ok := r.health[instanceKey(i)]; !ok || !now.Before(h.until) {
+			preferred = append(preferred, i)
+		}
+	}
+
+	switch {
+	case len(res.instances) == 0:
+		return nil, fmt.Errorf("%w of %s", domain.ErrNoInstances, serviceType)
+	case len(candidates) == 0:
+		return nil, fmt.Errorf("%w of %s", domain.ErrNoHealthyInstances, serviceType)
+	case len(preferred) == 0:
+		// every healthy instance failed recently, try them anyway
+		preferred = candidates
+	}
+
+	i := preferred[res.next%len(preferred)]
+	res.next++
+
+	return i, nil
+}
+
+// Report outcome of a request to instance, each consecutive failure
+// doubles the time the instance is avoided by Pick
+func (r *Registry[T]) Report(instance *domain.ServiceInstance, err error) {
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	k := instanceKey(instance)
+	if err == nil {
+		delete(r.health, k)
+		return
+	}
+
+	h, ok := r.health[k]
+	if !ok {
+		h = &health{}
+		r.health[k] = h
+	}
+
+	backoff := min(minBackoff<<min(h.failures, 16), maxBackoff)
+	h.failures++
+	h.until = time.Now().Add(backoff)
+}
+
+// Close stop heartbeats, registrations expire after their ttl
+func (r *Registry[T]) Close() error {
+	r.cancel()
+	r.wg.Wait()
+	return nil
+}
+
+// heartbeat renew registration and publish health changes until cancelled
+func (r *Registry[T]) heartbeat(ctx context.Context, reg *registration) {
+	defer r.wg.Done()
+
+	r.mtx.Lock()
+	ttl := reg.instance.TTL
+	r.mtx.Unlock()
+
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+
+			ok := healthy(ctx, reg.check)
+
+			r.mtx.Lock()
+			changed := reg.instance.Healthy != ok
+			reg.instance.Healthy = ok
+			reg.instance.Expires = time.Now().Add(ttl)
+			i := *reg.instance
+			r.mtx.Unlock()
+
+			if err := r.publish(ctx, i.Type); err != nil {
+				if ctx.Err() != nil {
+					// deregistered or closed during renewal
+					return
+				}
+				r.log.Errorf("failed to renew service instance %s of %s %v", i.ID, i.Type, err)
+				continue
+			}
+
+			if changed && ok {
+				r.notify(ctx, &i, domain.Healthy)
+			} else if changed {
+				r.notify(ctx, &i, domain.Unhealthy)
+			}
+		}
+	}
+}
+
+// publish mutable record of the instances of service type registered locally
+func (r *Registry[T]) publish(ctx context.Context, serviceType domain.ServiceType) error {
+
+	r.pmtx.Lock()
+	defer r.pmtx.Unlock()
+
+	r.mtx.Lock()
+	instances := make([]*domain.ServiceInstance, 0, len(r.registrations[serviceType]))
+	for _, reg := range r.registrations[serviceType] {
+		i := *reg.instance
+		instances = append(instances, &i)
+	}
+	r.mtx.Unlock()
+
+	b, err := json.Marshal(instances)
+	if err != nil {
+		return fmt.Errorf("failed to marshal registrations %v", err)
+	}
+
+	_, err = r.dht.PutMutable(ctx, r.key, salt(serviceType), b, domain.PutOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to store registrations of %s %v", serviceType, err)
+	}
+
+	return nil
+}
+
+// notify publish registration change on the joined network topic
+func (r *Registry[T]) notify(ctx context.Context, instance *domain.ServiceInstance, event domain.RegistrationEvent) {
+
+	if r.broker == nil {
+		return
+	}
+
+	msg := domain.NewJoinedNetwork(instance.ID, instance.Type)
+	msg.Event = event
+	msg.Endpoints = instance.Endpoints
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		r.log.Errorf("failed to marshal registration event %v", err)
+		return
+	}
+
+	if err := r.broker.Publish(ctx, domain.JoinedRaftV1.String(), b); err != nil {
+		r.log.Warnf("failed to publish registration event %v", err)
+	}
+}
+
+// remove registration and stop its heartbeat
+func (r *Registry[T]) remove(serviceType domain.ServiceType, id string) (*registration, bool) {
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	reg, ok := r.registrations[serviceType][id]
+	if !ok {
+		return nil, false
+	}
+
+	reg.cancel()
+	delete(r.registrations[serviceType], id)
+	if len(r.registrations[serviceType]) == 0 {
+		delete(r.registrations, serviceType)
+	}
+
+	return reg, true
+}
+
+// healthy run health check with a timeout, nil checks always pass
+func healthy(ctx context.Context, check domain.HealthCheck) bool {
+
+	if check == nil {
+		return true
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	return check(timeout) == nil
+}
+
+// serviceKey provider key of registries of service type
+func serviceKey(serviceType domain.ServiceType) []byte {
+	return []byte("service/" + string(serviceType))
+}
+
+// salt of the mutable record of the registrations of service type
+func salt(serviceType domain.ServiceType) []byte {
+	return []byte(serviceType)
+}
+
+func instanceKey(i *domain.ServiceInstance) string {
+	return string(i.Type) + "/" + i.ID
+}
@@ -0,0 +1,313 @@
+package registry_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/structx/go-dpkg/adapter/port/dht"
+	"github.com/structx/go-dpkg/adapter/registry"
+	"github.com/structx/go-dpkg/adapter/setup"
+	"github.com/structx/go-dpkg/domain"
+	kademlia "github.com/structx/go-dpkg/structs/dht"
+	"github.com/structx/go-dpkg/util/decode"
+)
+
+func init() {
+	_ = os.Setenv("DSERVICE_CONFIG", "./testfiles/registry.test.hcl")
+}
+
+// stubBroker records published registration events
+type stubBroker struct {
+	domain.MessageBroker
+	mtx    sync.Mutex
+	events []*domain.JoinedNetwork
+}
+
+func (b *stubBroker) Publish(_ context.Context, topic string, payload []byte) error {
+
+	if topic != domain.JoinedRaftV1.String() {
+		return errors.New("unexpected topic")
+	}
+
+	var msg domain.JoinedNetwork
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return err
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.events = append(b.events, &msg)
+	return nil
+}
+
+func (b *stubBroker) received(event domain.RegistrationEvent, id string) bool {
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for _, msg := range b.events {
+		if msg.Event == event && msg.ServiceID == id {
+			return true
+		}
+	}
+	return false
+}
+
+type RegistrySuite struct {
+	suite.Suite
+	broker     *stubBroker
+	transports []*dht.UDPTransport[domain.NodeID224]
+	nodes      []*kademlia.Node[domain.NodeID224]
+	keys       []ed25519.PrivateKey
+	registries []*registry.Registry[domain.NodeID224]
+}
+
+func (suite *RegistrySuite) SetupTest() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	cfg := setup.New()
+	assert.NoError(decode.ConfigFromEnv(cfg))
+
+	suite.broker = &stubBroker{}
+	suite.transports, suite.nodes, suite.keys, suite.registries = nil, nil, nil, nil
+
+	for range 3 {
+
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		assert.NoError(err)
+
+		pub, key, err := ed25519.GenerateKey(nil)
+		assert.NoError(err)
+
+		u, err := dht.NewUDPTransport[domain.NodeID224](zap.NewNop(), cfg, key, conn)
+		assert.NoError(err)
+
+		n := kademlia.NewNode[domain.NodeID224](ctx, "127.0.0.1", conn.LocalAddr().(*net.UDPAddr).Port, domain.DefaultReplicationFactor, pub,
+			kademlia.WithTransport[domain.NodeID224](u))
+		u.Attach(n)
+
+		go func() { _ = u.Serve() }()
+
+		r, err := registry.New[domain.NodeID224](zap.NewNop(), n, key, suite.broker)
+		assert.NoError(err)
+
+		suite.transports = append(suite.transports, u)
+		suite.nodes = append(suite.nodes, n)
+		suite.keys = append(suite.keys, key)
+		suite.registries = append(suite.registries, r)
+	}
+
+	for _, n := range suite.nodes[1:] {
+		assert.NoError(n.Bootstrap(ctx, []*domain.Contact[domain.NodeID224]{suite.nodes[0].Contact()}))
+	}
+	assert.NoError(suite.nodes[0].Bootstrap(ctx, []*domain.Contact[domain.NodeID224]{suite.nodes[1].Contact()}))
+
+	// registries only sign with the key of their node
+	_, key, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+	_, err = registry.New[domain.NodeID224](zap.NewNop(), suite.nodes[0], key, nil)
+	assert.Error(err)
+}
+
+func (suite *RegistrySuite) TestResolve() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	assert.NoError(suite.registries[0].Register(ctx, &domain.ServiceInstance{
+		Type:      domain.Mora,
+		ID:        "a",
+		Endpoints: []string{"127.0.0.1:9000"},
+		Metadata:  map[string]string{"zone": "a"},
+	}, nil))
+	assert.NoError(suite.registries[1].Register(ctx, &domain.ServiceInstance{
+		Type:      domain.Mora,
+		ID:        "b",
+		Endpoints: []string{"127.0.0.1:9001"},
+	}, func(context.Context) error { return errors.New("unavailable") }))
+
+	assert.Error(suite.registries[0].Register(ctx, &domain.ServiceInstance{Type: domain.Mora, ID: "a"}, nil))
+
+	instances, err := suite.registries[2].Resolve(ctx, domain.Mora)
+	assert.NoError(err)
+	assert.Len(instances, 2)
+	assert.Equal("a", instances[0].ID)
+	assert.Equal([]string{"127.0.0.1:9000"}, instances[0].Endpoints)
+	assert.Equal("a", instances[0].Metadata["zone"])
+	assert.True(instances[0].Healthy)
+	assert.False(instances[1].Healthy)
+
+	// unhealthy instances are never picked
+	for range 3 {
+		i, err := suite.registries[2].Pick(ctx, domain.Mora)
+		assert.NoError(err)
+		assert.Equal("a", i.ID)
+	}
+
+	assert.True(suite.broker.received(domain.Registered, "a"))
+	assert.True(suite.broker.received(domain.Registered, "b"))
+
+	_, err = suite.registries[2].Pick(ctx, domain.ServiceType("unknown"))
+	assert.ErrorIs(err, domain.ErrNoInstances)
+}
+
+func (suite *RegistrySuite) TestPick() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	for i, id := range []string{"a", "b"} {
+		assert.NoError(suite.registries[i].Register(ctx, &domain.ServiceInstance{Type: domain.Mora, ID: id}, nil))
+	}
+
+	pick := func() string {
+		i, err := suite.registries[2].Pick(ctx, domain.Mora)
+		assert.NoError(err)
+		return i.ID
+	}
+
+	// round robin between healthy instances
+	first := pick()
+	assert.NotEqual(first, pick())
+	assert.Equal(first, pick())
+
+	instances, err := suite.registries[2].Resolve(ctx, domain.Mora)
+	assert.NoError(err)
+	assert.Len(instances, 2)
+
+	// failing instances are avoided
+	suite.registries[2].Report(instances[0], errors.New("connection refused"))
+	for range 3 {
+		assert.Equal("b", pick())
+	}
+
+	// unless no other is healthy
+	suite.registries[2].Report(instances[1], errors.New("connection refused"))
+	seen := map[string]bool{pick(): true, pick(): true}
+	assert.Len(seen, 2)
+
+	suite.registries[2].Report(instances[1], nil)
+	for range 3 {
+		assert.Equal("b", pick())
+	}
+}
+
+func (suite *RegistrySuite) TestHeartbeat() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	var failing atomic.Bool
+	check := func(context.Context) error {
+		if failing.Load() {
+			return errors.New("unavailable")
+		}
+		return nil
+	}
+
+	assert.NoError(suite.registries[0].Register(ctx, &domain.ServiceInstance{Type: domain.Mora, ID: "a", TTL: time.Millisecond * 300}, check))
+	assert.NoError(suite.registries[1].Register(ctx, &domain.ServiceInstance{Type: domain.Mora, ID: "b", TTL: time.Millisecond * 300}, nil))
+
+	// heartbeats renew registrations beyond their ttl
+	time.Sleep(time.Millisecond * 500)
+	instances, err := suite.registries[2].Resolve(ctx, domain.Mora)
+	assert.NoError(err)
+	assert.Len(instances, 2)
+
+	// health changes are published with the next heartbeat
+	failing.Store(true)
+	assert.Eventually(func() bool { return suite.broker.received(domain.Unhealthy, "a") }, time.Second*2, time.Millisecond*20)
+
+	instances, err = suite.registries[2].Resolve(ctx, domain.Mora)
+	assert.NoError(err)
+	assert.Len(instances, 2)
+	assert.False(instances[0].Healthy)
+
+	failing.Store(false)
+	assert.Eventually(func() bool { return suite.broker.received(domain.Healthy, "a") }, time.Second*2, time.Millisecond*20)
+
+	// registrations expire once heartbeats stop
+	assert.NoError(suite.registries[1].Close())
+	assert.Eventually(func() bool {
+		instances, err := suite.registries[2].Resolve(ctx, domain.Mora)
+		return err == nil && len(instances) == 1 && instances[0].ID == "a"
+	}, time.Second*2, time.Millisecond*50)
+}
+
+func (suite *RegistrySuite) TestDeregister() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	assert.NoError(suite.registries[0].Register(ctx, &domain.ServiceInstance{Type: domain.Mora, ID: "a"}, nil))
+	assert.NoError(suite.registries[0].Deregister(ctx, domain.Mora, "a"))
+	assert.Error(suite.registries[0].Deregister(ctx, domain.Mora, "a"))
+
+	instances, err := suite.registries[2].Resolve(ctx, domain.Mora)
+	assert.NoError(err)
+	assert.Empty(instances)
+
+	assert.True(suite.broker.received(domain.Deregistered, "a"))
+}
+
+func (suite *RegistrySuite) TestForgedRegistration() {
+
+	assert := suite.Assert()
+	ctx := context.TODO()
+
+	assert.NoError(suite.registries[0].Register(ctx, &domain.ServiceInstance{Type: domain.Mora, ID: "a"}, nil))
+
+	// another node squats the registrations of node 0 with the highest version
+	forged, err := json.Marshal([]*domain.ServiceInstance{{Type: domain.Mora, ID: "forged", Healthy: true, Expires: time.Now().Add(time.Hour)}})
+	assert.NoError(err)
+
+	record := &domain.Record[domain.NodeID224]{
+		Key:       domain.MutableKey[domain.NodeID224](suite.keys[0].Public().(ed25519.PublicKey), []byte(domain.Mora)),
+		Value:     forged,
+		Publisher: suite.nodes[2].ID,
+		Seq:       math.MaxUint64,
+		Timestamp: time.Now(),
+	}
+	for _, n := range suite.nodes {
+		assert.ErrorIs(suite.transports[2].Store(ctx, n.Contact(), record), domain.ErrRecordRejected)
+	}
+
+	instances, err := suite.registries[1].Resolve(ctx, domain.Mora)
+	assert.NoError(err)
+	assert.Len(instances, 1)
+	assert.Equal("a", instances[0].ID)
+
+	// the owner still updates its registrations
+	assert.NoError(suite.registries[0].Register(ctx, &domain.ServiceInstance{Type: domain.Mora, ID: "b"}, nil))
+
+	instances, err = suite.registries[1].Resolve(ctx, domain.Mora)
+	assert.NoError(err)
+	assert.Len(instances, 2)
+}
+
+func (suite *RegistrySuite) TearDownTest() {
+	for _, r := range suite.registries {
+		suite.Assert().NoError(r.Close())
+	}
+	for _, u := range suite.transports {
+		suite.Assert().NoError(u.Close())
+	}
+}
+
+func TestRegistrySuite(t *testing.T) {
+	suite.Run(t, new(RegistrySuite))
+}
@@ -0,0 +1,27 @@
+
+server {
+    bind_addr = "0.0.0.0"
+    default_timeout = 15
+    ports {
+        http = 8080
+        grpc = 50051
+    }
+}
+
+logger {
+    log_path = "./testfiles/log/test.log"
+    log_level = "DEBUG"
+    raft_log_path = "./testfiles/log"
+}
+
+dht {
+    bind_addr = "127.0.0.1"
+
+    ports {
+        grpc = 50052
+    }
+
+    timeouts {
+        request = 200
+    }
+}
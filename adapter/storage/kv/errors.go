@@ -1,6 +1,10 @@
 package kv
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/structx/go-dpkg/domain"
+)
 
 // ErrNotFound key not found error
 type ErrNotFound struct {
@@ -11,3 +15,8 @@ type ErrNotFound struct {
 func (notFound *ErrNotFound) Error() string {
 	return fmt.Sprintf("error key %s not found", string(notFound.Key))
 }
+
+// Is not found error matches domain.ErrKeyNotFound
+func (notFound *ErrNotFound) Is(target error) bool {
+	return target == domain.ErrKeyNotFound
+}
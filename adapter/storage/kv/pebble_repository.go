@@ -2,6 +2,7 @@
 package kv
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -48,6 +49,11 @@ func (p *PebbleDB) Put(key, value []byte) error {
 	return p.db.Set(key, value, pebble.Sync)
 }
 
+// Delete key/value pair
+func (p *PebbleDB) Delete(key []byte) error {
+	return p.db.Delete(key, pebble.Sync)
+}
+
 // Get value by key
 func (p *PebbleDB) Get(key []byte) ([]byte, error) {
 
@@ -58,12 +64,15 @@ func (p *PebbleDB) Get(key []byte) ([]byte, error) {
 		return []byte{}, fmt.Errorf("failed to get key value %v", err)
 	}
 
+	// value is only valid until closer is closed
+	value := bytes.Clone(v)
+
 	err = closer.Close()
 	if err != nil {
 		return []byte{}, fmt.Errorf("failed to close closer %v", err)
 	}
 
-	return v, nil
+	return value, nil
 }
 
 // Close database connection
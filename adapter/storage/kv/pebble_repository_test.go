@@ -72,6 +72,24 @@ func (suite *PebbleDBSuite) TestGet() {
 	suite.TeardownTest()
 }
 
+func (suite *PebbleDBSuite) TestDelete() {
+
+	assert := suite.Assert()
+
+	key := []byte("delete")
+
+	err := suite.db.Put(key, []byte("value"))
+	assert.NoError(err)
+
+	assert.NoError(suite.db.Delete(key))
+
+	_, err = suite.db.Get(key)
+	assert.ErrorAs(err, new(*kv.ErrNotFound))
+	assert.ErrorIs(err, domain.ErrKeyNotFound)
+
+	suite.TeardownTest()
+}
+
 func (suite *PebbleDBSuite) TestIterator() {
 
 	assert := suite.Assert()
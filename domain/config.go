@@ -42,13 +42,52 @@ type AccessControl struct {
 	ServerAddr string `hcl:"server_addr"`
 }
 
+// Timeouts distributed hash table rpc timeouts in milliseconds
+type Timeouts struct {
+	Dial    int64 `hcl:"dial,optional"`
+	Request int64 `hcl:"request,optional"`
+	Idle    int64 `hcl:"idle,optional"`
+}
+
+// RateLimit inbound distributed hash table rpcs per second and burst,
+// zero rates disable the limit
+type RateLimit struct {
+	SenderRate  float64 `hcl:"sender_rate,optional"`
+	SenderBurst int     `hcl:"sender_burst,optional"`
+	IPRate      float64 `hcl:"ip_rate,optional"`
+	IPBurst     int     `hcl:"ip_burst,optional"`
+}
+
+// Reputation distributed hash table peer reputation
+type Reputation struct {
+	// BanThreshold score at which a peer is banned, zero for the default
+	BanThreshold float64 `hcl:"ban_threshold,optional"`
+	// BanDuration seconds a banned peer is rejected, zero for the default
+	BanDuration int64 `hcl:"ban_duration,optional"`
+}
+
 // DistributedHashTable configuration
 type DistributedHashTable struct {
 	BindAddr      string  `hcl:"bind_addr"`
 	AdvertiseAddr *string `hcl:"advertise_addr"`
 	Ports         *struct {
 		GRPC int `hcl:"grpc"`
+		// UDP port of the udp transport, zero disables it
+		UDP int `hcl:"udp,optional"`
 	} `hcl:"ports,block"`
+	Timeouts *Timeouts `hcl:"timeouts,block"`
+	// DataDir directory node key is persisted in
+	DataDir string `hcl:"data_dir,optional"`
+	// Seeds host:port addresses of nodes used to join the network
+	Seeds []string `hcl:"seeds,optional"`
+	// RefreshInterval seconds after which buckets without lookups are refreshed
+	RefreshInterval int64 `hcl:"refresh_interval,optional"`
+	// MaxPacketSize bytes of the largest udp datagram sent, zero for the default
+	MaxPacketSize int `hcl:"max_packet_size,optional"`
+	// RateLimit inbound rpcs per sender id and ip, nil disables limits
+	RateLimit *RateLimit `hcl:"rate_limit,block"`
+	// Reputation peer scoring and bans, nil uses the defaults
+	Reputation *Reputation `hcl:"reputation,block"`
 }
 
 // Config service configuration interface
@@ -1,8 +1,14 @@
 package domain
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"net"
+	"time"
 
 	"github.com/structx/go-dpkg/util/encode"
 )
@@ -18,6 +24,23 @@ const (
 	// Concurrent number of threads to use for connecting to
 	// other dht nodes
 	Concurrent = 3
+	// MaxSaltSize maximum salt of a mutable record
+	MaxSaltSize = 64
+)
+
+var (
+	// ErrRecordNotFound no record stored under key
+	ErrRecordNotFound = errors.New("value not found")
+	// ErrRecordExpired record has already expired
+	ErrRecordExpired = errors.New("record expired")
+	// ErrRecordOutdated newer record already stored under key
+	ErrRecordOutdated = errors.New("newer record already stored")
+	// ErrRecordRejected node refused to store record
+	ErrRecordRejected = errors.New("record rejected")
+	// ErrRecordSignature mutable record is not signed by the owner of its key
+	ErrRecordSignature = errors.New("invalid record signature")
+	// ErrQuorum fewer replicas than the quorum responded
+	ErrQuorum = errors.New("quorum not reached")
 )
 
 // NodeID224 224 bit sha3 hash
@@ -29,54 +52,382 @@ type NodeID384 [48]byte // 384 bits / 8 bits/byte = 48 bytes
 // NodeID512 512 bit sha3 hash
 type NodeID512 [64]byte // 512 bits / 8 bits/byte = 64 bytes
 
-// Distance224 between two nodes
-type Distance224 NodeID224
+// NodeID constraint satisfied by node ids of every supported width,
+// hashes of each width use the sha3 function of the same digest size
+type NodeID interface {
+	NodeID224 | NodeID256 | NodeID384 | NodeID512
+}
 
-// XOR based Distance between two nodes
-func (d Distance224) XOR(n NodeID224) NodeID224 {
-	// perform bitwise XOR operation between node IDs
-	result := NodeID224{}
-	for i := range d {
-		result[i] = d[i] ^ n[i]
+// Distance xor distance between two node ids
+func Distance[T NodeID](a, b T) T {
+	var result T
+	for i := 0; i < len(result); i++ {
+		result[i] = a[i] ^ b[i]
 	}
 	return result
 }
 
+// Compare node ids or distances as big endian integers
+func Compare[T NodeID](a, b T) int {
+	for i := 0; i < len(a); i++ {
+		if a[i] < b[i] {
+			return -1
+		} else if a[i] > b[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Bits width of node id in bits
+func Bits[T NodeID]() int {
+	var nodeID T
+	return len(nodeID) * 8
+}
+
+// Bytes copy of node id as byte slice
+func Bytes[T NodeID](nodeID T) []byte {
+	b := make([]byte, len(nodeID))
+	for i := range b {
+		b[i] = nodeID[i]
+	}
+	return b
+}
+
+// NodeIDFromBytes node id from byte slice of matching width
+func NodeIDFromBytes[T NodeID](b []byte) (T, error) {
+
+	var nodeID T
+	if len(b) != len(nodeID) {
+		return nodeID, fmt.Errorf("node id must be %d bytes", len(nodeID))
+	}
+
+	for i := range b {
+		nodeID[i] = b[i]
+	}
+
+	return nodeID, nil
+}
+
+// HashKey hash key into node id space of width T
+func HashKey[T NodeID](key []byte) T {
+	return encode.Hash[T](key)
+}
+
 // Contact to dht node
-type Contact struct {
+type Contact[T NodeID] struct {
 	IP   string
 	Port int
-	ID   NodeID224
+	ID   T
 }
 
-// SetID of contact
-func (c *Contact) SetID() {
-	c.ID = encode.HashKey([]byte(fmt.Sprintf("%s:%d", c.IP, c.Port)))
+// NodeIDFromPublicKey node id of ed25519 public key
+func NodeIDFromPublicKey[T NodeID](pub ed25519.PublicKey) T {
+	return HashKey[T](pub)
 }
 
-// Bucket in dht node
-type Bucket struct {
-	ID       NodeID224
-	Contacts []*Contact
+// Address host and port of contact
+func (c *Contact[T]) Address() string {
+	return net.JoinHostPort(c.IP, fmt.Sprintf("%d", c.Port))
+}
+
+// Record value stored in dht
+type Record[T NodeID] struct {
+	Key   T      `json:"key"`
+	Value []byte `json:"value"`
+	// Publisher node id of original publisher
+	Publisher T `json:"publisher"`
+	// Timestamp of original publication
+	Timestamp time.Time `json:"timestamp"`
+	// Seq version of the value, incremented by every write
+	Seq uint64 `json:"seq,omitempty"`
+	// PublicKey owner of a mutable record, empty for immutable records
+	PublicKey ed25519.PublicKey `json:"public_key,omitempty"`
+	// Salt distinguishes mutable records of the same owner
+	Salt []byte `json:"salt,omitempty"`
+	// Signature of owner over salt, seq, timestamp and value
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// Mutable record is signed by the owner of its key
+func (r *Record[T]) Mutable() bool {
+	return len(r.PublicKey) > 0
 }
 
-// RoutingTable with logarithmic structure
-type RoutingTable struct {
-	Buckets map[NodeID224][]*Bucket // multi-dimensional slice for buckets at different levels
+// signable deterministic encoding of the signed fields
+func (r *Record[T]) signable() []byte {
+	b := make([]byte, 0, binary.MaxVarintLen64+len(r.Salt)+16+len(r.Value))
+	b = binary.AppendUvarint(b, uint64(len(r.Salt)))
+	b = append(b, r.Salt...)
+	b = binary.BigEndian.AppendUint64(b, r.Seq)
+	b = binary.BigEndian.AppendUint64(b, uint64(r.Timestamp.UnixNano()))
+	return append(b, r.Value...)
+}
+
+// MutableKey key of the mutable records of pub, salt
+// lets one owner publish several records
+func MutableKey[T NodeID](pub ed25519.PublicKey, salt []byte) T {
+	b := make([]byte, 0, len(pub)+len(salt))
+	return HashKey[T](append(append(b, pub...), salt...))
+}
+
+// SignRecord turn record into a mutable record of the owner of key,
+// sets key and publisher so seq, timestamp, salt and value must be final
+func SignRecord[T NodeID](key ed25519.PrivateKey, record *Record[T]) {
+	pub := key.Public().(ed25519.PublicKey)
+	record.PublicKey = pub
+	record.Key = MutableKey[T](pub, record.Salt)
+	record.Publisher = NodeIDFromPublicKey[T](pub)
+	record.Signature = ed25519.Sign(key, record.signable())
+}
+
+// VerifyRecord signature of mutable record, immutable records
+// are not signed and only checked to carry no signature
+func VerifyRecord[T NodeID](record *Record[T]) error {
+
+	if !record.Mutable() {
+		if len(record.Signature) > 0 || len(record.Salt) > 0 {
+			return fmt.Errorf("%w signature without public key", ErrRecordSignature)
+		}
+		return nil
+	}
+
+	switch {
+	case len(record.PublicKey) != ed25519.PublicKeySize:
+		return fmt.Errorf("%w invalid public key", ErrRecordSignature)
+	case len(record.Salt) > MaxSaltSize:
+		return fmt.Errorf("%w salt exceeds %d bytes", ErrRecordSignature, MaxSaltSize)
+	case record.Key != MutableKey[T](record.PublicKey, record.Salt):
+		return fmt.Errorf("%w key is not derived from public key and salt", ErrRecordSignature)
+	case record.Publisher != NodeIDFromPublicKey[T](record.PublicKey):
+		return fmt.Errorf("%w publisher is not derived from public key", ErrRecordSignature)
+	case !ed25519.Verify(record.PublicKey, record.signable(), record.Signature):
+		return ErrRecordSignature
+	}
+
+	return nil
+}
+
+// CompareRecords order versions of a record by sequence number,
+// timestamp, publisher and value so every replica resolves
+// conflicting writes the same way
+func CompareRecords[T NodeID](a, b *Record[T]) int {
+
+	switch {
+	case a.Seq < b.Seq:
+		return -1
+	case a.Seq > b.Seq:
+		return 1
+	}
+
+	if c := a.Timestamp.Compare(b.Timestamp); c != 0 {
+		return c
+	}
+
+	if c := Compare(a.Publisher, b.Publisher); c != 0 {
+		return c
+	}
+
+	return bytes.Compare(a.Value, b.Value)
+}
+
+// PutOptions quorum write options
+type PutOptions struct {
+	// Quorum replicas that must store the record, zero for a majority of k
+	Quorum int
+}
+
+// PutResult replicas of a quorum write
+type PutResult[T NodeID] struct {
+	// Record version written
+	Record *Record[T]
+	// Replicas closest nodes the record was sent to
+	Replicas int
+	// Stored replicas that accepted the record
+	Stored int
+}
+
+// GetOptions quorum read options
+type GetOptions struct {
+	// Quorum replicas that must respond, zero for a majority of k
+	Quorum int
+	// NoRepair skip storing the newest version on stale replicas
+	NoRepair bool
+}
+
+// Provider contact of a node holding the content stored under key
+type Provider[T NodeID] struct {
+	Key     T          `json:"key"`
+	Contact Contact[T] `json:"contact"`
+	// TTL lifetime of the provider record after it was received
+	TTL time.Duration `json:"ttl"`
+	// PublicKey key of the provider node, owner of the mutable
+	// records it publishes, empty for providers of older nodes
+	PublicKey ed25519.PublicKey `json:"public_key,omitempty"`
+}
+
+// StoredRecord record with local bookkeeping
+type StoredRecord[T NodeID] struct {
+	Record[T]
+	// Expires time record is removed from local store
+	Expires time.Time `json:"expires"`
+	// StoredAt time record was last received
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// ContactInfo routing table entry of a contact
+type ContactInfo[T NodeID] struct {
+	Contact   Contact[T]
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// RTT smoothed round trip time, zero if never measured
+	RTT time.Duration
+	// Failures consecutive queries without response
+	Failures int
+}
+
+// Coordinate vivaldi network coordinate, the estimated round trip time
+// between two nodes is the euclidean distance of their vectors plus
+// both heights in seconds
+type Coordinate struct {
+	Vec []float64 `json:"vec"`
+	// Height latency of the access link not captured by the vector
+	Height float64 `json:"height"`
+	// Error relative confidence of the coordinate, high for new coordinates
+	Error float64 `json:"error"`
+}
+
+// BucketInfo contacts and replacement cache of a routing table bucket
+type BucketInfo[T NodeID] struct {
+	Index        int
+	LastLookup   time.Time
+	Contacts     []ContactInfo[T]
+	Replacements []ContactInfo[T]
+}
+
+// QueryTrace single query sent during a lookup
+type QueryTrace[T NodeID] struct {
+	Contact Contact[T]
+	// Hop number of responses on the path to the contact, seeds are hop 1
+	Hop int
+	// Sent time since start of lookup the query was sent
+	Sent time.Duration
+	RTT  time.Duration
+	// Returned contacts in response
+	Returned []*Contact[T]
+	Err      error
+}
+
+// LookupTrace queries of a single lookup in the order responses arrived
+type LookupTrace[T NodeID] struct {
+	Target   T
+	Started  time.Time
+	Duration time.Duration
+	Queries  []QueryTrace[T]
+	// Contacts k closest contacts that responded
+	Contacts []*Contact[T]
+}
+
+// Bucket in dht node
+type Bucket[T NodeID] struct {
+	ID       T
+	Contacts []*Contact[T]
 }
 
 // DHT k-buckets distributed hash table
 //
 //go:generate mockery --name DHT
-type DHT interface {
+type DHT[T NodeID] interface {
 	// FindKClosestBuckets iterate over all buckets and compare key to bucket id
-	FindKClosestBuckets(ctx context.Context, key []byte) []NodeID224
+	FindKClosestBuckets(ctx context.Context, key []byte) []T
 	// FindClosestNodes iterate over buckets and find closest contact addresses
-	FindClosestNodes(ctx context.Context, key []byte, nodeID NodeID224) []string
+	FindClosestNodes(ctx context.Context, key []byte, nodeID T) []string
 	// AddOrUpdateNode add or override node value
-	AddOrUpdateRoutingTable(ctx context.Context, c *Contact)
-	// Get value
-	Get(ctx context.Context, key []byte) *Bucket
-	// Put
-	AddOrUpdateNode(ctx context.Context, key []byte, value interface{})
+	AddOrUpdateRoutingTable(ctx context.Context, c *Contact[T])
+	// Get value from local store or network
+	Get(ctx context.Context, key []byte) ([]byte, error)
+	// Put value in local store and on closest nodes
+	Put(ctx context.Context, key, value []byte) error
+	// PutValue store new version of value on the closest nodes with a write quorum
+	PutValue(ctx context.Context, key, value []byte, opts PutOptions) (*PutResult[T], error)
+	// GetValue newest version of value from a read quorum of the closest nodes
+	GetValue(ctx context.Context, key []byte, opts GetOptions) (*Record[T], error)
+	// PutMutable sign value with owner key and store it under the mutable key of owner and salt
+	PutMutable(ctx context.Context, owner ed25519.PrivateKey, salt, value []byte, opts PutOptions) (*PutResult[T], error)
+	// GetMutable newest verified version of the mutable record of owner and salt
+	GetMutable(ctx context.Context, owner ed25519.PublicKey, salt []byte, opts GetOptions) (*Record[T], error)
+	// StoreRecord accept record from another node
+	StoreRecord(ctx context.Context, record *Record[T]) error
+	// FindRecord record in local store
+	FindRecord(ctx context.Context, key T) (*Record[T], error)
+	// ClosestContacts known contacts sorted by distance to node id
+	ClosestContacts(nodeID T, count int) []*Contact[T]
+	// Lookup iterative search for the closest live contacts to node id
+	Lookup(ctx context.Context, nodeID T) ([]*Contact[T], error)
+	// LookupValue iterative search for record stored under key
+	LookupValue(ctx context.Context, key T) (*Record[T], []*Contact[T], error)
+	// Bootstrap join network through seed nodes
+	Bootstrap(ctx context.Context, seeds []*Contact[T]) error
+	// Provide announce local node as provider of key on closest nodes
+	Provide(ctx context.Context, key []byte) error
+	// FindProviders local and network providers of key up to count
+	FindProviders(ctx context.Context, key []byte, count int) ([]*Provider[T], error)
+	// AddProvider accept provider record from another node
+	AddProvider(ctx context.Context, provider *Provider[T]) error
+	// Providers provider records in local store
+	Providers(ctx context.Context, key T) ([]*Provider[T], error)
+	// Contact advertised contact of local node
+	Contact() *Contact[T]
+	// ReplicationFactor bucket size k of local node
+	ReplicationFactor() int
+	// ObserveAddr external ip of local node as seen by observer
+	ObserveAddr(ctx context.Context, observer T, ip string)
+	// Coordinate network coordinate of local node, nil if not maintained
+	Coordinate() *Coordinate
+	// ObserveCoordinate network coordinate reported by contact
+	ObserveCoordinate(ctx context.Context, nodeID T, c *Coordinate)
+}
+
+// Inspector debug view into a dht node
+//
+//go:generate mockery --name Inspector
+type Inspector[T NodeID] interface {
+	// Buckets non empty routing table buckets
+	Buckets() []BucketInfo[T]
+	// TraceLookup node lookup recording every query
+	TraceLookup(ctx context.Context, target T) (*LookupTrace[T], error)
+	// Records locally stored records with expiry
+	Records(ctx context.Context) ([]*StoredRecord[T], error)
+}
+
+// DHTMetrics receives events of a dht node for telemetry
+//
+//go:generate mockery --name DHTMetrics
+type DHTMetrics interface {
+	// ObserveLookup completed lookup with hops to the closest contact
+	ObserveLookup(duration time.Duration, hops int, err error)
+	// ObserveEviction contact removed from the routing table
+	ObserveEviction(reason string)
+	// ObserveRepublish records republished and failed during one run
+	ObserveRepublish(published, failed int)
+	// ObserveRecords number of stored records and bytes of their values
+	ObserveRecords(count, size int)
+}
+
+// Transport remote procedure calls between dht nodes
+//
+//go:generate mockery --name Transport
+type Transport[T NodeID] interface {
+	// Ping check if contact is alive, returns node id of responder
+	Ping(ctx context.Context, c *Contact[T]) (T, error)
+	// Store record on contact
+	Store(ctx context.Context, c *Contact[T], record *Record[T]) error
+	// FindNode ask contact for closest contacts to node id
+	FindNode(ctx context.Context, c *Contact[T], nodeID T) ([]*Contact[T], error)
+	// FindValue ask contact for record or closest contacts to key
+	FindValue(ctx context.Context, c *Contact[T], key T) (*Record[T], []*Contact[T], error)
+	// AddProvider store provider record on contact
+	AddProvider(ctx context.Context, c *Contact[T], provider *Provider[T]) error
+	// GetProviders ask contact for providers and closest contacts to key
+	GetProviders(ctx context.Context, c *Contact[T], key T) ([]*Provider[T], []*Contact[T], error)
 }
@@ -1,15 +1,23 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrKeyNotFound no value stored under key in key value database
+var ErrKeyNotFound = errors.New("key not found")
 
 // KV key value database interface
 //
 //go:generate mockery --name KV
 type KV interface {
-	// Get value by key
+	// Get value by key, ErrKeyNotFound if key is not stored
 	Get(key []byte) ([]byte, error)
 	// Put set key/value pair
 	Put(key, value []byte) error
+	// Delete key/value pair
+	Delete(key []byte) error
 	// Iterator key/value iterator
 	Iterator(ctx context.Context) (KvIterator, error)
 	// Close database connection
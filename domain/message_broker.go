@@ -78,10 +78,27 @@ func (m *Msg) GetPayload() []byte {
 	return m.payload
 }
 
-// JoinedNetwork new service joined network message
+// RegistrationEvent change of a service registration
+type RegistrationEvent string
+
+const (
+	// Registered service instance joined the network
+	Registered RegistrationEvent = "registered"
+	// Deregistered service instance left the network
+	Deregistered RegistrationEvent = "deregistered"
+	// Healthy service instance passed its health check again
+	Healthy RegistrationEvent = "healthy"
+	// Unhealthy service instance failed its health check
+	Unhealthy RegistrationEvent = "unhealthy"
+)
+
+// JoinedNetwork new service joined network message, registry
+// changes of the service are published with their event
 type JoinedNetwork struct {
-	ServiceType ServiceType `json:"service_type"`
-	ServiceID   string      `json:"service_id"`
+	ServiceType ServiceType       `json:"service_type"`
+	ServiceID   string            `json:"service_id"`
+	Event       RegistrationEvent `json:"event,omitempty"`
+	Endpoints   []string          `json:"endpoints,omitempty"`
 }
 
 // NewJoinedNetwork constructor
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNoInstances no live instance of service type is registered
+	ErrNoInstances = errors.New("no live service instances")
+	// ErrNoHealthyInstances every live instance of service type is unhealthy
+	ErrNoHealthyInstances = errors.New("no healthy service instances")
+)
+
+// ServiceInstance registered instance of a service
+type ServiceInstance struct {
+	Type      ServiceType       `json:"type"`
+	ID        string            `json:"id"`
+	Endpoints []string          `json:"endpoints"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	// TTL lifetime of the registration unless renewed by a heartbeat
+	TTL time.Duration `json:"ttl"`
+	// Healthy result of the latest health check of the instance
+	Healthy bool `json:"healthy"`
+	// Expires time instance is considered gone
+	Expires time.Time `json:"expires"`
+}
+
+// HealthCheck error if a registered instance is unable to serve
+type HealthCheck func(ctx context.Context) error
+
+// ServiceRegistry registration and discovery of service instances
+type ServiceRegistry interface {
+	// Register announce instance and renew it with heartbeats until
+	// deregistered, instances without health check are always healthy
+	Register(ctx context.Context, instance *ServiceInstance, check HealthCheck) error
+	// Deregister stop heartbeats and withdraw instance
+	Deregister(ctx context.Context, serviceType ServiceType, id string) error
+	// Resolve live instances of service type
+	Resolve(ctx context.Context, serviceType ServiceType) ([]*ServiceInstance, error)
+	// Pick healthy instance of service type balancing load between instances
+	Pick(ctx context.Context, serviceType ServiceType) (*ServiceInstance, error)
+	// Report outcome of a request to instance, failing instances are avoided by Pick
+	Report(instance *ServiceInstance, err error)
+}
@@ -7,7 +7,7 @@
 package v1
 
 import (
-	empty "github.com/golang/protobuf/ptypes/empty"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -22,13 +22,89 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// StoreStatus outcome of a store request
+type StoreStatus int32
+
+const (
+	// STORE_STATUS_UNSPECIFIED returned by nodes that predate status codes
+	StoreStatus_STORE_STATUS_UNSPECIFIED StoreStatus = 0
+	// STORE_STATUS_STORED record was stored
+	StoreStatus_STORE_STATUS_STORED StoreStatus = 1
+	// STORE_STATUS_OUTDATED node already holds a newer record for the key
+	StoreStatus_STORE_STATUS_OUTDATED StoreStatus = 2
+	// STORE_STATUS_EXPIRED record expired before it could be stored
+	StoreStatus_STORE_STATUS_EXPIRED StoreStatus = 3
+	// STORE_STATUS_REJECTED record was invalid or could not be stored
+	StoreStatus_STORE_STATUS_REJECTED StoreStatus = 4
+)
+
+// Enum value maps for StoreStatus.
+var (
+	StoreStatus_name = map[int32]string{
+		0: "STORE_STATUS_UNSPECIFIED",
+		1: "STORE_STATUS_STORED",
+		2: "STORE_STATUS_OUTDATED",
+		3: "STORE_STATUS_EXPIRED",
+		4: "STORE_STATUS_REJECTED",
+	}
+	StoreStatus_value = map[string]int32{
+		"STORE_STATUS_UNSPECIFIED": 0,
+		"STORE_STATUS_STORED":      1,
+		"STORE_STATUS_OUTDATED":    2,
+		"STORE_STATUS_EXPIRED":     3,
+		"STORE_STATUS_REJECTED":    4,
+	}
+)
+
+func (x StoreStatus) Enum() *StoreStatus {
+	p := new(StoreStatus)
+	*p = x
+	return p
+}
+
+func (x StoreStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StoreStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_dht_dht_service_proto_enumTypes[0].Descriptor()
+}
+
+func (StoreStatus) Type() protoreflect.EnumType {
+	return &file_proto_dht_dht_service_proto_enumTypes[0]
+}
+
+func (x StoreStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StoreStatus.Descriptor instead.
+func (StoreStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{0}
+}
+
+// Sender identity of the node sending a message
+//
+// sender_id is the sha3-224 hash of public_key. signature is the
+// ed25519 signature over the deterministic encoding of the enclosing
+// message with the signature field left empty
+//
+// advertise_ip and advertise_port are the address other nodes should
+// use to reach the sender, which differs from the listen address of
+// nodes behind nat or in containers. receivers only take the port and
+// pair it with the address the request was received from, advertise_ip
+// is informational since a sender could point it at a third party
 type Sender struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SenderId    []byte               `protobuf:"bytes,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	RequestedAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
+	SenderId      []byte               `protobuf:"bytes,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	RequestedAt   *timestamp.Timestamp `protobuf:"bytes,2,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
+	PublicKey     []byte               `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Signature     []byte               `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	AdvertiseIp   string               `protobuf:"bytes,5,opt,name=advertise_ip,json=advertiseIp,proto3" json:"advertise_ip,omitempty"`
+	AdvertisePort int64                `protobuf:"varint,6,opt,name=advertise_port,json=advertisePort,proto3" json:"advertise_port,omitempty"`
 }
 
 func (x *Sender) Reset() {
@@ -77,6 +153,38 @@ func (x *Sender) GetRequestedAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *Sender) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *Sender) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *Sender) GetAdvertiseIp() string {
+	if x != nil {
+		return x.AdvertiseIp
+	}
+	return ""
+}
+
+func (x *Sender) GetAdvertisePort() int64 {
+	if x != nil {
+		return x.AdvertisePort
+	}
+	return 0
+}
+
+// Echo
+//
+// coordinate is the vivaldi network coordinate of the responder,
+// unset if the responder does not maintain one
 type Echo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -84,6 +192,7 @@ type Echo struct {
 
 	RpcId       []byte               `protobuf:"bytes,1,opt,name=rpc_id,json=rpcId,proto3" json:"rpc_id,omitempty"`
 	CompletedAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Coordinate  *Coordinate          `protobuf:"bytes,3,opt,name=coordinate,proto3" json:"coordinate,omitempty"`
 }
 
 func (x *Echo) Reset() {
@@ -132,6 +241,77 @@ func (x *Echo) GetCompletedAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *Echo) GetCoordinate() *Coordinate {
+	if x != nil {
+		return x.Coordinate
+	}
+	return nil
+}
+
+// Coordinate vivaldi network coordinate, vec and height in seconds
+type Coordinate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vec    []float64 `protobuf:"fixed64,1,rep,packed,name=vec,proto3" json:"vec,omitempty"`
+	Height float64   `protobuf:"fixed64,2,opt,name=height,proto3" json:"height,omitempty"`
+	Error  float64   `protobuf:"fixed64,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *Coordinate) Reset() {
+	*x = Coordinate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Coordinate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coordinate) ProtoMessage() {}
+
+func (x *Coordinate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coordinate.ProtoReflect.Descriptor instead.
+func (*Coordinate) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Coordinate) GetVec() []float64 {
+	if x != nil {
+		return x.Vec
+	}
+	return nil
+}
+
+func (x *Coordinate) GetHeight() float64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Coordinate) GetError() float64 {
+	if x != nil {
+		return x.Error
+	}
+	return 0
+}
+
 type Contact struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -145,7 +325,7 @@ type Contact struct {
 func (x *Contact) Reset() {
 	*x = Contact{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_dht_dht_service_proto_msgTypes[2]
+		mi := &file_proto_dht_dht_service_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -158,7 +338,7 @@ func (x *Contact) String() string {
 func (*Contact) ProtoMessage() {}
 
 func (x *Contact) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_dht_dht_service_proto_msgTypes[2]
+	mi := &file_proto_dht_dht_service_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -171,7 +351,7 @@ func (x *Contact) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Contact.ProtoReflect.Descriptor instead.
 func (*Contact) Descriptor() ([]byte, []int) {
-	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{2}
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Contact) GetIp() string {
@@ -206,7 +386,7 @@ type PingRequest struct {
 func (x *PingRequest) Reset() {
 	*x = PingRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_dht_dht_service_proto_msgTypes[3]
+		mi := &file_proto_dht_dht_service_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -219,7 +399,7 @@ func (x *PingRequest) String() string {
 func (*PingRequest) ProtoMessage() {}
 
 func (x *PingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_dht_dht_service_proto_msgTypes[3]
+	mi := &file_proto_dht_dht_service_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -232,7 +412,7 @@ func (x *PingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
 func (*PingRequest) Descriptor() ([]byte, []int) {
-	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{3}
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *PingRequest) GetSender() *Sender {
@@ -242,20 +422,101 @@ func (x *PingRequest) GetSender() *Sender {
 	return nil
 }
 
+// PingResponse
+//
+// replaces google.protobuf.Empty, the field is new so
+// older nodes are decoded as a response without responder.
+// observed_ip is the address the request was received from,
+// nodes learn their external address from it
+type PingResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Echo       *Echo   `protobuf:"bytes,1,opt,name=echo,proto3" json:"echo,omitempty"`
+	Responder  *Sender `protobuf:"bytes,2,opt,name=responder,proto3" json:"responder,omitempty"`
+	ObservedIp string  `protobuf:"bytes,3,opt,name=observed_ip,json=observedIp,proto3" json:"observed_ip,omitempty"`
+}
+
+func (x *PingResponse) Reset() {
+	*x = PingResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingResponse) ProtoMessage() {}
+
+func (x *PingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
+func (*PingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PingResponse) GetEcho() *Echo {
+	if x != nil {
+		return x.Echo
+	}
+	return nil
+}
+
+func (x *PingResponse) GetResponder() *Sender {
+	if x != nil {
+		return x.Responder
+	}
+	return nil
+}
+
+func (x *PingResponse) GetObservedIp() string {
+	if x != nil {
+		return x.ObservedIp
+	}
+	return ""
+}
+
+// StoreRequest
+//
+// seq was added after published_at, records of older
+// nodes decode as sequence number zero. public_key, salt
+// and signature are set for mutable records only, the
+// signature covers salt, seq, published_at and value
 type StoreRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Sender *Sender `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
-	Key    []byte  `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Value  []byte  `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Sender      *Sender              `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	Key         []byte               `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value       []byte               `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Publisher   []byte               `protobuf:"bytes,4,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	PublishedAt *timestamp.Timestamp `protobuf:"bytes,5,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	Seq         uint64               `protobuf:"varint,6,opt,name=seq,proto3" json:"seq,omitempty"`
+	PublicKey   []byte               `protobuf:"bytes,7,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Salt        []byte               `protobuf:"bytes,8,opt,name=salt,proto3" json:"salt,omitempty"`
+	Signature   []byte               `protobuf:"bytes,9,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
 func (x *StoreRequest) Reset() {
 	*x = StoreRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_dht_dht_service_proto_msgTypes[4]
+		mi := &file_proto_dht_dht_service_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -268,7 +529,7 @@ func (x *StoreRequest) String() string {
 func (*StoreRequest) ProtoMessage() {}
 
 func (x *StoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_dht_dht_service_proto_msgTypes[4]
+	mi := &file_proto_dht_dht_service_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -281,7 +542,7 @@ func (x *StoreRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StoreRequest.ProtoReflect.Descriptor instead.
 func (*StoreRequest) Descriptor() ([]byte, []int) {
-	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{4}
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *StoreRequest) GetSender() *Sender {
@@ -305,18 +566,67 @@ func (x *StoreRequest) GetValue() []byte {
 	return nil
 }
 
+func (x *StoreRequest) GetPublisher() []byte {
+	if x != nil {
+		return x.Publisher
+	}
+	return nil
+}
+
+func (x *StoreRequest) GetPublishedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+func (x *StoreRequest) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *StoreRequest) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *StoreRequest) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *StoreRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// StoreResponse
+//
+// status was added after echo, older nodes leave it unset
+// which decodes as STORE_STATUS_UNSPECIFIED. responder signs
+// responses sent over udp
 type StoreResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Echo *Echo `protobuf:"bytes,1,opt,name=echo,proto3" json:"echo,omitempty"`
+	Echo      *Echo       `protobuf:"bytes,1,opt,name=echo,proto3" json:"echo,omitempty"`
+	Status    StoreStatus `protobuf:"varint,2,opt,name=status,proto3,enum=dht.v1.StoreStatus" json:"status,omitempty"`
+	Responder *Sender     `protobuf:"bytes,3,opt,name=responder,proto3" json:"responder,omitempty"`
 }
 
 func (x *StoreResponse) Reset() {
 	*x = StoreResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_dht_dht_service_proto_msgTypes[5]
+		mi := &file_proto_dht_dht_service_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -329,7 +639,7 @@ func (x *StoreResponse) String() string {
 func (*StoreResponse) ProtoMessage() {}
 
 func (x *StoreResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_dht_dht_service_proto_msgTypes[5]
+	mi := &file_proto_dht_dht_service_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -342,7 +652,7 @@ func (x *StoreResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StoreResponse.ProtoReflect.Descriptor instead.
 func (*StoreResponse) Descriptor() ([]byte, []int) {
-	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{5}
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StoreResponse) GetEcho() *Echo {
@@ -352,6 +662,20 @@ func (x *StoreResponse) GetEcho() *Echo {
 	return nil
 }
 
+func (x *StoreResponse) GetStatus() StoreStatus {
+	if x != nil {
+		return x.Status
+	}
+	return StoreStatus_STORE_STATUS_UNSPECIFIED
+}
+
+func (x *StoreResponse) GetResponder() *Sender {
+	if x != nil {
+		return x.Responder
+	}
+	return nil
+}
+
 type FindNodeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -364,7 +688,7 @@ type FindNodeRequest struct {
 func (x *FindNodeRequest) Reset() {
 	*x = FindNodeRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_dht_dht_service_proto_msgTypes[6]
+		mi := &file_proto_dht_dht_service_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -377,7 +701,7 @@ func (x *FindNodeRequest) String() string {
 func (*FindNodeRequest) ProtoMessage() {}
 
 func (x *FindNodeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_dht_dht_service_proto_msgTypes[6]
+	mi := &file_proto_dht_dht_service_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -390,7 +714,7 @@ func (x *FindNodeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FindNodeRequest.ProtoReflect.Descriptor instead.
 func (*FindNodeRequest) Descriptor() ([]byte, []int) {
-	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{6}
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *FindNodeRequest) GetSender() *Sender {
@@ -407,6 +731,9 @@ func (x *FindNodeRequest) GetNodeId() []byte {
 	return nil
 }
 
+// FindNodeResponse
+//
+// responder signs responses sent over udp
 type FindNodeResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -414,12 +741,13 @@ type FindNodeResponse struct {
 
 	Echo        *Echo      `protobuf:"bytes,1,opt,name=echo,proto3" json:"echo,omitempty"`
 	ContactList []*Contact `protobuf:"bytes,2,rep,name=contact_list,json=contactList,proto3" json:"contact_list,omitempty"`
+	Responder   *Sender    `protobuf:"bytes,3,opt,name=responder,proto3" json:"responder,omitempty"`
 }
 
 func (x *FindNodeResponse) Reset() {
 	*x = FindNodeResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_dht_dht_service_proto_msgTypes[7]
+		mi := &file_proto_dht_dht_service_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -432,7 +760,7 @@ func (x *FindNodeResponse) String() string {
 func (*FindNodeResponse) ProtoMessage() {}
 
 func (x *FindNodeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_dht_dht_service_proto_msgTypes[7]
+	mi := &file_proto_dht_dht_service_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -445,7 +773,7 @@ func (x *FindNodeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FindNodeResponse.ProtoReflect.Descriptor instead.
 func (*FindNodeResponse) Descriptor() ([]byte, []int) {
-	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{7}
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *FindNodeResponse) GetEcho() *Echo {
@@ -462,18 +790,30 @@ func (x *FindNodeResponse) GetContactList() []*Contact {
 	return nil
 }
 
+func (x *FindNodeResponse) GetResponder() *Sender {
+	if x != nil {
+		return x.Responder
+	}
+	return nil
+}
+
+// FindValueRequest
+//
+// key was added after sender, requests from older
+// nodes without a key are rejected as invalid
 type FindValueRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Sender *Sender `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	Key    []byte  `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
 }
 
 func (x *FindValueRequest) Reset() {
 	*x = FindValueRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_dht_dht_service_proto_msgTypes[8]
+		mi := &file_proto_dht_dht_service_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -486,7 +826,7 @@ func (x *FindValueRequest) String() string {
 func (*FindValueRequest) ProtoMessage() {}
 
 func (x *FindValueRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_dht_dht_service_proto_msgTypes[8]
+	mi := &file_proto_dht_dht_service_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -499,7 +839,7 @@ func (x *FindValueRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FindValueRequest.ProtoReflect.Descriptor instead.
 func (*FindValueRequest) Descriptor() ([]byte, []int) {
-	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{8}
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *FindValueRequest) GetSender() *Sender {
@@ -509,31 +849,49 @@ func (x *FindValueRequest) GetSender() *Sender {
 	return nil
 }
 
-type FindValueResponse struct {
+func (x *FindValueRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+// Value record stored under key with publication metadata,
+// seq is the version of the value. mutable records carry the
+// public key and salt their key is derived from and the signature
+// of the owner, see StoreRequest
+type Value struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Echo *Echo `protobuf:"bytes,1,opt,name=echo,proto3" json:"echo,omitempty"`
+	Key         []byte               `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value       []byte               `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Publisher   []byte               `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	PublishedAt *timestamp.Timestamp `protobuf:"bytes,4,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	Seq         uint64               `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`
+	PublicKey   []byte               `protobuf:"bytes,6,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Salt        []byte               `protobuf:"bytes,7,opt,name=salt,proto3" json:"salt,omitempty"`
+	Signature   []byte               `protobuf:"bytes,8,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
-func (x *FindValueResponse) Reset() {
-	*x = FindValueResponse{}
+func (x *Value) Reset() {
+	*x = Value{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_dht_dht_service_proto_msgTypes[9]
+		mi := &file_proto_dht_dht_service_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *FindValueResponse) String() string {
+func (x *Value) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FindValueResponse) ProtoMessage() {}
+func (*Value) ProtoMessage() {}
 
-func (x *FindValueResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_dht_dht_service_proto_msgTypes[9]
+func (x *Value) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,95 +902,1181 @@ func (x *FindValueResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FindValueResponse.ProtoReflect.Descriptor instead.
-func (*FindValueResponse) Descriptor() ([]byte, []int) {
-	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use Value.ProtoReflect.Descriptor instead.
+func (*Value) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *FindValueResponse) GetEcho() *Echo {
+func (x *Value) GetKey() []byte {
 	if x != nil {
-		return x.Echo
+		return x.Key
 	}
 	return nil
 }
 
-var File_proto_dht_dht_service_proto protoreflect.FileDescriptor
+func (x *Value) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
 
-var file_proto_dht_dht_service_proto_rawDesc = []byte{
-	0x0a, 0x1b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x68, 0x74, 0x2f, 0x64, 0x68, 0x74, 0x5f,
+func (x *Value) GetPublisher() []byte {
+	if x != nil {
+		return x.Publisher
+	}
+	return nil
+}
+
+func (x *Value) GetPublishedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+func (x *Value) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Value) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *Value) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *Value) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// ContactList closest contacts known to the responding node
+type ContactList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Contacts []*Contact `protobuf:"bytes,1,rep,name=contacts,proto3" json:"contacts,omitempty"`
+}
+
+func (x *ContactList) Reset() {
+	*x = ContactList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContactList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContactList) ProtoMessage() {}
+
+func (x *ContactList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContactList.ProtoReflect.Descriptor instead.
+func (*ContactList) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ContactList) GetContacts() []*Contact {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+// FindValueResponse
+//
+// echo keeps field number 1, the result fields are new.
+// older nodes respond with neither set. responder
+// signs responses sent over udp
+type FindValueResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Echo *Echo `protobuf:"bytes,1,opt,name=echo,proto3" json:"echo,omitempty"`
+	// Types that are assignable to Result:
+	//	*FindValueResponse_Value
+	//	*FindValueResponse_ContactList
+	Result    isFindValueResponse_Result `protobuf_oneof:"result"`
+	Responder *Sender                    `protobuf:"bytes,4,opt,name=responder,proto3" json:"responder,omitempty"`
+}
+
+func (x *FindValueResponse) Reset() {
+	*x = FindValueResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FindValueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindValueResponse) ProtoMessage() {}
+
+func (x *FindValueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindValueResponse.ProtoReflect.Descriptor instead.
+func (*FindValueResponse) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FindValueResponse) GetEcho() *Echo {
+	if x != nil {
+		return x.Echo
+	}
+	return nil
+}
+
+func (m *FindValueResponse) GetResult() isFindValueResponse_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *FindValueResponse) GetValue() *Value {
+	if x, ok := x.GetResult().(*FindValueResponse_Value); ok {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *FindValueResponse) GetContactList() *ContactList {
+	if x, ok := x.GetResult().(*FindValueResponse_ContactList); ok {
+		return x.ContactList
+	}
+	return nil
+}
+
+func (x *FindValueResponse) GetResponder() *Sender {
+	if x != nil {
+		return x.Responder
+	}
+	return nil
+}
+
+type isFindValueResponse_Result interface {
+	isFindValueResponse_Result()
+}
+
+type FindValueResponse_Value struct {
+	Value *Value `protobuf:"bytes,2,opt,name=value,proto3,oneof"`
+}
+
+type FindValueResponse_ContactList struct {
+	ContactList *ContactList `protobuf:"bytes,3,opt,name=contact_list,json=contactList,proto3,oneof"`
+}
+
+func (*FindValueResponse_Value) isFindValueResponse_Result() {}
+
+func (*FindValueResponse_ContactList) isFindValueResponse_Result() {}
+
+// Provider contact of a node holding the content stored under a key
+type Provider struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Contact *Contact `protobuf:"bytes,1,opt,name=contact,proto3" json:"contact,omitempty"`
+	// ttl lifetime of the provider record after it was received
+	Ttl *duration.Duration `protobuf:"bytes,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// public_key key of the provider node, unset by older nodes
+	PublicKey []byte `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+}
+
+func (x *Provider) Reset() {
+	*x = Provider{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Provider) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provider) ProtoMessage() {}
+
+func (x *Provider) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provider.ProtoReflect.Descriptor instead.
+func (*Provider) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Provider) GetContact() *Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+func (x *Provider) GetTtl() *duration.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *Provider) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+// AddProviderRequest announce provider of content on one of
+// the k closest nodes to key
+type AddProviderRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sender   *Sender   `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	Key      []byte    `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Provider *Provider `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+}
+
+func (x *AddProviderRequest) Reset() {
+	*x = AddProviderRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddProviderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProviderRequest) ProtoMessage() {}
+
+func (x *AddProviderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProviderRequest.ProtoReflect.Descriptor instead.
+func (*AddProviderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AddProviderRequest) GetSender() *Sender {
+	if x != nil {
+		return x.Sender
+	}
+	return nil
+}
+
+func (x *AddProviderRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *AddProviderRequest) GetProvider() *Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return nil
+}
+
+// AddProviderResponse
+//
+// responder signs responses sent over udp
+type AddProviderResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Echo      *Echo       `protobuf:"bytes,1,opt,name=echo,proto3" json:"echo,omitempty"`
+	Status    StoreStatus `protobuf:"varint,2,opt,name=status,proto3,enum=dht.v1.StoreStatus" json:"status,omitempty"`
+	Responder *Sender     `protobuf:"bytes,3,opt,name=responder,proto3" json:"responder,omitempty"`
+}
+
+func (x *AddProviderResponse) Reset() {
+	*x = AddProviderResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddProviderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProviderResponse) ProtoMessage() {}
+
+func (x *AddProviderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProviderResponse.ProtoReflect.Descriptor instead.
+func (*AddProviderResponse) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AddProviderResponse) GetEcho() *Echo {
+	if x != nil {
+		return x.Echo
+	}
+	return nil
+}
+
+func (x *AddProviderResponse) GetStatus() StoreStatus {
+	if x != nil {
+		return x.Status
+	}
+	return StoreStatus_STORE_STATUS_UNSPECIFIED
+}
+
+func (x *AddProviderResponse) GetResponder() *Sender {
+	if x != nil {
+		return x.Responder
+	}
+	return nil
+}
+
+type GetProvidersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sender *Sender `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	Key    []byte  `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *GetProvidersRequest) Reset() {
+	*x = GetProvidersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProvidersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProvidersRequest) ProtoMessage() {}
+
+func (x *GetProvidersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProvidersRequest.ProtoReflect.Descriptor instead.
+func (*GetProvidersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetProvidersRequest) GetSender() *Sender {
+	if x != nil {
+		return x.Sender
+	}
+	return nil
+}
+
+func (x *GetProvidersRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+// GetProvidersResponse
+//
+// providers known for key together with the closest
+// contacts to key so lookups can continue, responder
+// signs responses sent over udp
+type GetProvidersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Echo        *Echo       `protobuf:"bytes,1,opt,name=echo,proto3" json:"echo,omitempty"`
+	Providers   []*Provider `protobuf:"bytes,2,rep,name=providers,proto3" json:"providers,omitempty"`
+	ContactList []*Contact  `protobuf:"bytes,3,rep,name=contact_list,json=contactList,proto3" json:"contact_list,omitempty"`
+	Responder   *Sender     `protobuf:"bytes,4,opt,name=responder,proto3" json:"responder,omitempty"`
+}
+
+func (x *GetProvidersResponse) Reset() {
+	*x = GetProvidersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProvidersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProvidersResponse) ProtoMessage() {}
+
+func (x *GetProvidersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProvidersResponse.ProtoReflect.Descriptor instead.
+func (*GetProvidersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetProvidersResponse) GetEcho() *Echo {
+	if x != nil {
+		return x.Echo
+	}
+	return nil
+}
+
+func (x *GetProvidersResponse) GetProviders() []*Provider {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+func (x *GetProvidersResponse) GetContactList() []*Contact {
+	if x != nil {
+		return x.ContactList
+	}
+	return nil
+}
+
+func (x *GetProvidersResponse) GetResponder() *Sender {
+	if x != nil {
+		return x.Responder
+	}
+	return nil
+}
+
+// Packet udp datagram carrying a single dht request or response
+//
+// responses repeat the request_id chosen by the requester, which
+// retransmits the request with the same id until a response arrives.
+// packets larger than the maximum datagram size are marshalled and
+// split into fragments, each a request or response chunk holding part
+// of the bytes of the packet, fragment counts from zero to fragments - 1
+type Packet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId uint64 `protobuf:"fixed64,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Fragment  uint32 `protobuf:"varint,2,opt,name=fragment,proto3" json:"fragment,omitempty"`
+	Fragments uint32 `protobuf:"varint,3,opt,name=fragments,proto3" json:"fragments,omitempty"`
+	// Types that are assignable to Body:
+	//	*Packet_PingRequest
+	//	*Packet_PingResponse
+	//	*Packet_StoreRequest
+	//	*Packet_StoreResponse
+	//	*Packet_FindNodeRequest
+	//	*Packet_FindNodeResponse
+	//	*Packet_FindValueRequest
+	//	*Packet_FindValueResponse
+	//	*Packet_AddProviderRequest
+	//	*Packet_AddProviderResponse
+	//	*Packet_GetProvidersRequest
+	//	*Packet_GetProvidersResponse
+	//	*Packet_Error
+	//	*Packet_RequestChunk
+	//	*Packet_ResponseChunk
+	Body isPacket_Body `protobuf_oneof:"body"`
+}
+
+func (x *Packet) Reset() {
+	*x = Packet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Packet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Packet) ProtoMessage() {}
+
+func (x *Packet) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Packet.ProtoReflect.Descriptor instead.
+func (*Packet) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Packet) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *Packet) GetFragment() uint32 {
+	if x != nil {
+		return x.Fragment
+	}
+	return 0
+}
+
+func (x *Packet) GetFragments() uint32 {
+	if x != nil {
+		return x.Fragments
+	}
+	return 0
+}
+
+func (m *Packet) GetBody() isPacket_Body {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
+func (x *Packet) GetPingRequest() *PingRequest {
+	if x, ok := x.GetBody().(*Packet_PingRequest); ok {
+		return x.PingRequest
+	}
+	return nil
+}
+
+func (x *Packet) GetPingResponse() *PingResponse {
+	if x, ok := x.GetBody().(*Packet_PingResponse); ok {
+		return x.PingResponse
+	}
+	return nil
+}
+
+func (x *Packet) GetStoreRequest() *StoreRequest {
+	if x, ok := x.GetBody().(*Packet_StoreRequest); ok {
+		return x.StoreRequest
+	}
+	return nil
+}
+
+func (x *Packet) GetStoreResponse() *StoreResponse {
+	if x, ok := x.GetBody().(*Packet_StoreResponse); ok {
+		return x.StoreResponse
+	}
+	return nil
+}
+
+func (x *Packet) GetFindNodeRequest() *FindNodeRequest {
+	if x, ok := x.GetBody().(*Packet_FindNodeRequest); ok {
+		return x.FindNodeRequest
+	}
+	return nil
+}
+
+func (x *Packet) GetFindNodeResponse() *FindNodeResponse {
+	if x, ok := x.GetBody().(*Packet_FindNodeResponse); ok {
+		return x.FindNodeResponse
+	}
+	return nil
+}
+
+func (x *Packet) GetFindValueRequest() *FindValueRequest {
+	if x, ok := x.GetBody().(*Packet_FindValueRequest); ok {
+		return x.FindValueRequest
+	}
+	return nil
+}
+
+func (x *Packet) GetFindValueResponse() *FindValueResponse {
+	if x, ok := x.GetBody().(*Packet_FindValueResponse); ok {
+		return x.FindValueResponse
+	}
+	return nil
+}
+
+func (x *Packet) GetAddProviderRequest() *AddProviderRequest {
+	if x, ok := x.GetBody().(*Packet_AddProviderRequest); ok {
+		return x.AddProviderRequest
+	}
+	return nil
+}
+
+func (x *Packet) GetAddProviderResponse() *AddProviderResponse {
+	if x, ok := x.GetBody().(*Packet_AddProviderResponse); ok {
+		return x.AddProviderResponse
+	}
+	return nil
+}
+
+func (x *Packet) GetGetProvidersRequest() *GetProvidersRequest {
+	if x, ok := x.GetBody().(*Packet_GetProvidersRequest); ok {
+		return x.GetProvidersRequest
+	}
+	return nil
+}
+
+func (x *Packet) GetGetProvidersResponse() *GetProvidersResponse {
+	if x, ok := x.GetBody().(*Packet_GetProvidersResponse); ok {
+		return x.GetProvidersResponse
+	}
+	return nil
+}
+
+func (x *Packet) GetError() *PacketError {
+	if x, ok := x.GetBody().(*Packet_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *Packet) GetRequestChunk() []byte {
+	if x, ok := x.GetBody().(*Packet_RequestChunk); ok {
+		return x.RequestChunk
+	}
+	return nil
+}
+
+func (x *Packet) GetResponseChunk() []byte {
+	if x, ok := x.GetBody().(*Packet_ResponseChunk); ok {
+		return x.ResponseChunk
+	}
+	return nil
+}
+
+type isPacket_Body interface {
+	isPacket_Body()
+}
+
+type Packet_PingRequest struct {
+	PingRequest *PingRequest `protobuf:"bytes,4,opt,name=ping_request,json=pingRequest,proto3,oneof"`
+}
+
+type Packet_PingResponse struct {
+	PingResponse *PingResponse `protobuf:"bytes,5,opt,name=ping_response,json=pingResponse,proto3,oneof"`
+}
+
+type Packet_StoreRequest struct {
+	StoreRequest *StoreRequest `protobuf:"bytes,6,opt,name=store_request,json=storeRequest,proto3,oneof"`
+}
+
+type Packet_StoreResponse struct {
+	StoreResponse *StoreResponse `protobuf:"bytes,7,opt,name=store_response,json=storeResponse,proto3,oneof"`
+}
+
+type Packet_FindNodeRequest struct {
+	FindNodeRequest *FindNodeRequest `protobuf:"bytes,8,opt,name=find_node_request,json=findNodeRequest,proto3,oneof"`
+}
+
+type Packet_FindNodeResponse struct {
+	FindNodeResponse *FindNodeResponse `protobuf:"bytes,9,opt,name=find_node_response,json=findNodeResponse,proto3,oneof"`
+}
+
+type Packet_FindValueRequest struct {
+	FindValueRequest *FindValueRequest `protobuf:"bytes,10,opt,name=find_value_request,json=findValueRequest,proto3,oneof"`
+}
+
+type Packet_FindValueResponse struct {
+	FindValueResponse *FindValueResponse `protobuf:"bytes,11,opt,name=find_value_response,json=findValueResponse,proto3,oneof"`
+}
+
+type Packet_AddProviderRequest struct {
+	AddProviderRequest *AddProviderRequest `protobuf:"bytes,12,opt,name=add_provider_request,json=addProviderRequest,proto3,oneof"`
+}
+
+type Packet_AddProviderResponse struct {
+	AddProviderResponse *AddProviderResponse `protobuf:"bytes,13,opt,name=add_provider_response,json=addProviderResponse,proto3,oneof"`
+}
+
+type Packet_GetProvidersRequest struct {
+	GetProvidersRequest *GetProvidersRequest `protobuf:"bytes,14,opt,name=get_providers_request,json=getProvidersRequest,proto3,oneof"`
+}
+
+type Packet_GetProvidersResponse struct {
+	GetProvidersResponse *GetProvidersResponse `protobuf:"bytes,15,opt,name=get_providers_response,json=getProvidersResponse,proto3,oneof"`
+}
+
+type Packet_Error struct {
+	Error *PacketError `protobuf:"bytes,16,opt,name=error,proto3,oneof"`
+}
+
+type Packet_RequestChunk struct {
+	RequestChunk []byte `protobuf:"bytes,17,opt,name=request_chunk,json=requestChunk,proto3,oneof"`
+}
+
+type Packet_ResponseChunk struct {
+	ResponseChunk []byte `protobuf:"bytes,18,opt,name=response_chunk,json=responseChunk,proto3,oneof"`
+}
+
+func (*Packet_PingRequest) isPacket_Body() {}
+
+func (*Packet_PingResponse) isPacket_Body() {}
+
+func (*Packet_StoreRequest) isPacket_Body() {}
+
+func (*Packet_StoreResponse) isPacket_Body() {}
+
+func (*Packet_FindNodeRequest) isPacket_Body() {}
+
+func (*Packet_FindNodeResponse) isPacket_Body() {}
+
+func (*Packet_FindValueRequest) isPacket_Body() {}
+
+func (*Packet_FindValueResponse) isPacket_Body() {}
+
+func (*Packet_AddProviderRequest) isPacket_Body() {}
+
+func (*Packet_AddProviderResponse) isPacket_Body() {}
+
+func (*Packet_GetProvidersRequest) isPacket_Body() {}
+
+func (*Packet_GetProvidersResponse) isPacket_Body() {}
+
+func (*Packet_Error) isPacket_Body() {}
+
+func (*Packet_RequestChunk) isPacket_Body() {}
+
+func (*Packet_ResponseChunk) isPacket_Body() {}
+
+// PacketError failed request, code is a grpc status code
+type PacketError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    uint32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *PacketError) Reset() {
+	*x = PacketError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_dht_dht_service_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PacketError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PacketError) ProtoMessage() {}
+
+func (x *PacketError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dht_dht_service_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PacketError.ProtoReflect.Descriptor instead.
+func (*PacketError) Descriptor() ([]byte, []int) {
+	return file_proto_dht_dht_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PacketError) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *PacketError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_proto_dht_dht_service_proto protoreflect.FileDescriptor
+
+var file_proto_dht_dht_service_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x68, 0x74, 0x2f, 0x64, 0x68, 0x74, 0x5f,
 	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x64,
-	0x68, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x22, 0x64, 0x0a, 0x06, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x1b, 0x0a,
-	0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x3d, 0x0a, 0x0c, 0x72, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x72, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x5c, 0x0a, 0x04, 0x45, 0x63, 0x68,
-	0x6f, 0x12, 0x15, 0x0a, 0x06, 0x72, 0x70, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x05, 0x72, 0x70, 0x63, 0x49, 0x64, 0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x70,
-	0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70,
-	0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x46, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x61,
-	0x63, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
-	0x69, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22,
-	0x35, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26,
-	0x0a, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
-	0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06,
-	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x22, 0x5e, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52,
+	0x68, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xeb, 0x01, 0x0a, 0x06, 0x53, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x3d,
+	0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x64,
+	0x76, 0x65, 0x72, 0x74, 0x69, 0x73, 0x65, 0x5f, 0x69, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x61, 0x64, 0x76, 0x65, 0x72, 0x74, 0x69, 0x73, 0x65, 0x49, 0x70, 0x12, 0x25, 0x0a,
+	0x0e, 0x61, 0x64, 0x76, 0x65, 0x72, 0x74, 0x69, 0x73, 0x65, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x61, 0x64, 0x76, 0x65, 0x72, 0x74, 0x69, 0x73, 0x65,
+	0x50, 0x6f, 0x72, 0x74, 0x22, 0x90, 0x01, 0x0a, 0x04, 0x45, 0x63, 0x68, 0x6f, 0x12, 0x15, 0x0a,
+	0x06, 0x72, 0x70, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x72,
+	0x70, 0x63, 0x49, 0x64, 0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x32, 0x0a, 0x0a, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x63, 0x6f, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x22, 0x4c, 0x0a, 0x0a, 0x43, 0x6f, 0x6f, 0x72, 0x64,
+	0x69, 0x6e, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x65, 0x63, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x01, 0x52, 0x03, 0x76, 0x65, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x46, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70,
+	0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x70, 0x6f, 0x72, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x35, 0x0a,
+	0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x06,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64,
+	0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x73, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x22, 0x7f, 0x0a, 0x0c, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x63, 0x68, 0x6f,
+	0x52, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x12, 0x2c, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x64, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64,
+	0x5f, 0x69, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x62, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x64, 0x49, 0x70, 0x22, 0x9e, 0x02, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e,
 	0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x10,
 	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79,
 	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x31, 0x0a, 0x0d, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45,
-	0x63, 0x68, 0x6f, 0x52, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x22, 0x52, 0x0a, 0x0f, 0x46, 0x69, 0x6e,
-	0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x06,
-	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64,
-	0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x73, 0x65,
-	0x6e, 0x64, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x68, 0x0a,
-	0x10, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x20, 0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0c, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x63, 0x68, 0x6f, 0x52, 0x04, 0x65,
-	0x63, 0x68, 0x6f, 0x12, 0x32, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x5f, 0x6c,
-	0x69, 0x73, 0x74, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x64, 0x68, 0x74, 0x2e,
-	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74,
-	0x61, 0x63, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x3a, 0x0a, 0x10, 0x46, 0x69, 0x6e, 0x64, 0x56,
-	0x61, 0x6c, 0x75, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x73,
-	0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68,
-	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x73, 0x65, 0x6e,
-	0x64, 0x65, 0x72, 0x22, 0x35, 0x0a, 0x11, 0x46, 0x69, 0x6e, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73,
+	0x68, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x73, 0x68, 0x65, 0x72, 0x12, 0x3d, 0x0a, 0x0c, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x8c, 0x01, 0x0a, 0x0d, 0x53, 0x74, 0x6f, 0x72, 0x65,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e,
-	0x45, 0x63, 0x68, 0x6f, 0x52, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x32, 0x80, 0x02, 0x0a, 0x0a, 0x44,
-	0x48, 0x54, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x35, 0x0a, 0x04, 0x50, 0x69, 0x6e,
-	0x67, 0x12, 0x13, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00,
-	0x12, 0x36, 0x0a, 0x05, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x2e, 0x64, 0x68, 0x74, 0x2e,
-	0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x15, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x08, 0x46, 0x69, 0x6e, 0x64,
-	0x4e, 0x6f, 0x64, 0x65, 0x12, 0x17, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
-	0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
-	0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x09, 0x46, 0x69, 0x6e,
-	0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x18, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e,
-	0x46, 0x69, 0x6e, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x19, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x56, 0x61,
-	0x6c, 0x75, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x29, 0x5a,
-	0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x74, 0x72, 0x75,
-	0x63, 0x74, 0x78, 0x2f, 0x67, 0x6f, 0x2d, 0x64, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2f, 0x64, 0x68, 0x74, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x45, 0x63, 0x68, 0x6f, 0x52, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x12, 0x2b, 0x0a, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x64, 0x68, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2c, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x64, 0x65, 0x72, 0x22, 0x52, 0x0a, 0x0f, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x96, 0x01, 0x0a, 0x10, 0x46, 0x69,
+	0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20,
+	0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x64,
+	0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x63, 0x68, 0x6f, 0x52, 0x04, 0x65, 0x63, 0x68, 0x6f,
+	0x12, 0x32, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x5f, 0x6c, 0x69, 0x73, 0x74,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x64, 0x65,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x64,
+	0x65, 0x72, 0x22, 0x4c, 0x0a, 0x10, 0x46, 0x69, 0x6e, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x22, 0xef, 0x01, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72,
+	0x12, 0x3d, 0x0a, 0x0c, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0b, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65,
+	0x71, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x73, 0x61, 0x6c, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x22, 0x3a, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x4c, 0x69, 0x73,
+	0x74, 0x12, 0x2b, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e,
+	0x74, 0x61, 0x63, 0x74, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x73, 0x22, 0xce,
+	0x01, 0x0a, 0x11, 0x46, 0x69, 0x6e, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x63, 0x68, 0x6f,
+	0x52, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x12, 0x25, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x48, 0x00, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x38, 0x0a,
+	0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e,
+	0x74, 0x61, 0x63, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x63, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x64, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x64, 0x65, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22,
+	0x81, 0x01, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x12, 0x2b, 0x0a, 0x03, 0x74, 0x74, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x03, 0x74, 0x74, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x4b, 0x65, 0x79, 0x22, 0x7c, 0x0a, 0x12, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x22, 0x92, 0x01, 0x0a, 0x13, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x65, 0x63, 0x68,
+	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x45, 0x63, 0x68, 0x6f, 0x52, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x12, 0x2b, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x64, 0x68,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2c, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x09, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x64, 0x65, 0x72, 0x22, 0x4f, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f,
+	0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a,
+	0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x73,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0xca, 0x01, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x20, 0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x63, 0x68, 0x6f, 0x52, 0x04, 0x65, 0x63,
+	0x68, 0x6f, 0x12, 0x2e, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x73, 0x12, 0x32, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x5f, 0x6c, 0x69,
+	0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61,
+	0x63, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x64, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x68, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x64, 0x65, 0x72, 0x22, 0xce, 0x08, 0x0a, 0x06, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x06, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x1a,
+	0x0a, 0x08, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x08, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72,
+	0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x66,
+	0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x38, 0x0a, 0x0c, 0x70, 0x69, 0x6e, 0x67,
+	0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x70, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x3b, 0x0a, 0x0d, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x68, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48,
+	0x00, 0x52, 0x0c, 0x70, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3b, 0x0a, 0x0d, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0c,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3e, 0x0a, 0x0e,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0d, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x11,
+	0x66, 0x69, 0x6e, 0x64, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x48, 0x00, 0x52, 0x0f, 0x66, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x48, 0x0a, 0x12, 0x66, 0x69, 0x6e, 0x64, 0x5f, 0x6e, 0x6f, 0x64, 0x65,
+	0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x10, 0x66, 0x69, 0x6e,
+	0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a,
+	0x12, 0x66, 0x69, 0x6e, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x68, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x10, 0x66, 0x69, 0x6e, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x4b, 0x0a, 0x13, 0x66, 0x69, 0x6e, 0x64, 0x5f,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
+	0x6e, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48,
+	0x00, 0x52, 0x11, 0x66, 0x69, 0x6e, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x14, 0x61, 0x64, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00,
+	0x52, 0x12, 0x61, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x51, 0x0a, 0x15, 0x61, 0x64, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x48, 0x00, 0x52, 0x13, 0x61, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x15, 0x67, 0x65, 0x74, 0x5f, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x13, 0x67, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x54, 0x0a, 0x16, 0x67, 0x65,
+	0x74, 0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x5f, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x64, 0x68, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x14, 0x67, 0x65, 0x74, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2b, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x25, 0x0a,
+	0x0d, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x11,
+	0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x12, 0x27, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x0d,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x06, 0x0a,
+	0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0x3b, 0x0a, 0x0b, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x2a, 0x94, 0x01, 0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x17, 0x0a, 0x13, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x5f, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x44, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x54, 0x4f,
+	0x52, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4f, 0x55, 0x54, 0x44, 0x41, 0x54,
+	0x45, 0x44, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x55, 0x53, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x10, 0x03, 0x12, 0x19,
+	0x0a, 0x15, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x52,
+	0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x04, 0x32, 0x95, 0x03, 0x0a, 0x0a, 0x44, 0x48,
+	0x54, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x33, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67,
+	0x12, 0x13, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x36, 0x0a,
+	0x05, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x64,
+	0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x08, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64,
+	0x65, 0x12, 0x17, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4e,
+	0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x64, 0x68, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x09, 0x46, 0x69, 0x6e, 0x64, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x18, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6e,
+	0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
+	0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0b, 0x41, 0x64,
+	0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x1a, 0x2e, 0x64, 0x68, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41,
+	0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69,
+	0x64, 0x65, 0x72, 0x73, 0x12, 0x1b, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x68, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x78, 0x2f, 0x67, 0x6f, 0x2d, 0x64, 0x70, 0x6b, 0x67, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x68, 0x74, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -647,45 +2091,99 @@ func file_proto_dht_dht_service_proto_rawDescGZIP() []byte {
 	return file_proto_dht_dht_service_proto_rawDescData
 }
 
-var file_proto_dht_dht_service_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_proto_dht_dht_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_dht_dht_service_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_proto_dht_dht_service_proto_goTypes = []interface{}{
-	(*Sender)(nil),              // 0: dht.v1.Sender
-	(*Echo)(nil),                // 1: dht.v1.Echo
-	(*Contact)(nil),             // 2: dht.v1.Contact
-	(*PingRequest)(nil),         // 3: dht.v1.PingRequest
-	(*StoreRequest)(nil),        // 4: dht.v1.StoreRequest
-	(*StoreResponse)(nil),       // 5: dht.v1.StoreResponse
-	(*FindNodeRequest)(nil),     // 6: dht.v1.FindNodeRequest
-	(*FindNodeResponse)(nil),    // 7: dht.v1.FindNodeResponse
-	(*FindValueRequest)(nil),    // 8: dht.v1.FindValueRequest
-	(*FindValueResponse)(nil),   // 9: dht.v1.FindValueResponse
-	(*timestamp.Timestamp)(nil), // 10: google.protobuf.Timestamp
-	(*empty.Empty)(nil),         // 11: google.protobuf.Empty
+	(StoreStatus)(0),             // 0: dht.v1.StoreStatus
+	(*Sender)(nil),               // 1: dht.v1.Sender
+	(*Echo)(nil),                 // 2: dht.v1.Echo
+	(*Coordinate)(nil),           // 3: dht.v1.Coordinate
+	(*Contact)(nil),              // 4: dht.v1.Contact
+	(*PingRequest)(nil),          // 5: dht.v1.PingRequest
+	(*PingResponse)(nil),         // 6: dht.v1.PingResponse
+	(*StoreRequest)(nil),         // 7: dht.v1.StoreRequest
+	(*StoreResponse)(nil),        // 8: dht.v1.StoreResponse
+	(*FindNodeRequest)(nil),      // 9: dht.v1.FindNodeRequest
+	(*FindNodeResponse)(nil),     // 10: dht.v1.FindNodeResponse
+	(*FindValueRequest)(nil),     // 11: dht.v1.FindValueRequest
+	(*Value)(nil),                // 12: dht.v1.Value
+	(*ContactList)(nil),          // 13: dht.v1.ContactList
+	(*FindValueResponse)(nil),    // 14: dht.v1.FindValueResponse
+	(*Provider)(nil),             // 15: dht.v1.Provider
+	(*AddProviderRequest)(nil),   // 16: dht.v1.AddProviderRequest
+	(*AddProviderResponse)(nil),  // 17: dht.v1.AddProviderResponse
+	(*GetProvidersRequest)(nil),  // 18: dht.v1.GetProvidersRequest
+	(*GetProvidersResponse)(nil), // 19: dht.v1.GetProvidersResponse
+	(*Packet)(nil),               // 20: dht.v1.Packet
+	(*PacketError)(nil),          // 21: dht.v1.PacketError
+	(*timestamp.Timestamp)(nil),  // 22: google.protobuf.Timestamp
+	(*duration.Duration)(nil),    // 23: google.protobuf.Duration
 }
 var file_proto_dht_dht_service_proto_depIdxs = []int32{
-	10, // 0: dht.v1.Sender.requested_at:type_name -> google.protobuf.Timestamp
-	10, // 1: dht.v1.Echo.completed_at:type_name -> google.protobuf.Timestamp
-	0,  // 2: dht.v1.PingRequest.sender:type_name -> dht.v1.Sender
-	0,  // 3: dht.v1.StoreRequest.sender:type_name -> dht.v1.Sender
-	1,  // 4: dht.v1.StoreResponse.echo:type_name -> dht.v1.Echo
-	0,  // 5: dht.v1.FindNodeRequest.sender:type_name -> dht.v1.Sender
-	1,  // 6: dht.v1.FindNodeResponse.echo:type_name -> dht.v1.Echo
-	2,  // 7: dht.v1.FindNodeResponse.contact_list:type_name -> dht.v1.Contact
-	0,  // 8: dht.v1.FindValueRequest.sender:type_name -> dht.v1.Sender
-	1,  // 9: dht.v1.FindValueResponse.echo:type_name -> dht.v1.Echo
-	3,  // 10: dht.v1.DHTService.Ping:input_type -> dht.v1.PingRequest
-	4,  // 11: dht.v1.DHTService.Store:input_type -> dht.v1.StoreRequest
-	6,  // 12: dht.v1.DHTService.FindNode:input_type -> dht.v1.FindNodeRequest
-	8,  // 13: dht.v1.DHTService.FindValue:input_type -> dht.v1.FindValueRequest
-	11, // 14: dht.v1.DHTService.Ping:output_type -> google.protobuf.Empty
-	5,  // 15: dht.v1.DHTService.Store:output_type -> dht.v1.StoreResponse
-	7,  // 16: dht.v1.DHTService.FindNode:output_type -> dht.v1.FindNodeResponse
-	9,  // 17: dht.v1.DHTService.FindValue:output_type -> dht.v1.FindValueResponse
-	14, // [14:18] is the sub-list for method output_type
-	10, // [10:14] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	22, // 0: dht.v1.Sender.requested_at:type_name -> google.protobuf.Timestamp
+	22, // 1: dht.v1.Echo.completed_at:type_name -> google.protobuf.Timestamp
+	3,  // 2: dht.v1.Echo.coordinate:type_name -> dht.v1.Coordinate
+	1,  // 3: dht.v1.PingRequest.sender:type_name -> dht.v1.Sender
+	2,  // 4: dht.v1.PingResponse.echo:type_name -> dht.v1.Echo
+	1,  // 5: dht.v1.PingResponse.responder:type_name -> dht.v1.Sender
+	1,  // 6: dht.v1.StoreRequest.sender:type_name -> dht.v1.Sender
+	22, // 7: dht.v1.StoreRequest.published_at:type_name -> google.protobuf.Timestamp
+	2,  // 8: dht.v1.StoreResponse.echo:type_name -> dht.v1.Echo
+	0,  // 9: dht.v1.StoreResponse.status:type_name -> dht.v1.StoreStatus
+	1,  // 10: dht.v1.StoreResponse.responder:type_name -> dht.v1.Sender
+	1,  // 11: dht.v1.FindNodeRequest.sender:type_name -> dht.v1.Sender
+	2,  // 12: dht.v1.FindNodeResponse.echo:type_name -> dht.v1.Echo
+	4,  // 13: dht.v1.FindNodeResponse.contact_list:type_name -> dht.v1.Contact
+	1,  // 14: dht.v1.FindNodeResponse.responder:type_name -> dht.v1.Sender
+	1,  // 15: dht.v1.FindValueRequest.sender:type_name -> dht.v1.Sender
+	22, // 16: dht.v1.Value.published_at:type_name -> google.protobuf.Timestamp
+	4,  // 17: dht.v1.ContactList.contacts:type_name -> dht.v1.Contact
+	2,  // 18: dht.v1.FindValueResponse.echo:type_name -> dht.v1.Echo
+	12, // 19: dht.v1.FindValueResponse.value:type_name -> dht.v1.Value
+	13, // 20: dht.v1.FindValueResponse.contact_list:type_name -> dht.v1.ContactList
+	1,  // 21: dht.v1.FindValueResponse.responder:type_name -> dht.v1.Sender
+	4,  // 22: dht.v1.Provider.contact:type_name -> dht.v1.Contact
+	23, // 23: dht.v1.Provider.ttl:type_name -> google.protobuf.Duration
+	1,  // 24: dht.v1.AddProviderRequest.sender:type_name -> dht.v1.Sender
+	15, // 25: dht.v1.AddProviderRequest.provider:type_name -> dht.v1.Provider
+	2,  // 26: dht.v1.AddProviderResponse.echo:type_name -> dht.v1.Echo
+	0,  // 27: dht.v1.AddProviderResponse.status:type_name -> dht.v1.StoreStatus
+	1,  // 28: dht.v1.AddProviderResponse.responder:type_name -> dht.v1.Sender
+	1,  // 29: dht.v1.GetProvidersRequest.sender:type_name -> dht.v1.Sender
+	2,  // 30: dht.v1.GetProvidersResponse.echo:type_name -> dht.v1.Echo
+	15, // 31: dht.v1.GetProvidersResponse.providers:type_name -> dht.v1.Provider
+	4,  // 32: dht.v1.GetProvidersResponse.contact_list:type_name -> dht.v1.Contact
+	1,  // 33: dht.v1.GetProvidersResponse.responder:type_name -> dht.v1.Sender
+	5,  // 34: dht.v1.Packet.ping_request:type_name -> dht.v1.PingRequest
+	6,  // 35: dht.v1.Packet.ping_response:type_name -> dht.v1.PingResponse
+	7,  // 36: dht.v1.Packet.store_request:type_name -> dht.v1.StoreRequest
+	8,  // 37: dht.v1.Packet.store_response:type_name -> dht.v1.StoreResponse
+	9,  // 38: dht.v1.Packet.find_node_request:type_name -> dht.v1.FindNodeRequest
+	10, // 39: dht.v1.Packet.find_node_response:type_name -> dht.v1.FindNodeResponse
+	11, // 40: dht.v1.Packet.find_value_request:type_name -> dht.v1.FindValueRequest
+	14, // 41: dht.v1.Packet.find_value_response:type_name -> dht.v1.FindValueResponse
+	16, // 42: dht.v1.Packet.add_provider_request:type_name -> dht.v1.AddProviderRequest
+	17, // 43: dht.v1.Packet.add_provider_response:type_name -> dht.v1.AddProviderResponse
+	18, // 44: dht.v1.Packet.get_providers_request:type_name -> dht.v1.GetProvidersRequest
+	19, // 45: dht.v1.Packet.get_providers_response:type_name -> dht.v1.GetProvidersResponse
+	21, // 46: dht.v1.Packet.error:type_name -> dht.v1.PacketError
+	5,  // 47: dht.v1.DHTService.Ping:input_type -> dht.v1.PingRequest
+	7,  // 48: dht.v1.DHTService.Store:input_type -> dht.v1.StoreRequest
+	9,  // 49: dht.v1.DHTService.FindNode:input_type -> dht.v1.FindNodeRequest
+	11, // 50: dht.v1.DHTService.FindValue:input_type -> dht.v1.FindValueRequest
+	16, // 51: dht.v1.DHTService.AddProvider:input_type -> dht.v1.AddProviderRequest
+	18, // 52: dht.v1.DHTService.GetProviders:input_type -> dht.v1.GetProvidersRequest
+	6,  // 53: dht.v1.DHTService.Ping:output_type -> dht.v1.PingResponse
+	8,  // 54: dht.v1.DHTService.Store:output_type -> dht.v1.StoreResponse
+	10, // 55: dht.v1.DHTService.FindNode:output_type -> dht.v1.FindNodeResponse
+	14, // 56: dht.v1.DHTService.FindValue:output_type -> dht.v1.FindValueResponse
+	17, // 57: dht.v1.DHTService.AddProvider:output_type -> dht.v1.AddProviderResponse
+	19, // 58: dht.v1.DHTService.GetProviders:output_type -> dht.v1.GetProvidersResponse
+	53, // [53:59] is the sub-list for method output_type
+	47, // [47:53] is the sub-list for method input_type
+	47, // [47:47] is the sub-list for extension type_name
+	47, // [47:47] is the sub-list for extension extendee
+	0,  // [0:47] is the sub-list for field type_name
 }
 
 func init() { file_proto_dht_dht_service_proto_init() }
@@ -719,7 +2217,7 @@ func file_proto_dht_dht_service_proto_init() {
 			}
 		}
 		file_proto_dht_dht_service_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Contact); i {
+			switch v := v.(*Coordinate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -731,7 +2229,7 @@ func file_proto_dht_dht_service_proto_init() {
 			}
 		}
 		file_proto_dht_dht_service_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PingRequest); i {
+			switch v := v.(*Contact); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -743,7 +2241,7 @@ func file_proto_dht_dht_service_proto_init() {
 			}
 		}
 		file_proto_dht_dht_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StoreRequest); i {
+			switch v := v.(*PingRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -755,7 +2253,7 @@ func file_proto_dht_dht_service_proto_init() {
 			}
 		}
 		file_proto_dht_dht_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StoreResponse); i {
+			switch v := v.(*PingResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -767,7 +2265,7 @@ func file_proto_dht_dht_service_proto_init() {
 			}
 		}
 		file_proto_dht_dht_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FindNodeRequest); i {
+			switch v := v.(*StoreRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -779,7 +2277,7 @@ func file_proto_dht_dht_service_proto_init() {
 			}
 		}
 		file_proto_dht_dht_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FindNodeResponse); i {
+			switch v := v.(*StoreResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -791,7 +2289,7 @@ func file_proto_dht_dht_service_proto_init() {
 			}
 		}
 		file_proto_dht_dht_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FindValueRequest); i {
+			switch v := v.(*FindNodeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -803,6 +2301,54 @@ func file_proto_dht_dht_service_proto_init() {
 			}
 		}
 		file_proto_dht_dht_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FindNodeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_dht_dht_service_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FindValueRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_dht_dht_service_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Value); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_dht_dht_service_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContactList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_dht_dht_service_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*FindValueResponse); i {
 			case 0:
 				return &v.state
@@ -814,19 +2360,125 @@ func file_proto_dht_dht_service_proto_init() {
 				return nil
 			}
 		}
+		file_proto_dht_dht_service_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Provider); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_dht_dht_service_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddProviderRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_dht_dht_service_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddProviderResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_dht_dht_service_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetProvidersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_dht_dht_service_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetProvidersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_dht_dht_service_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Packet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_dht_dht_service_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PacketError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_proto_dht_dht_service_proto_msgTypes[13].OneofWrappers = []interface{}{
+		(*FindValueResponse_Value)(nil),
+		(*FindValueResponse_ContactList)(nil),
+	}
+	file_proto_dht_dht_service_proto_msgTypes[19].OneofWrappers = []interface{}{
+		(*Packet_PingRequest)(nil),
+		(*Packet_PingResponse)(nil),
+		(*Packet_StoreRequest)(nil),
+		(*Packet_StoreResponse)(nil),
+		(*Packet_FindNodeRequest)(nil),
+		(*Packet_FindNodeResponse)(nil),
+		(*Packet_FindValueRequest)(nil),
+		(*Packet_FindValueResponse)(nil),
+		(*Packet_AddProviderRequest)(nil),
+		(*Packet_AddProviderResponse)(nil),
+		(*Packet_GetProvidersRequest)(nil),
+		(*Packet_GetProvidersResponse)(nil),
+		(*Packet_Error)(nil),
+		(*Packet_RequestChunk)(nil),
+		(*Packet_ResponseChunk)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_dht_dht_service_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   10,
+			NumEnums:      1,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_dht_dht_service_proto_goTypes,
 		DependencyIndexes: file_proto_dht_dht_service_proto_depIdxs,
+		EnumInfos:         file_proto_dht_dht_service_proto_enumTypes,
 		MessageInfos:      file_proto_dht_dht_service_proto_msgTypes,
 	}.Build()
 	File_proto_dht_dht_service_proto = out.File
@@ -8,7 +8,6 @@ package v1
 
 import (
 	context "context"
-	empty "github.com/golang/protobuf/ptypes/empty"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -23,10 +22,12 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type DHTServiceClient interface {
-	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
 	Store(ctx context.Context, in *StoreRequest, opts ...grpc.CallOption) (*StoreResponse, error)
 	FindNode(ctx context.Context, in *FindNodeRequest, opts ...grpc.CallOption) (*FindNodeResponse, error)
 	FindValue(ctx context.Context, in *FindValueRequest, opts ...grpc.CallOption) (*FindValueResponse, error)
+	AddProvider(ctx context.Context, in *AddProviderRequest, opts ...grpc.CallOption) (*AddProviderResponse, error)
+	GetProviders(ctx context.Context, in *GetProvidersRequest, opts ...grpc.CallOption) (*GetProvidersResponse, error)
 }
 
 type dHTServiceClient struct {
@@ -37,8 +38,8 @@ func NewDHTServiceClient(cc grpc.ClientConnInterface) DHTServiceClient {
 	return &dHTServiceClient{cc}
 }
 
-func (c *dHTServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
+func (c *dHTServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	out := new(PingResponse)
 	err := c.cc.Invoke(ctx, "/dht.v1.DHTService/Ping", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -73,14 +74,34 @@ func (c *dHTServiceClient) FindValue(ctx context.Context, in *FindValueRequest,
 	return out, nil
 }
 
+func (c *dHTServiceClient) AddProvider(ctx context.Context, in *AddProviderRequest, opts ...grpc.CallOption) (*AddProviderResponse, error) {
+	out := new(AddProviderResponse)
+	err := c.cc.Invoke(ctx, "/dht.v1.DHTService/AddProvider", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dHTServiceClient) GetProviders(ctx context.Context, in *GetProvidersRequest, opts ...grpc.CallOption) (*GetProvidersResponse, error) {
+	out := new(GetProvidersResponse)
+	err := c.cc.Invoke(ctx, "/dht.v1.DHTService/GetProviders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DHTServiceServer is the server API for DHTService service.
 // All implementations must embed UnimplementedDHTServiceServer
 // for forward compatibility
 type DHTServiceServer interface {
-	Ping(context.Context, *PingRequest) (*empty.Empty, error)
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
 	Store(context.Context, *StoreRequest) (*StoreResponse, error)
 	FindNode(context.Context, *FindNodeRequest) (*FindNodeResponse, error)
 	FindValue(context.Context, *FindValueRequest) (*FindValueResponse, error)
+	AddProvider(context.Context, *AddProviderRequest) (*AddProviderResponse, error)
+	GetProviders(context.Context, *GetProvidersRequest) (*GetProvidersResponse, error)
 	mustEmbedUnimplementedDHTServiceServer()
 }
 
@@ -88,7 +109,7 @@ type DHTServiceServer interface {
 type UnimplementedDHTServiceServer struct {
 }
 
-func (UnimplementedDHTServiceServer) Ping(context.Context, *PingRequest) (*empty.Empty, error) {
+func (UnimplementedDHTServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
 func (UnimplementedDHTServiceServer) Store(context.Context, *StoreRequest) (*StoreResponse, error) {
@@ -100,6 +121,12 @@ func (UnimplementedDHTServiceServer) FindNode(context.Context, *FindNodeRequest)
 func (UnimplementedDHTServiceServer) FindValue(context.Context, *FindValueRequest) (*FindValueResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindValue not implemented")
 }
+func (UnimplementedDHTServiceServer) AddProvider(context.Context, *AddProviderRequest) (*AddProviderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProvider not implemented")
+}
+func (UnimplementedDHTServiceServer) GetProviders(context.Context, *GetProvidersRequest) (*GetProvidersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProviders not implemented")
+}
 func (UnimplementedDHTServiceServer) mustEmbedUnimplementedDHTServiceServer() {}
 
 // UnsafeDHTServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -185,6 +212,42 @@ func _DHTService_FindValue_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DHTService_AddProvider_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProviderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DHTServiceServer).AddProvider(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dht.v1.DHTService/AddProvider",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DHTServiceServer).AddProvider(ctx, req.(*AddProviderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DHTService_GetProviders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProvidersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DHTServiceServer).GetProviders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dht.v1.DHTService/GetProviders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DHTServiceServer).GetProviders(ctx, req.(*GetProvidersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DHTService_ServiceDesc is the grpc.ServiceDesc for DHTService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -208,6 +271,14 @@ var DHTService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FindValue",
 			Handler:    _DHTService_FindValue_Handler,
 		},
+		{
+			MethodName: "AddProvider",
+			Handler:    _DHTService_AddProvider_Handler,
+		},
+		{
+			MethodName: "GetProviders",
+			Handler:    _DHTService_GetProviders_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/dht/dht_service.proto",
@@ -3,296 +3,996 @@ package dht
 
 import (
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/structx/go-dpkg/domain"
-	"github.com/structx/go-dpkg/structs/tree"
-	"github.com/structx/go-dpkg/util/encode"
+)
+
+const (
+	// DefaultRecordTTL lifetime of a record after original publication
+	DefaultRecordTTL = time.Hour * 24
+	// DefaultRepublishInterval interval stored records are republished
+	DefaultRepublishInterval = time.Hour
+	// DefaultRefreshInterval interval after which buckets without lookups are refreshed
+	DefaultRefreshInterval = time.Hour
+	// DefaultProviderTTL lifetime of provider records
+	DefaultProviderTTL = time.Hour * 24
+	// ObservedAddrQuorum number of peers that must report the same
+	// external address before the node advertises it
+	ObservedAddrQuorum = 3
+	// expireInterval interval expired records are removed
+	expireInterval = time.Minute
+	// maxObservers peers whose observed address is remembered
+	maxObservers = 32
 )
 
 // Node kademlia distributed hash table node
-type Node struct {
-	ID           domain.NodeID224
-	routingTable *tree.BST
+// over node ids of width T
+type Node[T domain.NodeID] struct {
+	ID           T
+	pub          ed25519.PublicKey
+	routingTable *RoutingTable[T]
+	kv           domain.KV
+	values       *ValueStore[T]
+	providers    *ProviderStore[T]
+
+	mtx sync.Mutex
+	// contact advertised address of the local node
+	contact domain.Contact[T]
+	// advertised address was configured and is never replaced
+	// by the external address observed by peers
+	advertised bool
+	// observations external ip of the local node reported by peers
+	observations map[T]string
+	// provided keys announced by the local node
+	provided map[T]struct{}
 
 	replicationFactor int
+	alpha             int
+	// disjointPaths number of disjoint paths per lookup
+	disjointPaths int
+	// subnet limits of routing table
+	bucketSubnetLimit int
+	tableSubnetLimit  int
+
+	recordTTL         time.Duration
+	providerTTL       time.Duration
+	republishInterval time.Duration
+	refreshInterval   time.Duration
+	snapshotInterval  time.Duration
+
+	transport domain.Transport[T]
+	metrics   domain.DHTMetrics
+
+	// random source of refresh ids, nil for crypto/rand
+	random io.Reader
+	// syncEviction ping contacts before eviction during updates
+	syncEviction bool
+	// xorOrder ignore latency when choosing contacts to query
+	xorOrder bool
+	// vivaldi network coordinates, nil if not maintained
+	vivaldi *vivaldi[T]
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 // interface compliance
-var _ domain.DHT = (*Node)(nil)
+var _ domain.DHT[domain.NodeID224] = (*Node[domain.NodeID224])(nil)
+var _ domain.Inspector[domain.NodeID224] = (*Node[domain.NodeID224])(nil)
 
-// NewNode constructor
-func NewNode(ctx context.Context, ip string, port, replicationFactor int) *Node {
+// Option node configuration option
+type Option[T domain.NodeID] func(*Node[T])
 
-	addr := fmt.Sprintf("%s:%d", ip, port)
-	nodeID := encode.HashKey([]byte(addr))
+// WithTransport set transport used to query other nodes
+func WithTransport[T domain.NodeID](t domain.Transport[T]) Option[T] {
+	return func(n *Node[T]) {
+		n.transport = t
+	}
+}
 
-	var contactID domain.NodeID224 = nodeID
+// WithMetrics report lookups, evictions and republishing to metrics
+func WithMetrics[T domain.NodeID](m domain.DHTMetrics) Option[T] {
+	return func(n *Node[T]) {
+		n.metrics = m
+	}
+}
 
-	c := &domain.Contact{
-		IP:   ip,
-		Port: port,
-		ID:   contactID,
+// WithConcurrency set number of parallel queries during lookups
+func WithConcurrency[T domain.NodeID](alpha int) Option[T] {
+	return func(n *Node[T]) {
+		if alpha > 0 {
+			n.alpha = alpha
+		}
 	}
+}
 
-	bst := tree.NewBSTWithDefault()
-	bst.Run(ctx)
+// WithDisjointPaths run lookups over d disjoint paths, every contact
+// is queried by a single path so one malicious contact can not
+// steer all paths (S/Kademlia). d <= 1 disables disjoint lookups
+func WithDisjointPaths[T domain.NodeID](d int) Option[T] {
+	return func(n *Node[T]) {
+		n.disjointPaths = d
+	}
+}
 
-	bucketSlice := make([]*domain.Bucket, 0, replicationFactor)
-	for i := 0; i < replicationFactor; i++ {
-		bucketID := encode.MaskFromPrefix(nodeID, i)
+// WithSubnetLimits set maximum number of routing table contacts sharing
+// a /24 (ipv4) or /48 (ipv6) subnet per bucket and per table, zero disables a limit
+func WithSubnetLimits[T domain.NodeID](perBucket, perTable int) Option[T] {
+	return func(n *Node[T]) {
+		n.bucketSubnetLimit = perBucket
+		n.tableSubnetLimit = perTable
+	}
+}
 
-		bucketSlice = append(bucketSlice, &domain.Bucket{
-			ID:       bucketID,
-			Contacts: make([]*domain.Contact, 0),
-		})
+// WithStore persist records in key value database
+func WithStore[T domain.NodeID](kv domain.KV) Option[T] {
+	return func(n *Node[T]) {
+		n.kv = kv
 	}
+}
 
-	// Insert node contact only in the first bucket
-	bucketSlice[0].Contacts = append(bucketSlice[0].Contacts, c)
+// WithRecordTTL set lifetime of records after original publication
+func WithRecordTTL[T domain.NodeID](ttl time.Duration) Option[T] {
+	return func(n *Node[T]) {
+		if ttl > 0 {
+			n.recordTTL = ttl
+		}
+	}
+}
 
-	bst.Insert(context.TODO(), nodeID, bucketSlice)
+// WithProviderTTL set lifetime of provider records announced by the
+// node, also the maximum lifetime of provider records received
+func WithProviderTTL[T domain.NodeID](ttl time.Duration) Option[T] {
+	return func(n *Node[T]) {
+		if ttl > 0 {
+			n.providerTTL = ttl
+		}
+	}
+}
 
-	return &Node{
-		ID:                nodeID,
-		routingTable:      bst,
-		replicationFactor: replicationFactor,
+// WithRepublishInterval set interval stored records are republished
+func WithRepublishInterval[T domain.NodeID](interval time.Duration) Option[T] {
+	return func(n *Node[T]) {
+		if interval > 0 {
+			n.republishInterval = interval
+		}
 	}
 }
 
-// NewNodeWithDefault constructor with default values
-func NewNodeWithDefault(ctx context.Context, ip string, port int) *Node {
+// WithRefreshInterval set interval after which buckets without lookups are refreshed
+func WithRefreshInterval[T domain.NodeID](interval time.Duration) Option[T] {
+	return func(n *Node[T]) {
+		if interval > 0 {
+			n.refreshInterval = interval
+		}
+	}
+}
 
-	addr := fmt.Sprintf("%s:%d", ip, port)
-	nodeID := encode.HashKey([]byte(addr))
+// WithSnapshotInterval set interval the routing table is persisted
+func WithSnapshotInterval[T domain.NodeID](interval time.Duration) Option[T] {
+	return func(n *Node[T]) {
+		if interval > 0 {
+			n.snapshotInterval = interval
+		}
+	}
+}
 
-	var contactID domain.NodeID224 = nodeID
+// WithAdvertiseAddr set address advertised to other nodes when it
+// differs from the listen address, e.g. behind nat or in containers.
+// an empty ip keeps the listen ip and learns the external ip from peers
+func WithAdvertiseAddr[T domain.NodeID](ip string, port int) Option[T] {
+	return func(n *Node[T]) {
+		if ip != "" {
+			n.contact.IP = ip
+			n.advertised = true
+		}
+		if port > 0 {
+			n.contact.Port = port
+		}
+	}
+}
 
-	c := &domain.Contact{
-		Port: port,
-		ID:   contactID,
+// WithRandom set source of random ids used to refresh buckets,
+// a seeded source makes refresh lookups reproducible
+func WithRandom[T domain.NodeID](r io.Reader) Option[T] {
+	return func(n *Node[T]) {
+		n.random = r
 	}
-	c.SetID()
+}
 
-	bst := tree.NewBSTWithDefault()
-	bst.Run(ctx)
+// WithSyncEviction ping least recently seen contacts of full buckets
+// during routing table updates instead of in the background
+func WithSyncEviction[T domain.NodeID]() Option[T] {
+	return func(n *Node[T]) {
+		n.syncEviction = true
+	}
+}
+
+// NewNode constructor, the node id is derived from the ed25519 public
+// key the transport signs with, adapter/port/dht.LoadIdentity persists one
+func NewNode[T domain.NodeID](_ context.Context, ip string, port, replicationFactor int, pub ed25519.PublicKey, opts ...Option[T]) *Node[T] {
 
-	bucketSlice := make([]*domain.Bucket, 0, domain.DefaultReplicationFactor)
-	for i := 0; i < domain.DefaultReplicationFactor; i++ {
-		bucketID := encode.MaskFromPrefix(nodeID, i)
+	n := &Node[T]{
+		ID:                domain.NodeIDFromPublicKey[T](pub),
+		pub:               pub,
+		replicationFactor: replicationFactor,
+		alpha:             domain.Concurrent,
+		disjointPaths:     1,
+		bucketSubnetLimit: DefaultBucketSubnetLimit,
+		tableSubnetLimit:  DefaultTableSubnetLimit,
+		recordTTL:         DefaultRecordTTL,
+		providerTTL:       DefaultProviderTTL,
+		republishInterval: DefaultRepublishInterval,
+		refreshInterval:   DefaultRefreshInterval,
+		snapshotInterval:  DefaultSnapshotInterval,
+		contact:           domain.Contact[T]{IP: ip, Port: port},
+		observations:      make(map[T]string),
+		provided:          make(map[T]struct{}),
+		metrics:           nopMetrics{},
+	}
 
-		bucketSlice = append(bucketSlice, &domain.Bucket{
-			ID:       bucketID,
-			Contacts: make([]*domain.Contact, 0),
-		})
+	for _, opt := range opts {
+		opt(n)
 	}
 
-	// Insert node contact only in the first bucket
-	bucketSlice[0].Contacts = append(bucketSlice[0].Contacts, c)
+	n.contact.ID = n.ID
 
-	bst.Insert(context.TODO(), nodeID, bucketSlice)
+	if n.kv == nil {
+		n.kv = NewMemoryKV()
+	}
+	n.values = NewValueStore[T](n.kv)
+	n.values.onChange = n.metrics.ObserveRecords
+	n.providers = NewProviderStore[T](n.kv)
 
-	return &Node{
-		ID:                nodeID,
-		routingTable:      tree.NewBSTWithDefault(),
-		replicationFactor: domain.DefaultReplicationFactor,
+	var ping func(ctx context.Context, c *domain.Contact[T]) error
+	if n.transport != nil {
+		ping = n.ping
+	}
+	n.routingTable = NewRoutingTable(n.ID, replicationFactor, ping)
+	n.routingTable.syncPing = n.syncEviction
+	n.routingTable.onEvict = n.metrics.ObserveEviction
+	n.routingTable.SetSubnetLimits(n.bucketSubnetLimit, n.tableSubnetLimit)
+	if n.random != nil {
+		n.routingTable.random = n.random
 	}
+
+	return n
+}
+
+// NewNodeWithDefault constructor with default values,
+// an empty or unspecified ip is learned from peers
+func NewNodeWithDefault[T domain.NodeID](ctx context.Context, ip string, port int, pub ed25519.PublicKey, opts ...Option[T]) *Node[T] {
+	return NewNode[T](ctx, ip, port, domain.DefaultReplicationFactor, pub, opts...)
 }
 
-// FindKClosestBuckets iterate over buckets and find shortest distance to key
-func (n *Node) FindKClosestBuckets(ctx context.Context, key []byte) []domain.NodeID224 {
+// Contact getter advertised contact of local node
+func (n *Node[T]) Contact() *domain.Contact[T] {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
 
-	keyHash := encode.HashKey(key)
+	c := n.contact
+	return &c
+}
 
-	closestBuckets := make([]domain.NodeID224, 0, n.replicationFactor)
+// ReplicationFactor getter bucket size k of local node
+func (n *Node[T]) ReplicationFactor() int {
+	return n.replicationFactor
+}
 
-	targetBucketID := encode.MaskFromPrefix(keyHash, 0)
-	result := n.routingTable.Search(ctx, targetBucketID)
+// ObserveAddr external ip of the local node as seen by observer,
+// the advertised ip is replaced once a quorum of peers agree on
+// an address unless an advertise address was configured
+func (n *Node[T]) ObserveAddr(_ context.Context, observer T, ip string) {
 
-	if result == nil {
-		// no result found
-		// include self by default
-		return []domain.NodeID224{n.ID}
+	if observer == n.ID || unspecified(ip) {
+		return
 	}
 
-	bucketSlice, ok := result.GetValue().([]*domain.Bucket)
-	if !ok {
-		// invalid data structure found
-		// include self by default
-		return []domain.NodeID224{n.ID}
-	}
-
-	// targetBucketID := encode.MaskFromPrefix(hashKey, 0)
-	for _, levelBucket := range bucketSlice {
-
-		var bestDistance = [28]byte{
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF,
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.advertised {
+		return
+	}
+
+	if _, ok := n.observations[observer]; !ok && len(n.observations) >= maxObservers {
+		// forget an arbitrary peer to bound memory
+		for k := range n.observations {
+			delete(n.observations, k)
+			break
 		}
+	}
+	n.observations[observer] = ip
+
+	if ip == n.contact.IP {
+		return
+	}
 
-		distance := domain.Distance224(keyHash).XOR(levelBucket.ID)
-		if compareDistances(distance, bestDistance) < 0 {
-			closestBuckets = append(closestBuckets, levelBucket.ID)
+	var votes int
+	for _, observed := range n.observations {
+		if observed == ip {
+			votes++
 		}
+	}
 
+	if votes >= ObservedAddrQuorum {
+		n.contact.IP = ip
 	}
+}
 
-	return closestBuckets
+// RoutingTable getter routing table
+func (n *Node[T]) RoutingTable() *RoutingTable[T] {
+	return n.routingTable
 }
 
-// FindClosestNodes iterate over bucket and find shortest contact to key
-func (n *Node) FindClosestNodes(ctx context.Context, key []byte, bucketID domain.NodeID224) []string {
+// FindKClosestBuckets non empty buckets sorted by distance to key
+func (n *Node[T]) FindKClosestBuckets(_ context.Context, key []byte) []T {
+
+	keyHash := domain.HashKey[T](key)
 
-	keyHash := encode.HashKey(key)
-	closestNodes := make([]string, 0, n.replicationFactor)
+	bits := domain.Bits[T]()
+
+	closestBuckets := make([]T, 0, bits)
+	for i := 0; i < bits; i++ {
+		if len(n.routingTable.Bucket(i)) > 0 {
+			closestBuckets = append(closestBuckets, n.routingTable.BucketID(i))
+		}
+	}
 
-	result := n.routingTable.Search(ctx, bucketID)
-	if result == nil {
-		// no bucket was found
-		return []string{}
+	if len(closestBuckets) == 0 {
+		// no contacts known
+		// include self by default
+		return []T{n.ID}
 	}
 
-	contactSlice, ok := result.GetValue().([]*domain.Contact)
-	if !ok {
-		// invalid data structure found
-		return []string{}
+	sort.Slice(closestBuckets, func(i, j int) bool {
+		return domain.Compare(
+			domain.Distance(keyHash, closestBuckets[i]),
+			domain.Distance(keyHash, closestBuckets[j]),
+		) < 0
+	})
+
+	if len(closestBuckets) > n.replicationFactor {
+		closestBuckets = closestBuckets[:n.replicationFactor]
 	}
 
-	var bestDistance = [28]byte{
-		0xFF, 0xFF, 0xFF,
-		0xFF, 0xFF, 0xFF,
-		0xFF, 0xFF, 0xFF,
-		0xFF, 0xFF, 0xFF,
-		0xFF, 0xFF, 0xFF,
-		0xFF, 0xFF, 0xFF,
-		0xFF, 0xFF, 0xFF,
-		0xFF, 0xFF, 0xFF,
-		0xFF, 0xFF, 0xFF,
-		0xFF,
+	return closestBuckets
+}
+
+// FindClosestNodes contact addresses in bucket sorted by distance to key
+func (n *Node[T]) FindClosestNodes(_ context.Context, key []byte, bucketID T) []string {
+
+	keyHash := domain.HashKey[T](key)
+
+	contactSlice := n.routingTable.Bucket(n.routingTable.BucketIndex(bucketID))
+	sort.Slice(contactSlice, func(i, j int) bool {
+		return domain.Compare(
+			domain.Distance(keyHash, contactSlice[i].ID),
+			domain.Distance(keyHash, contactSlice[j].ID),
+		) < 0
+	})
+	if !n.xorOrder {
+		preferFast(keyHash, contactSlice, contactID[T], n.latency)
 	}
 
+	closestNodes := make([]string, 0, len(contactSlice))
 	for _, contact := range contactSlice {
-		distance := domain.Distance224(keyHash).XOR(contact.ID)
-		if compareDistances(distance, bestDistance) < 0 {
-			closestNodes = append(closestNodes, net.JoinHostPort(contact.IP, fmt.Sprintf("%d", contact.Port)))
-		}
+		closestNodes = append(closestNodes, contact.Address())
 	}
 
 	return closestNodes
 }
 
-// AddOrUpdateRoutingTable add or overwrite routing table
-func (n *Node) AddOrUpdateRoutingTable(ctx context.Context, c *domain.Contact) {
+// AddOrUpdateRoutingTable add contact or mark as recently seen
+func (n *Node[T]) AddOrUpdateRoutingTable(ctx context.Context, c *domain.Contact[T]) {
+	n.routingTable.Update(ctx, c)
+}
+
+// Get value from local store, otherwise lookup value
+// in network and cache it on the closest node without it
+func (n *Node[T]) Get(ctx context.Context, key []byte) ([]byte, error) {
+
+	keyHash := domain.HashKey[T](key)
+
+	record, err := n.values.Get(keyHash)
+	if err == nil {
+		return record.Value, nil
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to get record %v", err)
+	}
+
+	if n.transport == nil {
+		return nil, ErrNotFound
+	}
+
+	result, err := n.lookup(ctx, keyHash, true)
+	if errors.Is(err, ErrNoContacts) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed value lookup %w", err)
+	}
+
+	if result.record == nil {
+		return nil, ErrNotFound
+	}
+
+	n.cache(ctx, result)
+
+	return result.record.Value, nil
+}
+
+// Put value in local store and on the k closest nodes to key,
+// versioned after the local record only, see PutValue
+func (n *Node[T]) Put(ctx context.Context, key, value []byte) error {
+
+	keyHash := domain.HashKey[T](key)
+	record := &domain.Record[T]{
+		Key:       keyHash,
+		Value:     value,
+		Publisher: n.ID,
+		Timestamp: time.Now(),
+		Seq:       n.nextSeq(keyHash, false, nil),
+	}
+
+	if err := n.values.Put(record, record.Timestamp.Add(n.recordTTL)); err != nil {
+		return fmt.Errorf("failed to store record %v", err)
+	}
+
+	if n.transport == nil {
+		return nil
+	}
+
+	return n.publish(ctx, record)
+}
+
+// StoreRecord accept record from another node, records expire
+// sooner the more contacts are known closer to the key than self
+func (n *Node[T]) StoreRecord(_ context.Context, record *domain.Record[T]) error {
+	return n.values.Put(record, n.expiry(record))
+}
+
+// FindRecord record in local store
+func (n *Node[T]) FindRecord(_ context.Context, key T) (*domain.Record[T], error) {
+	return n.values.Get(key)
+}
+
+// Run start republishing and expiring stored records
+// and persisting the routing table
+func (n *Node[T]) Run(ctx context.Context) {
 
-	for i := 0; i < n.replicationFactor; i++ {
-		bucketID := encode.MaskFromPrefix(c.ID, i)
+	ctx, n.cancel = context.WithCancel(ctx)
 
-		if i == 0 {
-			n.routingTable.Insert(ctx, bucketID, &domain.Bucket{
-				ID:       bucketID,
-				Contacts: []*domain.Contact{c},
-			})
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		republish := time.NewTicker(n.republishInterval)
+		defer republish.Stop()
+
+		refresh := time.NewTicker(min(n.refreshInterval, time.Minute))
+		defer refresh.Stop()
+
+		expire := time.NewTicker(min(n.republishInterval, expireInterval))
+		defer expire.Stop()
+
+		snapshot := time.NewTicker(n.snapshotInterval)
+		defer snapshot.Stop()
+
+		// report records persisted by previous runs
+		if count, size, err := n.values.Stats(); err == nil {
+			n.metrics.ObserveRecords(count, size)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-republish.C:
+				_ = n.Republish(ctx)
+				_ = n.Reprovide(ctx)
+			case <-refresh.C:
+				_ = n.Refresh(ctx)
+			case now := <-expire.C:
+				_, _ = n.values.Expire(ctx, now)
+				_, _ = n.providers.Expire(ctx, now)
+			case <-snapshot.C:
+				_ = n.SaveRoutingTable()
+			}
+		}
+	}()
+}
+
+// Close stop republishing and expiring records
+// and persist the routing table
+func (n *Node[T]) Close() error {
+	if n.cancel != nil {
+		n.cancel()
+	}
+	n.wg.Wait()
+	return n.SaveRoutingTable()
+}
+
+// Republish stored records to the k closest nodes. records received
+// within the last interval are skipped since another node already
+// republished them, the original publisher refreshes the timestamp
+// before the record expires. signed records can only be refreshed
+// by their owner and expire unless put again
+func (n *Node[T]) Republish(ctx context.Context) error {
+
+	records, err := n.values.Records(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read records %v", err)
+	}
+
+	now := time.Now()
+
+	var (
+		errs      []error
+		published int
+	)
+	defer func() { n.metrics.ObserveRepublish(published, len(errs)) }()
+
+	for _, sr := range records {
+
+		if !sr.Expires.After(now) {
+			continue
+		}
+
+		record := sr.Record
+		if record.Publisher == n.ID && !record.Mutable() {
+			if now.Sub(record.Timestamp) >= n.recordTTL-n.republishInterval {
+				record.Timestamp = now
+				if err := n.values.Put(&record, now.Add(n.recordTTL)); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
+		} else if now.Sub(sr.StoredAt) < n.republishInterval {
 			continue
 		}
 
-		n.routingTable.Insert(ctx, bucketID, &domain.Bucket{
-			ID:       bucketID,
-			Contacts: make([]*domain.Contact, 0),
-		})
+		if n.transport == nil {
+			continue
+		}
+
+		if err := n.publish(ctx, &record); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		published++
 	}
+
+	return errors.Join(errs...)
 }
 
-// Get value
-func (n *Node) Get(ctx context.Context, key []byte) *domain.Bucket {
+// Provide announce local node as provider of key on the k closest
+// nodes, the key is announced again every republish interval
+func (n *Node[T]) Provide(ctx context.Context, key []byte) error {
 
-	keyHash := encode.HashKey(key)
+	provider := &domain.Provider[T]{
+		Key:       domain.HashKey[T](key),
+		Contact:   *n.Contact(),
+		TTL:       n.providerTTL,
+		PublicKey: n.pub,
+	}
 
-	result := n.routingTable.Search(ctx, keyHash)
-	if result == nil {
+	if err := n.providers.Add(provider); err != nil {
+		return fmt.Errorf("failed to add provider %v", err)
+	}
+
+	n.mtx.Lock()
+	n.provided[provider.Key] = struct{}{}
+	n.mtx.Unlock()
+
+	if n.transport == nil {
 		return nil
 	}
 
-	bucketSlice, ok := result.GetValue().([]*domain.Bucket)
-	if !ok {
+	return n.announce(ctx, provider)
+}
+
+// Reprovide announce every key provided by the local node again
+func (n *Node[T]) Reprovide(ctx context.Context) error {
+
+	n.mtx.Lock()
+	keys := make([]T, 0, len(n.provided))
+	for key := range n.provided {
+		keys = append(keys, key)
+	}
+	n.mtx.Unlock()
+
+	if n.transport == nil {
 		return nil
 	}
 
-	for _, levelBucket := range bucketSlice {
+	var errs []error
+	for _, key := range keys {
+		provider := &domain.Provider[T]{Key: key, Contact: *n.Contact(), TTL: n.providerTTL, PublicKey: n.pub}
+		if err := n.providers.Add(provider); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := n.announce(ctx, provider); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// FindProviders local providers of key followed by a lookup
+// collecting providers from the nodes closest to key
+func (n *Node[T]) FindProviders(ctx context.Context, key []byte, count int) ([]*domain.Provider[T], error) {
+
+	k := domain.HashKey[T](key)
+
+	providers, err := n.providers.Get(k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read providers %v", err)
+	}
 
-		var bestDistance = [28]byte{
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF,
+	if len(providers) >= count || n.transport == nil {
+		return limitProviders(providers, count), nil
+	}
+
+	result, err := n.lookupProviders(ctx, k, count)
+	if errors.Is(err, ErrNoContacts) {
+		return limitProviders(providers, count), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed provider lookup %w", err)
+	}
+
+	return limitProviders(mergeProviders(providers, result.providers), count), nil
+}
+
+// AddProvider accept provider record from another node,
+// the lifetime is capped at the provider ttl of the local node
+func (n *Node[T]) AddProvider(_ context.Context, provider *domain.Provider[T]) error {
+	p := *provider
+	p.TTL = min(p.TTL, n.providerTTL)
+	return n.providers.Add(&p)
+}
+
+// Providers provider records of key in local store
+func (n *Node[T]) Providers(_ context.Context, key T) ([]*domain.Provider[T], error) {
+	return n.providers.Get(key)
+}
+
+// Buckets non empty routing table buckets
+func (n *Node[T]) Buckets() []domain.BucketInfo[T] {
+	return n.routingTable.Buckets()
+}
+
+// TraceLookup node lookup over a single path recording every query,
+// the trace is returned with the queries sent so far if the lookup fails
+func (n *Node[T]) TraceLookup(ctx context.Context, target T) (*domain.LookupTrace[T], error) {
+
+	n.routingTable.Touch(target)
+
+	l := newLookup(n, target, false)
+	l.trace = &domain.LookupTrace[T]{Target: target, Started: time.Now()}
+
+	result, err := l.run(ctx, n.ClosestContacts(target, n.replicationFactor))
+	l.trace.Duration = time.Since(l.trace.Started)
+	n.observeLookup(l.trace.Started, result, err)
+	if err != nil {
+		return l.trace, fmt.Errorf("failed node lookup %w", err)
+	}
+	l.trace.Contacts = result.contacts
+
+	return l.trace, nil
+}
+
+// Records locally stored records with expiry
+func (n *Node[T]) Records(ctx context.Context) ([]*domain.StoredRecord[T], error) {
+	return n.values.Records(ctx)
+}
+
+// ClosestContacts known contacts sorted by distance to node id
+func (n *Node[T]) ClosestContacts(nodeID T, count int) []*domain.Contact[T] {
+	return n.routingTable.Closest(nodeID, count)
+}
+
+// Lookup iterative search for the k closest live contacts to node id
+func (n *Node[T]) Lookup(ctx context.Context, nodeID T) ([]*domain.Contact[T], error) {
+
+	result, err := n.lookup(ctx, nodeID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed node lookup %w", err)
+	}
+
+	return result.contacts, nil
+}
+
+// LookupValue iterative search for record stored under key,
+// returns closest contacts when no record was found
+func (n *Node[T]) LookupValue(ctx context.Context, key T) (*domain.Record[T], []*domain.Contact[T], error) {
+
+	result, err := n.lookup(ctx, key, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed value lookup %w", err)
+	}
+
+	return result.record, result.contacts, nil
+}
+
+// Bootstrap join network through seed nodes, responsive seeds are
+// added to the routing table followed by a lookup of the own id. buckets
+// farther away than the closest neighbor are refreshed afterwards.
+// contacts of the persisted routing table are tried first, seeds are
+// only contacted if fewer than k of them respond, or not all
+// contacts of a smaller snapshot
+func (n *Node[T]) Bootstrap(ctx context.Context, seeds []*domain.Contact[T]) error {
+
+	if n.transport == nil {
+		return ErrMissingTransport
+	}
+
+	// an unreadable snapshot falls back to the seeds
+	restored, saved, _ := n.RestoreRoutingTable(ctx)
+	if restored == 0 || restored < min(n.replicationFactor, saved) {
+		if err := n.addSeeds(ctx, seeds); err != nil {
+			return err
 		}
+	}
+
+	contactSlice, err := n.Lookup(ctx, n.ID)
+	if err != nil {
+		return fmt.Errorf("failed self lookup %w", err)
+	}
+
+	closest := domain.Bits[T]()
+	if len(contactSlice) > 0 {
+		closest = n.routingTable.BucketIndex(contactSlice[0].ID)
+	}
+
+	for i := 0; i < closest; i++ {
+		// refresh is best effort, buckets without
+		// reachable contacts are expected
+		_, _ = n.Lookup(ctx, n.routingTable.RandomID(i))
+	}
+
+	return nil
+}
 
-		for _, contact := range levelBucket.Contacts {
-			distance := domain.Distance224(levelBucket.ID).XOR(contact.ID)
-			if compareDistances(distance, bestDistance) < 0 {
-				return levelBucket
+// addSeeds ping seeds in parallel and add responsive seeds
+// to the routing table, fails if the routing table stays empty
+func (n *Node[T]) addSeeds(ctx context.Context, seeds []*domain.Contact[T]) error {
+
+	errs := make([]error, len(seeds))
+
+	var zero T
+	var wg sync.WaitGroup
+	for i, c := range seeds {
+		wg.Add(1)
+		go func(i int, c *domain.Contact[T]) {
+			defer wg.Done()
+
+			// seed ids are usually unknown and learned from the response
+			nodeID, err := n.transport.Ping(ctx, c)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to ping seed %s %w", c.Address(), err)
+				return
+			} else if c.ID != zero && c.ID != nodeID {
+				errs[i] = fmt.Errorf("seed %s %w", c.Address(), ErrIdentityMismatch)
+				return
+			} else if nodeID == n.ID {
+				errs[i] = fmt.Errorf("seed %s is local node", c.Address())
+				return
 			}
+
+			n.routingTable.Update(ctx, &domain.Contact[T]{IP: c.IP, Port: c.Port, ID: nodeID})
+		}(i, c)
+	}
+	wg.Wait()
+
+	if n.routingTable.Len() == 0 {
+		return fmt.Errorf("%w %w", ErrNoSeeds, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// Refresh lookup random id in range of every bucket
+// that has not seen a lookup within the refresh interval
+func (n *Node[T]) Refresh(ctx context.Context) error {
+
+	if n.transport == nil {
+		return ErrMissingTransport
+	}
+
+	var errs []error
+	for _, i := range n.routingTable.Stale(n.refreshInterval) {
+		if _, err := n.Lookup(ctx, n.routingTable.RandomID(i)); err != nil && !errors.Is(err, ErrNoContacts) {
+			errs = append(errs, err)
 		}
 	}
 
+	return errors.Join(errs...)
+}
+
+// ping contact and check the responder is the expected node
+func (n *Node[T]) ping(ctx context.Context, c *domain.Contact[T]) error {
+
+	sent := time.Now()
+
+	nodeID, err := n.transport.Ping(ctx, c)
+	if err != nil {
+		return err
+	} else if nodeID != c.ID {
+		return ErrIdentityMismatch
+	}
+
+	n.measured(c.ID, time.Since(sent))
+
 	return nil
 }
 
-// AddOrUpdateNode add or overwrite node records
-func (n *Node) AddOrUpdateNode(ctx context.Context, key []byte, value interface{}) {
+// lookup mark bucket of target as looked up and run iterative
+// lookup seeded with the closest contacts in the routing table
+func (n *Node[T]) lookup(ctx context.Context, target T, findValue bool) (*lookupResult[T], error) {
 
-	keyHash := encode.HashKey(key)
+	n.routingTable.Touch(target)
+	seeds := n.ClosestContacts(target, n.replicationFactor)
 
-	result := n.routingTable.Search(ctx, keyHash)
-	bucketSlice, ok := result.GetValue().([]*domain.Bucket)
-	if !ok {
-		return
+	start := time.Now()
+	if n.disjointPaths > 1 {
+		result, err := disjointLookup(ctx, n, target, findValue, seeds)
+		n.observeLookup(start, result, err)
+		return result, err
+	}
+
+	result, err := newLookup(n, target, findValue).run(ctx, seeds)
+	n.observeLookup(start, result, err)
+	return result, err
+}
+
+// observeLookup report duration and hops of lookup started at start
+func (n *Node[T]) observeLookup(start time.Time, result *lookupResult[T], err error) {
+	var hops int
+	if result != nil {
+		hops = result.hops
+	}
+	n.metrics.ObserveLookup(time.Since(start), hops, err)
+}
+
+// lookupProviders iterative lookup collecting providers of key
+func (n *Node[T]) lookupProviders(ctx context.Context, key T, count int) (*lookupResult[T], error) {
+
+	n.routingTable.Touch(key)
+
+	l := newLookup(n, key, false)
+	l.findProviders, l.count = true, count
+
+	start := time.Now()
+	result, err := l.run(ctx, n.ClosestContacts(key, n.replicationFactor))
+	n.observeLookup(start, result, err)
+	return result, err
+}
+
+// announce provider record to the k closest nodes,
+// succeeds if at least one node stored the record
+func (n *Node[T]) announce(ctx context.Context, provider *domain.Provider[T]) error {
+
+	contactSlice, err := n.Lookup(ctx, provider.Key)
+	if errors.Is(err, ErrNoContacts) {
+		// no other nodes known keep local record only
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(contactSlice))
+
+	var wg sync.WaitGroup
+	for i, c := range contactSlice {
+		wg.Add(1)
+		go func(i int, c *domain.Contact[T]) {
+			defer wg.Done()
+			errs[i] = n.transport.AddProvider(ctx, c, provider)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
 	}
 
-	for _, levelBucket := range bucketSlice {
+	return fmt.Errorf("failed to add provider on any contact %w", errors.Join(errs...))
+}
+
+// publish record to the k closest nodes, succeeds if at least
+// one node stored the record or already holds a newer one
+func (n *Node[T]) publish(ctx context.Context, record *domain.Record[T]) error {
+
+	contactSlice, err := n.Lookup(ctx, record.Key)
+	if errors.Is(err, ErrNoContacts) {
+		// no other nodes known keep local copy only
+		return nil
+	} else if err != nil {
+		return err
+	}
 
-		var bestDistance = [28]byte{
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF, 0xFF, 0xFF,
-			0xFF,
+	errs := n.storeReplicas(ctx, contactSlice, record)
+	for _, err := range errs {
+		if err == nil || errors.Is(err, ErrOutdated) {
+			return nil
 		}
+	}
+
+	return fmt.Errorf("failed to store record on any contact %w", errors.Join(errs...))
+}
 
-		distance := domain.Distance224(levelBucket.ID).XOR(keyHash)
-		if compareDistances(distance, bestDistance) < 0 {
-			n.routingTable.Insert(ctx, keyHash, value)
-			return
+// cache record on the closest contact that did not return it
+func (n *Node[T]) cache(ctx context.Context, result *lookupResult[T]) {
+	for _, c := range result.contacts {
+		if c.ID == result.holder.ID {
+			continue
 		}
+		_ = n.transport.Store(ctx, c, result.record)
+		return
 	}
 }
 
-func compareDistances(a, b domain.NodeID224) int {
-	for i := range a {
-		if a[i] < b[i] {
-			return -1
-		} else if a[i] > b[i] {
-			return 1
+// expiry of record received from another node, halved for every
+// known contact closer to the key than self and never later than
+// the lifetime after original publication
+func (n *Node[T]) expiry(record *domain.Record[T]) time.Time {
+
+	self := domain.Distance(record.Key, n.ID)
+
+	var closer int
+	for _, c := range n.routingTable.Closest(record.Key, n.replicationFactor) {
+		if domain.Compare(domain.Distance(record.Key, c.ID), self) < 0 {
+			closer++
 		}
 	}
-	return 0
+
+	expires := time.Now().Add(n.recordTTL >> uint(closer))
+	if latest := record.Timestamp.Add(n.recordTTL); latest.Before(expires) {
+		return latest
+	}
+
+	return expires
+}
+
+// observed contact responded to a query after rtt
+func (n *Node[T]) observed(c *domain.Contact[T], rtt time.Duration) {
+	n.routingTable.Update(context.TODO(), c)
+	n.measured(c.ID, rtt)
+}
+
+// nopMetrics discards events of nodes without metrics
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveLookup(time.Duration, int, error) {}
+
+func (nopMetrics) ObserveEviction(string) {}
+
+func (nopMetrics) ObserveRepublish(int, int) {}
+
+func (nopMetrics) ObserveRecords(int, int) {}
+
+// unspecified ip is empty or a wildcard listen address
+func unspecified(ip string) bool {
+	if ip == "" {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.IsUnspecified()
+}
+
+// unresponsive contact failed to respond to a query
+func (n *Node[T]) unresponsive(c *domain.Contact[T]) {
+	n.routingTable.Fail(c.ID)
 }
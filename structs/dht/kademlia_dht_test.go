@@ -3,8 +3,10 @@ package dht_test
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -17,7 +19,7 @@ func Test_FindKClosestBuckets(t *testing.T) {
 
 		assert := assert.New(t)
 		ctx := context.TODO()
-		n := dht.NewNode(ctx, "127.0.0.1", 50051, domain.DefaultReplicationFactor)
+		n := dht.NewNode[domain.NodeID224](ctx, "127.0.0.1", 50051, domain.DefaultReplicationFactor, keyOf("127.0.0.1:50051"))
 		bucketIDSlice := n.FindKClosestBuckets(ctx, []byte("127.0.0.1:50051"))
 		for _, bucketID := range bucketIDSlice {
 			assert.NotNil(bucketID)
@@ -31,12 +33,12 @@ func Test_FindClosestNodes(t *testing.T) {
 
 		assert := assert.New(t)
 		ctx := context.TODO()
-		n := dht.NewNode(ctx, "127.0.0.1", 50051, domain.DefaultReplicationFactor)
-		c := &domain.Contact{
+		n := dht.NewNode[domain.NodeID224](ctx, "127.0.0.1", 50051, domain.DefaultReplicationFactor, keyOf("127.0.0.1:50051"))
+		c := &domain.Contact[domain.NodeID224]{
 			IP:   "10.0.1.77",
 			Port: 50051,
 		}
-		c.SetID()
+		c.ID = domain.HashKey[domain.NodeID224]([]byte(c.Address()))
 
 		n.AddOrUpdateRoutingTable(ctx, c)
 
@@ -48,3 +50,250 @@ func Test_FindClosestNodes(t *testing.T) {
 		}
 	})
 }
+
+func Test_PutGet(t *testing.T) {
+	t.Run("closest", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw, contactSlice, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 32)
+
+		assert.NoError(nodeSlice[5].Put(ctx, []byte("key"), []byte("value")))
+
+		key := domain.HashKey[domain.NodeID224]([]byte("key"))
+		for _, nodeID := range closestIDs(key, contactSlice, domain.DefaultReplicationFactor) {
+			for _, c := range contactSlice {
+				if c.ID != nodeID {
+					continue
+				}
+				n, err := nw.node(c)
+				assert.NoError(err)
+
+				record, err := n.FindRecord(ctx, key)
+				assert.NoError(err)
+				assert.Equal(nodeSlice[5].ID, record.Publisher)
+			}
+		}
+
+		value, err := nodeSlice[20].Get(ctx, []byte("key"))
+		assert.NoError(err)
+		assert.Equal([]byte("value"), value)
+
+		_, err = nodeSlice[20].Get(ctx, []byte("missing"))
+		assert.ErrorIs(err, dht.ErrNotFound)
+	})
+	t.Run("cache", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw := newNetwork[domain.NodeID224]()
+		ca, cb, cc := newContact(0), newContact(1), newContact(2)
+		a, b, c := nw.join(ctx, ca), nw.join(ctx, cb), nw.join(ctx, cc)
+
+		// c only knows a, a knows the holder b
+		c.AddOrUpdateRoutingTable(ctx, ca)
+		a.AddOrUpdateRoutingTable(ctx, cb)
+
+		key := domain.HashKey[domain.NodeID224]([]byte("key"))
+		assert.NoError(b.StoreRecord(ctx, &domain.Record[domain.NodeID224]{
+			Key:       key,
+			Value:     []byte("value"),
+			Publisher: b.ID,
+			Timestamp: time.Now(),
+		}))
+
+		value, err := c.Get(ctx, []byte("key"))
+		assert.NoError(err)
+		assert.Equal([]byte("value"), value)
+
+		// cached on the contact queried before the holder
+		record, err := a.FindRecord(ctx, key)
+		assert.NoError(err)
+		assert.Equal(b.ID, record.Publisher)
+	})
+}
+
+func Test_Providers(t *testing.T) {
+	t.Run("closest", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw, contactSlice, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 32)
+
+		assert.NoError(nodeSlice[5].Provide(ctx, []byte("content")))
+		assert.NoError(nodeSlice[9].Provide(ctx, []byte("content")))
+
+		key := domain.HashKey[domain.NodeID224]([]byte("content"))
+		for _, nodeID := range closestIDs(key, contactSlice, domain.DefaultReplicationFactor) {
+			for _, c := range contactSlice {
+				if c.ID != nodeID {
+					continue
+				}
+				n, err := nw.node(c)
+				assert.NoError(err)
+
+				providers, err := n.Providers(ctx, key)
+				assert.NoError(err)
+				assert.Len(providers, 2)
+			}
+		}
+
+		providers, err := nodeSlice[20].FindProviders(ctx, []byte("content"), 2)
+		assert.NoError(err)
+		assert.Len(providers, 2)
+		assert.ElementsMatch(
+			[]string{contactSlice[5].Address(), contactSlice[9].Address()},
+			[]string{providers[0].Contact.Address(), providers[1].Contact.Address()},
+		)
+
+		providers, err = nodeSlice[20].FindProviders(ctx, []byte("missing"), 2)
+		assert.NoError(err)
+		assert.Empty(providers)
+	})
+	t.Run("ttl_capped", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		n := dht.NewNodeWithDefault(ctx, "127.0.0.1", 50051, keyOf("127.0.0.1:50051"), dht.WithProviderTTL[domain.NodeID224](time.Minute))
+		key := domain.HashKey[domain.NodeID224]([]byte("content"))
+		assert.NoError(n.AddProvider(ctx, &domain.Provider[domain.NodeID224]{Key: key, Contact: *newContact(1), TTL: time.Hour}))
+
+		providers, err := n.Providers(ctx, key)
+		assert.NoError(err)
+		assert.Len(providers, 1)
+		assert.LessOrEqual(providers[0].TTL, time.Minute)
+	})
+}
+
+func Test_Republish(t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	nw := newNetwork[domain.NodeID224]()
+	ca, cb := newContact(0), newContact(1)
+	a, b := nw.join(ctx, ca), nw.join(ctx, cb)
+
+	// no contacts known record is only stored locally
+	assert.NoError(a.Put(ctx, []byte("key"), []byte("value")))
+
+	key := domain.HashKey[domain.NodeID224]([]byte("key"))
+	_, err := b.FindRecord(ctx, key)
+	assert.ErrorIs(err, dht.ErrNotFound)
+
+	a.AddOrUpdateRoutingTable(ctx, cb)
+	assert.NoError(a.Republish(ctx))
+
+	record, err := b.FindRecord(ctx, key)
+	assert.NoError(err)
+	assert.Equal(a.ID, record.Publisher)
+}
+
+func Test_Expiry(t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	ttl := time.Millisecond * 400
+	record := &domain.Record[domain.NodeID224]{
+		Key:       domain.HashKey[domain.NodeID224]([]byte("key")),
+		Value:     []byte("value"),
+		Timestamp: time.Now(),
+	}
+
+	// record expires sooner on a node that knows closer contacts
+	near := dht.NewNode[domain.NodeID224](ctx, "10.1.0.1", 50051, domain.DefaultReplicationFactor, keyOf("10.1.0.1:50051"), dht.WithRecordTTL[domain.NodeID224](ttl))
+	far := dht.NewNode[domain.NodeID224](ctx, "10.1.0.2", 50051, domain.DefaultReplicationFactor, keyOf("10.1.0.2:50051"), dht.WithRecordTTL[domain.NodeID224](ttl))
+	for _, c := range closestIDs(record.Key, contactsN(64), domain.DefaultReplicationFactor) {
+		far.AddOrUpdateRoutingTable(ctx, &domain.Contact[domain.NodeID224]{IP: "10.2.0.1", Port: 50051, ID: c})
+	}
+
+	assert.NoError(near.StoreRecord(ctx, record))
+	assert.NoError(far.StoreRecord(ctx, record))
+
+	assert.Eventually(func() bool {
+		_, err := far.FindRecord(ctx, record.Key)
+		return errors.Is(err, dht.ErrNotFound)
+	}, ttl/2, time.Millisecond*10)
+
+	_, err := near.FindRecord(ctx, record.Key)
+	assert.NoError(err)
+}
+
+func contactsN(n int) []*domain.Contact[domain.NodeID224] {
+	contactSlice := make([]*domain.Contact[domain.NodeID224], 0, n)
+	for i := 0; i < n; i++ {
+		contactSlice = append(contactSlice, newContact(i))
+	}
+	return contactSlice
+}
+
+func Test_ObserveAddr(t *testing.T) {
+
+	peers := func() []domain.NodeID224 {
+		ids := make([]domain.NodeID224, dht.ObservedAddrQuorum)
+		for i := range ids {
+			ids[i] = domain.HashKey[domain.NodeID224]([]byte(fmt.Sprintf("peer%d", i)))
+		}
+		return ids
+	}()
+
+	t.Run("quorum", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		n := dht.NewNodeWithDefault[domain.NodeID224](ctx, "0.0.0.0", 50051, keyOf("0.0.0.0:50051"))
+		assert.Equal("0.0.0.0", n.Contact().IP)
+
+		n.ObserveAddr(ctx, peers[0], "")
+		for _, peer := range peers[:len(peers)-1] {
+			n.ObserveAddr(ctx, peer, "203.0.113.7")
+			// same peer reporting twice is a single vote
+			n.ObserveAddr(ctx, peer, "203.0.113.7")
+		}
+		assert.Equal("0.0.0.0", n.Contact().IP)
+
+		n.ObserveAddr(ctx, peers[len(peers)-1], "203.0.113.7")
+		assert.Equal("203.0.113.7", n.Contact().IP)
+		assert.Equal(50051, n.Contact().Port)
+		assert.Equal(n.ID, n.Contact().ID)
+	})
+
+	t.Run("advertised", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		n := dht.NewNodeWithDefault[domain.NodeID224](ctx, "0.0.0.0", 50051, keyOf("0.0.0.0:50051"),
+			dht.WithAdvertiseAddr[domain.NodeID224]("198.51.100.1", 4000))
+		for _, peer := range peers {
+			n.ObserveAddr(ctx, peer, "203.0.113.7")
+		}
+
+		assert.Equal(&domain.Contact[domain.NodeID224]{IP: "198.51.100.1", Port: 4000, ID: n.ID}, n.Contact())
+	})
+
+	t.Run("provider", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		n := dht.NewNodeWithDefault[domain.NodeID224](ctx, "0.0.0.0", 50051, keyOf("0.0.0.0:50051"),
+			dht.WithAdvertiseAddr[domain.NodeID224]("", 4000))
+		for _, peer := range peers {
+			n.ObserveAddr(ctx, peer, "203.0.113.7")
+		}
+
+		assert.NoError(n.Provide(ctx, []byte("content")))
+		providers, err := n.FindProviders(ctx, []byte("content"), 1)
+		assert.NoError(err)
+		assert.Len(providers, 1)
+		assert.Equal("203.0.113.7", providers[0].Contact.IP)
+		assert.Equal(4000, providers[0].Contact.Port)
+	})
+}
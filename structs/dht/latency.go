@@ -0,0 +1,301 @@
+package dht
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+const (
+	// vivaldiDimensions dimensions of coordinate vectors
+	vivaldiDimensions = 8
+	// vivaldiErrorMax error of a new coordinate
+	vivaldiErrorMax = 1.5
+	// vivaldiCE weight of a new sample on the error estimate
+	vivaldiCE = 0.25
+	// vivaldiCC weight of a new sample on the coordinate
+	vivaldiCC = 0.25
+	// vivaldiHeightMin smallest height in seconds
+	vivaldiHeightMin = 10e-6
+	// vivaldiZero distances below are treated as zero
+	vivaldiZero = 1e-6
+	// maxCoordinates remote coordinates remembered
+	maxCoordinates = 1024
+)
+
+// WithVivaldi maintain vivaldi network coordinates, latency to
+// contacts without measured round trip time is estimated from the
+// coordinates they report
+func WithVivaldi[T domain.NodeID]() Option[T] {
+	return func(n *Node[T]) {
+		n.vivaldi = newVivaldi[T]()
+	}
+}
+
+// WithXOROrder query contacts in xor distance order only, ignoring
+// their latency. lookups do not depend on measured round trip times
+// which keeps them reproducible, e.g. in simulations
+func WithXOROrder[T domain.NodeID]() Option[T] {
+	return func(n *Node[T]) {
+		n.xorOrder = true
+	}
+}
+
+// vivaldi coordinate of the local node and coordinates reported by contacts
+type vivaldi[T domain.NodeID] struct {
+	mtx    sync.Mutex
+	self   domain.Coordinate
+	remote map[T]domain.Coordinate
+}
+
+func newVivaldi[T domain.NodeID]() *vivaldi[T] {
+	return &vivaldi[T]{
+		self:   newCoordinate(),
+		remote: make(map[T]domain.Coordinate),
+	}
+}
+
+// newCoordinate coordinate at the origin with maximum error
+func newCoordinate() domain.Coordinate {
+	return domain.Coordinate{
+		Vec:    make([]float64, vivaldiDimensions),
+		Height: vivaldiHeightMin,
+		Error:  vivaldiErrorMax,
+	}
+}
+
+// Coordinate network coordinate of local node, nil without WithVivaldi
+func (n *Node[T]) Coordinate() *domain.Coordinate {
+
+	if n.vivaldi == nil {
+		return nil
+	}
+
+	n.vivaldi.mtx.Lock()
+	defer n.vivaldi.mtx.Unlock()
+
+	c := cloneCoordinate(n.vivaldi.self)
+	return &c
+}
+
+// ObserveCoordinate remember network coordinate reported by contact,
+// coordinates of unexpected dimensions or with invalid values are ignored
+func (n *Node[T]) ObserveCoordinate(_ context.Context, nodeID T, c *domain.Coordinate) {
+
+	if n.vivaldi == nil || nodeID == n.ID || !validCoordinate(c) {
+		return
+	}
+
+	n.vivaldi.mtx.Lock()
+	defer n.vivaldi.mtx.Unlock()
+
+	if _, ok := n.vivaldi.remote[nodeID]; !ok && len(n.vivaldi.remote) >= maxCoordinates {
+		// forget an arbitrary contact to bound memory
+		for k := range n.vivaldi.remote {
+			delete(n.vivaldi.remote, k)
+			break
+		}
+	}
+	n.vivaldi.remote[nodeID] = cloneCoordinate(*c)
+}
+
+// measured contact responded after rtt, updates the smoothed round trip
+// time and moves the local coordinate towards the observed latency
+func (n *Node[T]) measured(nodeID T, rtt time.Duration) {
+
+	n.routingTable.RecordRTT(nodeID, rtt)
+
+	if n.vivaldi == nil || rtt <= 0 {
+		return
+	}
+
+	n.vivaldi.mtx.Lock()
+	defer n.vivaldi.mtx.Unlock()
+
+	if remote, ok := n.vivaldi.remote[nodeID]; ok {
+		n.vivaldi.self = updateCoordinate(n.vivaldi.self, remote, rtt.Seconds())
+	}
+}
+
+// latency measured round trip time of contact, estimated from network
+// coordinates if it was never measured. false if neither is known
+func (n *Node[T]) latency(nodeID T) (time.Duration, bool) {
+
+	if rtt := n.routingTable.RTT(nodeID); rtt > 0 {
+		return rtt, true
+	}
+
+	if n.vivaldi == nil {
+		return 0, false
+	}
+
+	n.vivaldi.mtx.Lock()
+	defer n.vivaldi.mtx.Unlock()
+
+	remote, ok := n.vivaldi.remote[nodeID]
+	if !ok {
+		return 0, false
+	}
+
+	return time.Duration(distance(n.vivaldi.self, remote) * float64(time.Second)), true
+}
+
+// preferFast order items sorted by distance to target so that those at
+// comparable distance, sharing the same number of leading zero bits, are
+// ordered by latency. items of unknown latency follow those of known
+// latency and otherwise keep their order
+func preferFast[T domain.NodeID, E any](target T, s []E, id func(E) T, latency func(T) (time.Duration, bool)) {
+
+	type ranked struct {
+		item   E
+		prefix int
+		rtt    time.Duration
+		known  bool
+	}
+
+	rs := make([]ranked, len(s))
+	for i, e := range s {
+		nodeID := id(e)
+		rtt, ok := latency(nodeID)
+		rs[i] = ranked{item: e, prefix: commonPrefixLen(target, nodeID), rtt: rtt, known: ok}
+	}
+
+	sort.SliceStable(rs, func(i, j int) bool {
+		a, b := rs[i], rs[j]
+		switch {
+		case a.prefix != b.prefix:
+			return a.prefix > b.prefix
+		case a.known != b.known:
+			return a.known
+		}
+		return a.known && a.rtt < b.rtt
+	})
+
+	for i := range rs {
+		s[i] = rs[i].item
+	}
+}
+
+// updateCoordinate move local coordinate towards or away from remote
+// so their distance approaches the observed rtt in seconds
+func updateCoordinate(local, remote domain.Coordinate, rtt float64) domain.Coordinate {
+
+	dist := distance(local, remote)
+
+	// weight the sample by the relative confidence of both coordinates
+	weight := local.Error / max(local.Error+remote.Error, vivaldiZero)
+
+	sampleError := math.Abs(dist-rtt) / rtt
+	local.Error = min(sampleError*vivaldiCE*weight+local.Error*(1-vivaldiCE*weight), vivaldiErrorMax)
+
+	force := vivaldiCC * weight * (rtt - dist)
+	return applyForce(local, remote, force)
+}
+
+// applyForce push coordinate away from other by force seconds,
+// negative forces pull it towards other
+func applyForce(c, other domain.Coordinate, force float64) domain.Coordinate {
+
+	unit, mag := unitVector(c.Vec, other.Vec)
+
+	vec := make([]float64, len(c.Vec))
+	for i := range vec {
+		vec[i] = c.Vec[i] + unit[i]*force
+	}
+
+	height := c.Height
+	if mag > vivaldiZero {
+		height = max((c.Height+other.Height)*force/mag+c.Height, vivaldiHeightMin)
+	}
+
+	return domain.Coordinate{Vec: vec, Height: height, Error: c.Error}
+}
+
+// unitVector direction from b to a and the distance between them,
+// a random direction if both are at the same place
+func unitVector(a, b []float64) ([]float64, float64) {
+
+	diff := make([]float64, len(a))
+	for i := range diff {
+		diff[i] = a[i] - b[i]
+	}
+
+	if mag := magnitude(diff); mag > vivaldiZero {
+		for i := range diff {
+			diff[i] /= mag
+		}
+		return diff, mag
+	}
+
+	for i := range diff {
+		diff[i] = rand.Float64() - 0.5
+	}
+	if mag := magnitude(diff); mag > vivaldiZero {
+		for i := range diff {
+			diff[i] /= mag
+		}
+		return diff, 0
+	}
+
+	// unlucky random direction, push along the first axis
+	clear(diff)
+	diff[0] = 1
+	return diff, 0
+}
+
+// distance estimated round trip time between coordinates in seconds
+func distance(a, b domain.Coordinate) float64 {
+
+	diff := make([]float64, len(a.Vec))
+	for i := range diff {
+		diff[i] = a.Vec[i] - b.Vec[i]
+	}
+
+	return magnitude(diff) + a.Height + b.Height
+}
+
+func magnitude(v []float64) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+// validCoordinate coordinate of the expected dimensions with finite values
+func validCoordinate(c *domain.Coordinate) bool {
+
+	if c == nil || len(c.Vec) != vivaldiDimensions {
+		return false
+	}
+
+	finite := func(f float64) bool {
+		return !math.IsNaN(f) && !math.IsInf(f, 0)
+	}
+
+	for _, x := range c.Vec {
+		if !finite(x) {
+			return false
+		}
+	}
+
+	return finite(c.Height) && c.Height >= 0 && finite(c.Error) && c.Error >= 0
+}
+
+func cloneCoordinate(c domain.Coordinate) domain.Coordinate {
+	c.Vec = append([]float64(nil), c.Vec...)
+	return c
+}
+
+func contactID[T domain.NodeID](c *domain.Contact[T]) T {
+	return c.ID
+}
+
+func candidateID[T domain.NodeID](c *candidate[T]) T {
+	return c.contact.ID
+}
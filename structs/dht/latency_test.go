@@ -0,0 +1,131 @@
+package dht_test
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structx/go-dpkg/domain"
+	"github.com/structx/go-dpkg/structs/dht"
+)
+
+// prefixLen leading zero bits of the distance between a and b
+func prefixLen(a, b domain.NodeID224) int {
+	d := domain.Distance(a, b)
+	for i := range d {
+		if d[i] != 0 {
+			return i*8 + bits.LeadingZeros8(d[i])
+		}
+	}
+	return len(d) * 8
+}
+
+// comparableTarget target whose two closest known contacts of n are at
+// comparable distance, returns the target and both contacts
+func comparableTarget(t *testing.T, n *dht.Node[domain.NodeID224]) (domain.NodeID224, *domain.Contact[domain.NodeID224], *domain.Contact[domain.NodeID224]) {
+
+	for i := 0; i < 1000; i++ {
+		target := domain.HashKey[domain.NodeID224]([]byte(fmt.Sprintf("target-%d", i)))
+		closest := n.ClosestContacts(target, 2)
+		if len(closest) == 2 && prefixLen(target, closest[0].ID) == prefixLen(target, closest[1].ID) {
+			return target, closest[0], closest[1]
+		}
+	}
+
+	t.Fatal("no target with comparable contacts")
+	return domain.NodeID224{}, nil, nil
+}
+
+func Test_LatencyOrder(t *testing.T) {
+	t.Run("rtt", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw, contactSlice, _ := bootstrap[domain.NodeID224](ctx, t, 32)
+
+		for _, opts := range [][]dht.Option[domain.NodeID224]{{}, {dht.WithXOROrder[domain.NodeID224]()}} {
+
+			n := nw.join(ctx, contactOf[domain.NodeID224](100), append(opts, dht.WithConcurrency[domain.NodeID224](1))...)
+			for _, c := range contactSlice {
+				n.AddOrUpdateRoutingTable(ctx, c)
+			}
+
+			target, closest, fast := comparableTarget(t, n)
+			for _, c := range contactSlice {
+				n.RoutingTable().RecordRTT(c.ID, time.Millisecond*300)
+			}
+			n.RoutingTable().RecordRTT(closest.ID, time.Millisecond*200)
+			n.RoutingTable().RecordRTT(fast.ID, time.Millisecond*10)
+
+			trace, err := n.TraceLookup(ctx, target)
+			assert.NoError(err)
+
+			if len(opts) == 0 {
+				// faster contact at comparable distance is queried first
+				assert.Equal(fast.ID, trace.Queries[0].Contact.ID)
+			} else {
+				assert.Equal(closest.ID, trace.Queries[0].Contact.ID)
+			}
+		}
+	})
+	t.Run("vivaldi", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw, contactSlice, _ := bootstrap[domain.NodeID224](ctx, t, 32)
+
+		n := nw.join(ctx, contactOf[domain.NodeID224](100), dht.WithVivaldi[domain.NodeID224](), dht.WithConcurrency[domain.NodeID224](1))
+		for _, c := range contactSlice {
+			n.AddOrUpdateRoutingTable(ctx, c)
+		}
+
+		coordinate := func(x float64) *domain.Coordinate {
+			return &domain.Coordinate{Vec: []float64{x, 0, 0, 0, 0, 0, 0, 0}, Height: 10e-6, Error: 0.1}
+		}
+
+		// latency of contacts never measured is estimated from coordinates
+		target, closest, fast := comparableTarget(t, n)
+		n.ObserveCoordinate(ctx, closest.ID, coordinate(0.5))
+		n.ObserveCoordinate(ctx, fast.ID, coordinate(0.01))
+
+		trace, err := n.TraceLookup(ctx, target)
+		assert.NoError(err)
+		assert.Equal(fast.ID, trace.Queries[0].Contact.ID)
+	})
+}
+
+func Test_Vivaldi(t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	nw, contactSlice, _ := bootstrap[domain.NodeID224](ctx, t, 8)
+
+	assert.Nil(nw.join(ctx, contactOf[domain.NodeID224](100)).Coordinate())
+
+	n := nw.join(ctx, contactOf[domain.NodeID224](101), dht.WithVivaldi[domain.NodeID224]())
+	initial := n.Coordinate()
+	assert.Len(initial.Vec, 8)
+
+	// coordinates of other dimensions are ignored
+	n.ObserveCoordinate(ctx, contactSlice[0].ID, &domain.Coordinate{Vec: []float64{1}, Error: 0.1})
+	assert.NoError(n.Bootstrap(ctx, contactSlice[:1]))
+	assert.Equal(initial, n.Coordinate())
+
+	// seed reported a distant coordinate but responded quickly
+	n.ObserveCoordinate(ctx, contactSlice[0].ID, &domain.Coordinate{Vec: []float64{1, 0, 0, 0, 0, 0, 0, 0}, Height: 10e-6, Error: 0.1})
+	assert.NoError(n.Bootstrap(ctx, contactSlice[:1]))
+
+	moved := n.Coordinate()
+	assert.Greater(moved.Vec[0], 0.0)
+
+	// returned coordinates are copies
+	moved.Vec[0] = -1
+	assert.Greater(n.Coordinate().Vec[0], 0.0)
+}
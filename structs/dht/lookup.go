@@ -0,0 +1,473 @@
+package dht
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+var (
+	// ErrNoContacts routing table has no contacts to start a lookup from
+	ErrNoContacts = errors.New("no contacts available for lookup")
+	// ErrMissingTransport node was created without a transport
+	ErrMissingTransport = errors.New("missing dht transport")
+	// ErrNoSeeds none of the seed nodes responded
+	ErrNoSeeds = errors.New("no seed node responded")
+	// ErrIdentityMismatch contact responded with a different node id
+	ErrIdentityMismatch = errors.New("contact node id mismatch")
+)
+
+// candidateState progress of a contact during lookup
+type candidateState int
+
+const (
+	unqueried candidateState = iota
+	inflight
+	responded
+	failedQuery
+)
+
+type candidate[T domain.NodeID] struct {
+	contact  *domain.Contact[T]
+	distance T
+	state    candidateState
+	// hop number of responses on the path to the contact
+	hop int
+}
+
+// queryResult response of a single contact
+type queryResult[T domain.NodeID] struct {
+	c         *candidate[T]
+	record    *domain.Record[T]
+	providers []*domain.Provider[T]
+	contacts  []*domain.Contact[T]
+	err       error
+
+	sent time.Time
+	rtt  time.Duration
+}
+
+// lookupResult outcome of lookup
+type lookupResult[T domain.NodeID] struct {
+	// record found during value lookup
+	record *domain.Record[T]
+	// holder contact that returned the record
+	holder *domain.Contact[T]
+	// contacts k closest contacts that responded
+	contacts []*domain.Contact[T]
+	// providers collected during provider lookup
+	providers []*domain.Provider[T]
+	// hops responses on the path to the closest contact
+	hops int
+}
+
+// lookup iterative kademlia node lookup
+//
+// the shortlist is kept sorted by xor distance to the target,
+// each round sends alpha queries in parallel to the closest
+// unqueried contacts. once a round fails to find a closer contact
+// every unqueried contact within the k closest is queried, the lookup
+// ends when the k closest contacts have all been queried
+type lookup[T domain.NodeID] struct {
+	target    T
+	self      T
+	k         int
+	alpha     int
+	findValue bool
+	transport domain.Transport[T]
+
+	// findProviders collect providers until count were found
+	findProviders bool
+	count         int
+	providers     []*domain.Provider[T]
+
+	// called for each contact that responded or failed to respond
+	onResponse func(c *domain.Contact[T], rtt time.Duration)
+	onFailure  func(c *domain.Contact[T])
+	// latency of contact used to order queries, nil for xor order only
+	latency func(nodeID T) (time.Duration, bool)
+
+	// trace records every query, nil if not traced
+	trace *domain.LookupTrace[T]
+
+	shortlist []*candidate[T]
+	seen      map[T]struct{}
+
+	// claims contacts taken by disjoint paths, nil for a single path
+	claims *claims[T]
+	path   int
+}
+
+// claims contacts shared by disjoint lookup paths,
+// the first path to learn a contact owns it
+type claims[T domain.NodeID] struct {
+	mtx sync.Mutex
+	m   map[T]int
+}
+
+// claim contact for path, false if owned by another path
+func (c *claims[T]) claim(nodeID T, path int) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if owner, ok := c.m[nodeID]; ok {
+		return owner == path
+	}
+	c.m[nodeID] = path
+	return true
+}
+
+func newLookup[T domain.NodeID](n *Node[T], target T, findValue bool) *lookup[T] {
+
+	var latency func(nodeID T) (time.Duration, bool)
+	if !n.xorOrder {
+		latency = n.latency
+	}
+
+	return &lookup[T]{
+		target:     target,
+		self:       n.ID,
+		k:          n.replicationFactor,
+		alpha:      n.alpha,
+		findValue:  findValue,
+		transport:  n.transport,
+		onResponse: n.observed,
+		onFailure:  n.unresponsive,
+		latency:    latency,
+		shortlist:  make([]*candidate[T], 0, n.replicationFactor),
+		seen:       make(map[T]struct{}),
+	}
+}
+
+// add contacts learned at hop to shortlist ignoring self and duplicates
+func (l *lookup[T]) add(contactSlice []*domain.Contact[T], hop int) {
+
+	for _, c := range contactSlice {
+		if c == nil || c.ID == l.self {
+			continue
+		}
+		if _, ok := l.seen[c.ID]; ok {
+			continue
+		}
+		l.seen[c.ID] = struct{}{}
+
+		if l.claims != nil && !l.claims.claim(c.ID, l.path) {
+			// contact belongs to another path
+			continue
+		}
+
+		l.shortlist = append(l.shortlist, &candidate[T]{
+			contact:  c,
+			distance: domain.Distance(l.target, c.ID),
+			state:    unqueried,
+			hop:      hop,
+		})
+	}
+
+	sort.SliceStable(l.shortlist, func(i, j int) bool {
+		return domain.Compare(l.shortlist[i].distance, l.shortlist[j].distance) < 0
+	})
+}
+
+// next unqueried contacts among the k closest that have not failed,
+// contacts at comparable distance are queried fastest first
+func (l *lookup[T]) next(limit int) []*candidate[T] {
+
+	batch := make([]*candidate[T], 0, l.k)
+	considered := 0
+
+	for _, c := range l.shortlist {
+		if considered == l.k {
+			break
+		}
+		if c.state == failedQuery {
+			continue
+		}
+		considered++
+
+		if c.state == unqueried {
+			batch = append(batch, c)
+		}
+	}
+
+	if l.latency != nil {
+		preferFast(l.target, batch, candidateID[T], l.latency)
+	}
+
+	return batch[:min(limit, len(batch))]
+}
+
+// closest distance of any contact that has not failed
+func (l *lookup[T]) closest() (T, bool) {
+	for _, c := range l.shortlist {
+		if c.state != failedQuery {
+			return c.distance, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// result k closest contacts that responded
+func (l *lookup[T]) result() []*domain.Contact[T] {
+
+	contactSlice := make([]*domain.Contact[T], 0, l.k)
+	for _, c := range l.shortlist {
+		if len(contactSlice) == l.k {
+			break
+		}
+		if c.state == responded {
+			contactSlice = append(contactSlice, c.contact)
+		}
+	}
+
+	return contactSlice
+}
+
+// run lookup seeded with contacts from local routing table
+func (l *lookup[T]) run(ctx context.Context, seeds []*domain.Contact[T]) (*lookupResult[T], error) {
+
+	if l.transport == nil {
+		return nil, ErrMissingTransport
+	}
+
+	l.add(seeds, 1)
+	if len(l.shortlist) == 0 {
+		return nil, ErrNoContacts
+	}
+
+	limit := l.alpha
+	best, _ := l.closest()
+
+	for {
+		batch := l.next(limit)
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, r := range l.query(ctx, batch) {
+
+			l.record(r)
+
+			if r.err != nil {
+				r.c.state = failedQuery
+				// queries cut short by cancellation or the deadline
+				// of the lookup say nothing about the contact
+				if ctx.Err() == nil {
+					l.onFailure(r.c.contact)
+				}
+				continue
+			}
+
+			r.c.state = responded
+			l.onResponse(r.c.contact, r.rtt)
+
+			if l.findValue && r.record != nil {
+				return &lookupResult[T]{
+					record:   r.record,
+					holder:   r.c.contact,
+					contacts: l.result(),
+					hops:     r.c.hop,
+				}, nil
+			}
+
+			l.add(r.contacts, r.c.hop+1)
+
+			if l.findProviders {
+				l.providers = mergeProviders(l.providers, r.providers)
+				if len(l.providers) >= l.count {
+					return &lookupResult[T]{
+						contacts:  l.result(),
+						providers: l.providers,
+						hops:      l.hops(),
+					}, nil
+				}
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// closer contact found continue with alpha parallel queries,
+		// otherwise query all remaining contacts in the k closest
+		current, ok := l.closest()
+		if ok && domain.Compare(current, best) < 0 {
+			best = current
+			limit = l.alpha
+		} else {
+			limit = l.k
+		}
+	}
+
+	contactSlice := l.result()
+	if len(contactSlice) == 0 {
+		return nil, ErrNoContacts
+	}
+
+	return &lookupResult[T]{contacts: contactSlice, providers: l.providers, hops: l.hops()}, nil
+}
+
+// hops of the closest contact that responded
+func (l *lookup[T]) hops() int {
+	for _, c := range l.shortlist {
+		if c.state == responded {
+			return c.hop
+		}
+	}
+	return 0
+}
+
+// query batch of contacts in parallel
+func (l *lookup[T]) query(ctx context.Context, batch []*candidate[T]) []*queryResult[T] {
+
+	results := make([]*queryResult[T], len(batch))
+
+	var wg sync.WaitGroup
+	for i, c := range batch {
+		c.state = inflight
+
+		wg.Add(1)
+		go func(i int, c *candidate[T]) {
+			defer wg.Done()
+
+			r := &queryResult[T]{c: c, sent: time.Now()}
+			switch {
+			case l.findProviders:
+				r.providers, r.contacts, r.err = l.transport.GetProviders(ctx, c.contact, l.target)
+			case l.findValue:
+				r.record, r.contacts, r.err = l.transport.FindValue(ctx, c.contact, l.target)
+				if r.err == nil && r.record != nil {
+					if err := verifyRecord(r.record, l.target); err != nil {
+						r.record, r.contacts, r.err = nil, nil, fmt.Errorf("invalid record %w", err)
+					}
+				}
+			default:
+				r.contacts, r.err = l.transport.FindNode(ctx, c.contact, l.target)
+			}
+			r.rtt = time.Since(r.sent)
+			results[i] = r
+		}(i, c)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// record query result in trace
+func (l *lookup[T]) record(r *queryResult[T]) {
+
+	if l.trace == nil {
+		return
+	}
+
+	l.trace.Queries = append(l.trace.Queries, domain.QueryTrace[T]{
+		Contact:  *r.c.contact,
+		Hop:      r.c.hop,
+		Sent:     r.sent.Sub(l.trace.Started),
+		RTT:      r.rtt,
+		Returned: r.contacts,
+		Err:      r.err,
+	})
+}
+
+// disjointLookup split seeds over d paths that never query the same
+// contact, the record of the first path that found one is returned,
+// otherwise the k closest contacts that responded on any path
+func disjointLookup[T domain.NodeID](ctx context.Context, n *Node[T], target T, findValue bool, seeds []*domain.Contact[T]) (*lookupResult[T], error) {
+
+	d := min(n.disjointPaths, len(seeds))
+	if d <= 1 {
+		return newLookup(n, target, findValue).run(ctx, seeds)
+	}
+
+	shared := &claims[T]{m: make(map[T]int, len(seeds))}
+
+	paths := make([][]*domain.Contact[T], d)
+	for i, c := range seeds {
+		paths[i%d] = append(paths[i%d], c)
+		shared.m[c.ID] = i % d
+	}
+
+	results := make([]*lookupResult[T], d)
+	errs := make([]error, d)
+
+	var wg sync.WaitGroup
+	for i := range paths {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			l := newLookup(n, target, findValue)
+			l.claims, l.path = shared, i
+			results[i], errs[i] = l.run(ctx, paths[i])
+		}(i)
+	}
+	wg.Wait()
+
+	contactSlice := make([]*domain.Contact[T], 0, n.replicationFactor*d)
+	var found *lookupResult[T]
+	for i, r := range results {
+		if errs[i] != nil {
+			continue
+		}
+		if found == nil && r.record != nil {
+			found = r
+		}
+		contactSlice = append(contactSlice, r.contacts...)
+	}
+
+	if len(contactSlice) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sort.SliceStable(contactSlice, func(i, j int) bool {
+		return domain.Compare(domain.Distance(target, contactSlice[i].ID), domain.Distance(target, contactSlice[j].ID)) < 0
+	})
+	if len(contactSlice) > n.replicationFactor {
+		contactSlice = contactSlice[:n.replicationFactor]
+	}
+
+	if found != nil {
+		return &lookupResult[T]{record: found.record, holder: found.holder, contacts: contactSlice, hops: found.hops}, nil
+	}
+
+	// hops of the path that found the closest contact
+	var hops int
+	for i, r := range results {
+		if errs[i] == nil && len(r.contacts) > 0 && r.contacts[0].ID == contactSlice[0].ID {
+			hops = r.hops
+		}
+	}
+
+	return &lookupResult[T]{contacts: contactSlice, hops: hops}, nil
+}
+
+// mergeProviders append providers of contacts not yet included
+func mergeProviders[T domain.NodeID](providers, more []*domain.Provider[T]) []*domain.Provider[T] {
+	for _, p := range more {
+		known := false
+		for _, q := range providers {
+			if q.Contact.ID == p.Contact.ID {
+				known = true
+				break
+			}
+		}
+		if !known {
+			providers = append(providers, p)
+		}
+	}
+	return providers
+}
+
+func limitProviders[T domain.NodeID](providers []*domain.Provider[T], count int) []*domain.Provider[T] {
+	if count > 0 && len(providers) > count {
+		return providers[:count]
+	}
+	return providers
+}
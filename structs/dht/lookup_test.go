@@ -0,0 +1,495 @@
+package dht_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structx/go-dpkg/domain"
+	"github.com/structx/go-dpkg/structs/dht"
+)
+
+var errUnreachable = errors.New("unreachable")
+
+// network in-memory dht network
+type network[T domain.NodeID] struct {
+	mtx   sync.RWMutex
+	nodes map[string]*dht.Node[T]
+	// queries number of find queries between two nodes
+	queries map[[2]T]int
+	// stalled find node queries block until cancelled
+	stalled bool
+}
+
+func newNetwork[T domain.NodeID]() *network[T] {
+	return &network[T]{
+		nodes:   make(map[string]*dht.Node[T]),
+		queries: make(map[[2]T]int),
+	}
+}
+
+func (nw *network[T]) join(ctx context.Context, c *domain.Contact[T], opts ...dht.Option[T]) *dht.Node[T] {
+
+	opts = append([]dht.Option[T]{dht.WithTransport[T](&memTransport[T]{nw: nw, self: c})}, opts...)
+	n := dht.NewNode[T](ctx, c.IP, c.Port, domain.DefaultReplicationFactor, keyOf(c.Address()), opts...)
+
+	nw.mtx.Lock()
+	nw.nodes[c.Address()] = n
+	nw.mtx.Unlock()
+
+	return n
+}
+
+func (nw *network[T]) leave(c *domain.Contact[T]) {
+	nw.mtx.Lock()
+	delete(nw.nodes, c.Address())
+	nw.mtx.Unlock()
+}
+
+// queried count find query from node to contact
+func (nw *network[T]) queried(from, to T) {
+	nw.mtx.Lock()
+	nw.queries[[2]T{from, to}]++
+	nw.mtx.Unlock()
+}
+
+func (nw *network[T]) isStalled() bool {
+	nw.mtx.RLock()
+	defer nw.mtx.RUnlock()
+	return nw.stalled
+}
+
+func (nw *network[T]) node(c *domain.Contact[T]) (*dht.Node[T], error) {
+	nw.mtx.RLock()
+	defer nw.mtx.RUnlock()
+
+	n, ok := nw.nodes[c.Address()]
+	if !ok {
+		return nil, errUnreachable
+	}
+	return n, nil
+}
+
+// memTransport transport of a single node in the network
+type memTransport[T domain.NodeID] struct {
+	nw   *network[T]
+	self *domain.Contact[T]
+}
+
+func (t *memTransport[T]) Ping(ctx context.Context, c *domain.Contact[T]) (T, error) {
+	n, err := t.nw.node(c)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	n.AddOrUpdateRoutingTable(ctx, t.self)
+	return n.ID, nil
+}
+
+func (t *memTransport[T]) Store(ctx context.Context, c *domain.Contact[T], record *domain.Record[T]) error {
+	n, err := t.nw.node(c)
+	if err != nil {
+		return err
+	}
+	n.AddOrUpdateRoutingTable(ctx, t.self)
+	return n.StoreRecord(ctx, record)
+}
+
+func (t *memTransport[T]) FindNode(ctx context.Context, c *domain.Contact[T], nodeID T) ([]*domain.Contact[T], error) {
+	t.nw.queried(t.self.ID, c.ID)
+	if t.nw.isStalled() {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	n, err := t.nw.node(c)
+	if err != nil {
+		return nil, err
+	}
+	n.AddOrUpdateRoutingTable(ctx, t.self)
+	return n.ClosestContacts(nodeID, domain.DefaultReplicationFactor), nil
+}
+
+func (t *memTransport[T]) FindValue(ctx context.Context, c *domain.Contact[T], key T) (*domain.Record[T], []*domain.Contact[T], error) {
+	t.nw.queried(t.self.ID, c.ID)
+	n, err := t.nw.node(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	n.AddOrUpdateRoutingTable(ctx, t.self)
+
+	record, err := n.FindRecord(ctx, key)
+	if err == nil {
+		return record, nil, nil
+	}
+	return nil, n.ClosestContacts(key, domain.DefaultReplicationFactor), nil
+}
+
+func (t *memTransport[T]) AddProvider(ctx context.Context, c *domain.Contact[T], provider *domain.Provider[T]) error {
+	n, err := t.nw.node(c)
+	if err != nil {
+		return err
+	}
+	n.AddOrUpdateRoutingTable(ctx, t.self)
+	return n.AddProvider(ctx, provider)
+}
+
+func (t *memTransport[T]) GetProviders(ctx context.Context, c *domain.Contact[T], key T) ([]*domain.Provider[T], []*domain.Contact[T], error) {
+	n, err := t.nw.node(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	n.AddOrUpdateRoutingTable(ctx, t.self)
+
+	providers, err := n.Providers(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return providers, n.ClosestContacts(key, domain.DefaultReplicationFactor), nil
+}
+
+func newContact(i int) *domain.Contact[domain.NodeID224] {
+	return contactOf[domain.NodeID224](i)
+}
+
+func contactOf[T domain.NodeID](i int) *domain.Contact[T] {
+	c := &domain.Contact[T]{
+		IP:   fmt.Sprintf("10.%d.%d.1", i/250, i%250+1),
+		Port: 50051,
+	}
+	c.ID = domain.NodeIDFromPublicKey[T](keyOf(c.Address()))
+	return c
+}
+
+// keyOf public key of node listening on address
+func keyOf(address string) ed25519.PublicKey {
+	seed := sha256.Sum256([]byte(address))
+	return ed25519.NewKeyFromSeed(seed[:]).Public().(ed25519.PublicKey)
+}
+
+// bootstrap network where every node joins through the first node
+func bootstrap[T domain.NodeID](ctx context.Context, t *testing.T, size int, opts ...dht.Option[T]) (*network[T], []*domain.Contact[T], []*dht.Node[T]) {
+
+	nw := newNetwork[T]()
+	contactSlice := make([]*domain.Contact[T], 0, size)
+	nodeSlice := make([]*dht.Node[T], 0, size)
+
+	for i := 0; i < size; i++ {
+		c := contactOf[T](i)
+		n := nw.join(ctx, c, opts...)
+
+		if i > 0 {
+			n.AddOrUpdateRoutingTable(ctx, contactSlice[0])
+			_, err := n.Lookup(ctx, n.ID)
+			assert.NoError(t, err)
+		}
+
+		contactSlice = append(contactSlice, c)
+		nodeSlice = append(nodeSlice, n)
+	}
+
+	// lookups of random ids spread contacts of late joiners,
+	// similar to a bucket refresh
+	for i, n := range nodeSlice {
+		for j := 0; j < 8; j++ {
+			_, err := n.Lookup(ctx, domain.HashKey[T]([]byte(fmt.Sprintf("refresh-%d-%d", i, j))))
+			assert.NoError(t, err)
+		}
+	}
+
+	return nw, contactSlice, nodeSlice
+}
+
+func closestIDs[T domain.NodeID](target T, contactSlice []*domain.Contact[T], k int) []T {
+
+	ids := make([]T, 0, len(contactSlice))
+	for _, c := range contactSlice {
+		ids = append(ids, c.ID)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return domain.Compare(domain.Distance(target, ids[i]), domain.Distance(target, ids[j])) < 0
+	})
+
+	return ids[:k]
+}
+
+func Test_Lookup(t *testing.T) {
+	t.Run("k_closest", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		_, contactSlice, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 64)
+
+		target := domain.HashKey[domain.NodeID224]([]byte("target"))
+		result, err := nodeSlice[10].Lookup(ctx, target)
+		assert.NoError(err)
+
+		others := append(append([]*domain.Contact[domain.NodeID224]{}, contactSlice[:10]...), contactSlice[11:]...)
+		expected := closestIDs(target, others, domain.DefaultReplicationFactor)
+
+		actual := make([]domain.NodeID224, 0, len(result))
+		for _, c := range result {
+			actual = append(actual, c.ID)
+		}
+		assert.Equal(expected, actual)
+	})
+	t.Run("unresponsive", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw, contactSlice, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 32)
+
+		target := domain.HashKey[domain.NodeID224]([]byte("target"))
+		expected := closestIDs(target, contactSlice[1:], 1)
+		for _, c := range contactSlice {
+			if c.ID == expected[0] {
+				nw.leave(c)
+			}
+		}
+
+		result, err := nodeSlice[0].Lookup(ctx, target)
+		assert.NoError(err)
+		for _, c := range result {
+			assert.NotEqual(expected[0], c.ID)
+		}
+	})
+	t.Run("cancelled", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		nw, _, nodeSlice := bootstrap[domain.NodeID224](context.TODO(), t, 8)
+		nw.mtx.Lock()
+		nw.stalled = true
+		nw.mtx.Unlock()
+
+		n := nodeSlice[0]
+		size := n.RoutingTable().Len()
+
+		// queries cut short by the caller are no failures of the contacts
+		for range 4 {
+			ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+			_, err := n.Lookup(ctx, domain.HashKey[domain.NodeID224]([]byte("target")))
+			cancel()
+			assert.ErrorIs(err, context.DeadlineExceeded)
+		}
+		assert.Equal(size, n.RoutingTable().Len())
+	})
+	t.Run("no_contacts", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		n := newNetwork[domain.NodeID224]().join(ctx, newContact(0))
+		_, err := n.Lookup(ctx, domain.HashKey[domain.NodeID224]([]byte("target")))
+		assert.ErrorIs(err, dht.ErrNoContacts)
+	})
+}
+
+func Test_TraceLookup(t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	_, contactSlice, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 64)
+
+	target := domain.HashKey[domain.NodeID224]([]byte("target"))
+	trace, err := nodeSlice[10].TraceLookup(ctx, target)
+	assert.NoError(err)
+
+	others := append(append([]*domain.Contact[domain.NodeID224]{}, contactSlice[:10]...), contactSlice[11:]...)
+	assert.Equal(closestIDs(target, others, domain.DefaultReplicationFactor), contactIDs(trace.Contacts))
+
+	assert.Equal(target, trace.Target)
+	assert.NotEmpty(trace.Queries)
+	assert.Equal(1, trace.Queries[0].Hop)
+	for _, q := range trace.Queries {
+		assert.NoError(q.Err)
+		assert.Positive(q.RTT)
+		assert.LessOrEqual(q.Sent+q.RTT, trace.Duration)
+	}
+
+	// every responder has a measured rtt in the routing table
+	var measured int
+	for _, b := range nodeSlice[10].Buckets() {
+		for _, info := range b.Contacts {
+			if info.RTT > 0 {
+				measured++
+			}
+		}
+	}
+	assert.Positive(measured)
+}
+
+func Test_LookupWidths(t *testing.T) {
+	t.Run("256", lookupWidth[domain.NodeID256])
+	t.Run("384", lookupWidth[domain.NodeID384])
+	t.Run("512", lookupWidth[domain.NodeID512])
+}
+
+// lookupWidth k closest lookup and value round trip in keyspace of T
+func lookupWidth[T domain.NodeID](t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	_, contactSlice, nodeSlice := bootstrap[T](ctx, t, 32)
+	assert.Equal(domain.Bits[T](), len(nodeSlice[0].ID)*8)
+
+	target := domain.HashKey[T]([]byte("target"))
+	result, err := nodeSlice[0].Lookup(ctx, target)
+	assert.NoError(err)
+	assert.Equal(closestIDs(target, contactSlice[1:], domain.DefaultReplicationFactor), contactIDs(result))
+
+	assert.NoError(nodeSlice[1].Put(ctx, []byte("key"), []byte("value")))
+	value, err := nodeSlice[2].Get(ctx, []byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+}
+
+func Test_DisjointLookup(t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	nw, contactSlice, _ := bootstrap[domain.NodeID224](ctx, t, 64)
+
+	c := newContact(100)
+	n := nw.join(ctx, c, dht.WithDisjointPaths[domain.NodeID224](3))
+	for _, seed := range contactSlice[:8] {
+		n.AddOrUpdateRoutingTable(ctx, seed)
+	}
+
+	target := domain.HashKey[domain.NodeID224]([]byte("target"))
+	result, err := n.Lookup(ctx, target)
+	assert.NoError(err)
+	assert.Equal(closestIDs(target, contactSlice, domain.DefaultReplicationFactor), contactIDs(result))
+
+	// every contact is queried by a single path
+	for key, count := range nw.queries {
+		if key[0] == c.ID {
+			assert.Equal(1, count)
+		}
+	}
+}
+
+func Test_LookupValue(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		_, contactSlice, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 64)
+
+		key := domain.HashKey[domain.NodeID224]([]byte("key"))
+		holder := closestIDs(key, contactSlice, 1)[0]
+		initiator := nodeSlice[0]
+		for i, c := range contactSlice {
+			if c.ID == holder {
+				assert.NoError(nodeSlice[i].StoreRecord(ctx, &domain.Record[domain.NodeID224]{
+					Key:       key,
+					Value:     []byte("value"),
+					Publisher: c.ID,
+					Timestamp: time.Now(),
+				}))
+				continue
+			}
+			initiator = nodeSlice[i]
+		}
+
+		record, _, err := initiator.LookupValue(ctx, key)
+		assert.NoError(err)
+		assert.Equal([]byte("value"), record.Value)
+		assert.Equal(holder, record.Publisher)
+	})
+	t.Run("missing", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		_, _, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 16)
+
+		record, contactSlice, err := nodeSlice[3].LookupValue(ctx, domain.HashKey[domain.NodeID224]([]byte("missing")))
+		assert.NoError(err)
+		assert.Nil(record)
+		assert.Len(contactSlice, domain.DefaultReplicationFactor)
+	})
+}
+
+func Test_Bootstrap(t *testing.T) {
+	t.Run("seeds", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw, contactSlice, _ := bootstrap[domain.NodeID224](ctx, t, 32)
+
+		c := newContact(100)
+		n := nw.join(ctx, c)
+		assert.NoError(n.Bootstrap(ctx, []*domain.Contact[domain.NodeID224]{contactSlice[3], newContact(101)}))
+
+		// routing table filled beyond the responsive seed
+		assert.Greater(n.RoutingTable().Len(), 1)
+
+		result, err := n.Lookup(ctx, c.ID)
+		assert.NoError(err)
+		assert.Equal(closestIDs(c.ID, contactSlice, domain.DefaultReplicationFactor), contactIDs(result))
+	})
+	t.Run("seed_id", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw := newNetwork[domain.NodeID224]()
+		seed := newContact(0)
+		nw.join(ctx, seed)
+		n := nw.join(ctx, newContact(1))
+
+		// unknown id is learned from ping, wrong id is rejected
+		wrong := &domain.Contact[domain.NodeID224]{IP: seed.IP, Port: seed.Port, ID: domain.HashKey[domain.NodeID224]([]byte("wrong"))}
+		assert.ErrorIs(n.Bootstrap(ctx, []*domain.Contact[domain.NodeID224]{wrong}), dht.ErrIdentityMismatch)
+
+		assert.NoError(n.Bootstrap(ctx, []*domain.Contact[domain.NodeID224]{{IP: seed.IP, Port: seed.Port}}))
+		assert.Equal([]*domain.Contact[domain.NodeID224]{{IP: seed.IP, Port: seed.Port, ID: seed.ID}}, n.ClosestContacts(seed.ID, 1))
+	})
+	t.Run("unreachable", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		n := newNetwork[domain.NodeID224]().join(ctx, newContact(0))
+		assert.ErrorIs(n.Bootstrap(ctx, []*domain.Contact[domain.NodeID224]{newContact(1)}), dht.ErrNoSeeds)
+	})
+}
+
+func Test_Refresh(t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	nw, contactSlice, _ := bootstrap[domain.NodeID224](ctx, t, 32)
+
+	n := nw.join(ctx, newContact(100), dht.WithRefreshInterval[domain.NodeID224](time.Millisecond))
+	n.AddOrUpdateRoutingTable(ctx, contactSlice[0])
+
+	time.Sleep(time.Millisecond * 5)
+	assert.NoError(n.Refresh(ctx))
+	assert.Greater(n.RoutingTable().Len(), 1)
+}
+
+func contactIDs[T domain.NodeID](contactSlice []*domain.Contact[T]) []T {
+	ids := make([]T, 0, len(contactSlice))
+	for _, c := range contactSlice {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
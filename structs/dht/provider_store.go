@@ -0,0 +1,206 @@
+package dht
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+// MaxProvidersPerKey provider records kept for a single key
+const MaxProvidersPerKey = 20
+
+// providerPrefix key prefix of provider records persisted in kv
+var providerPrefix = []byte("dht/provider/")
+
+// storedProvider provider record with local expiry
+type storedProvider[T domain.NodeID] struct {
+	domain.Provider[T]
+	// Expires time record is removed from local store
+	Expires time.Time `json:"expires"`
+}
+
+// ProviderStore provider records persisted in key value database,
+// all providers of a key are kept in a single entry
+type ProviderStore[T domain.NodeID] struct {
+	mtx sync.Mutex
+	kv  domain.KV
+}
+
+// NewProviderStore constructor
+func NewProviderStore[T domain.NodeID](kv domain.KV) *ProviderStore[T] {
+	return &ProviderStore[T]{
+		kv: kv,
+	}
+}
+
+// Add provider record expiring after its ttl, a known provider
+// is refreshed. once a key holds the maximum number of providers
+// the record expiring first is replaced
+func (ps *ProviderStore[T]) Add(provider *domain.Provider[T]) error {
+
+	if provider.TTL <= 0 {
+		return ErrExpired
+	}
+
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	now := time.Now()
+	entries, err := ps.get(provider.Key)
+	if err != nil {
+		return err
+	}
+
+	live := entries[:0]
+	for _, e := range entries {
+		if e.Expires.After(now) && e.Contact.ID != provider.Contact.ID {
+			live = append(live, e)
+		}
+	}
+
+	if len(live) >= MaxProvidersPerKey {
+		sort.Slice(live, func(i, j int) bool {
+			return live[i].Expires.Before(live[j].Expires)
+		})
+		live = live[len(live)-MaxProvidersPerKey+1:]
+	}
+
+	live = append(live, &storedProvider[T]{
+		Provider: *provider,
+		Expires:  now.Add(provider.TTL),
+	})
+
+	return ps.put(provider.Key, live)
+}
+
+// Get providers of key that have not expired,
+// ttl is the remaining lifetime of each record
+func (ps *ProviderStore[T]) Get(key T) ([]*domain.Provider[T], error) {
+
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	entries, err := ps.get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	providers := make([]*domain.Provider[T], 0, len(entries))
+	for _, e := range entries {
+		if !e.Expires.After(now) {
+			continue
+		}
+		p := e.Provider
+		p.TTL = e.Expires.Sub(now)
+		providers = append(providers, &p)
+	}
+
+	return providers, nil
+}
+
+// Expire remove provider records expired before now,
+// returns number of removed records
+func (ps *ProviderStore[T]) Expire(ctx context.Context, now time.Time) (int, error) {
+
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	it, err := ps.kv.Iterator(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create iterator %v", err)
+	}
+
+	keys := make([]T, 0)
+	for it.Next() {
+		if !bytes.HasPrefix(it.Key(), providerPrefix) {
+			continue
+		}
+		key, err := domain.NodeIDFromBytes[T](it.Key()[len(providerPrefix):])
+		if err != nil {
+			// providers of another id width
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	if err := it.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close iterator %v", err)
+	}
+
+	var removed int
+	for _, key := range keys {
+
+		entries, err := ps.get(key)
+		if err != nil {
+			return removed, err
+		}
+
+		live := entries[:0]
+		for _, e := range entries {
+			if e.Expires.After(now) {
+				live = append(live, e)
+			}
+		}
+
+		if len(live) == len(entries) {
+			continue
+		}
+		removed += len(entries) - len(live)
+
+		if err := ps.put(key, live); err != nil {
+			return removed, err
+		}
+	}
+
+	return removed, nil
+}
+
+func (ps *ProviderStore[T]) get(key T) ([]*storedProvider[T], error) {
+
+	v, err := ps.kv.Get(providerKey(key))
+	if errors.Is(err, domain.ErrKeyNotFound) || err == nil && len(v) == 0 {
+		return []*storedProvider[T]{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get providers %v", err)
+	}
+
+	var entries []*storedProvider[T]
+	if err := json.Unmarshal(v, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal providers %v", err)
+	}
+
+	return entries, nil
+}
+
+func (ps *ProviderStore[T]) put(key T, entries []*storedProvider[T]) error {
+
+	if len(entries) == 0 {
+		if err := ps.kv.Delete(providerKey(key)); err != nil {
+			return fmt.Errorf("failed to delete providers %v", err)
+		}
+		return nil
+	}
+
+	v, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal providers %v", err)
+	}
+
+	if err := ps.kv.Put(providerKey(key), v); err != nil {
+		return fmt.Errorf("failed to persist providers %v", err)
+	}
+
+	return nil
+}
+
+func providerKey[T domain.NodeID](key T) []byte {
+	return append(bytes.Clone(providerPrefix), domain.Bytes(key)...)
+}
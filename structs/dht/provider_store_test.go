@@ -0,0 +1,78 @@
+package dht_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structx/go-dpkg/domain"
+	"github.com/structx/go-dpkg/structs/dht"
+)
+
+func Test_ProviderStore(t *testing.T) {
+	t.Run("add_refresh", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		ps := dht.NewProviderStore[domain.NodeID224](dht.NewMemoryKV())
+		key := domain.HashKey[domain.NodeID224]([]byte("content"))
+
+		assert.ErrorIs(ps.Add(&domain.Provider[domain.NodeID224]{Key: key, Contact: *newContact(0)}), dht.ErrExpired)
+
+		assert.NoError(ps.Add(&domain.Provider[domain.NodeID224]{Key: key, Contact: *newContact(0), TTL: time.Minute}))
+		assert.NoError(ps.Add(&domain.Provider[domain.NodeID224]{Key: key, Contact: *newContact(1), TTL: time.Minute}))
+		assert.NoError(ps.Add(&domain.Provider[domain.NodeID224]{Key: key, Contact: *newContact(0), TTL: time.Hour}))
+
+		providers, err := ps.Get(key)
+		assert.NoError(err)
+		assert.Len(providers, 2)
+		assert.Equal(newContact(0).ID, providers[1].Contact.ID)
+		assert.Greater(providers[1].TTL, time.Minute)
+	})
+	t.Run("limit", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		ps := dht.NewProviderStore[domain.NodeID224](dht.NewMemoryKV())
+		key := domain.HashKey[domain.NodeID224]([]byte("content"))
+
+		// first provider expires before every other provider
+		for i := 0; i <= dht.MaxProvidersPerKey; i++ {
+			ttl := time.Hour + time.Duration(i)*time.Minute
+			assert.NoError(ps.Add(&domain.Provider[domain.NodeID224]{Key: key, Contact: *newContact(i), TTL: ttl}))
+		}
+
+		providers, err := ps.Get(key)
+		assert.NoError(err)
+		assert.Len(providers, dht.MaxProvidersPerKey)
+		for _, p := range providers {
+			assert.NotEqual(newContact(0).ID, p.Contact.ID)
+		}
+	})
+	t.Run("expire", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		ps := dht.NewProviderStore[domain.NodeID224](dht.NewMemoryKV())
+		k1, k2 := domain.HashKey[domain.NodeID224]([]byte("k1")), domain.HashKey[domain.NodeID224]([]byte("k2"))
+
+		assert.NoError(ps.Add(&domain.Provider[domain.NodeID224]{Key: k1, Contact: *newContact(0), TTL: time.Minute}))
+		assert.NoError(ps.Add(&domain.Provider[domain.NodeID224]{Key: k1, Contact: *newContact(1), TTL: time.Hour}))
+		assert.NoError(ps.Add(&domain.Provider[domain.NodeID224]{Key: k2, Contact: *newContact(2), TTL: time.Minute}))
+
+		removed, err := ps.Expire(ctx, time.Now().Add(time.Minute*2))
+		assert.NoError(err)
+		assert.Equal(2, removed)
+
+		providers, err := ps.Get(k1)
+		assert.NoError(err)
+		assert.Len(providers, 1)
+
+		providers, err = ps.Get(k2)
+		assert.NoError(err)
+		assert.Empty(providers)
+	})
+}
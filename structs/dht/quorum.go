@@ -0,0 +1,288 @@
+package dht
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+// ErrQuorum fewer replicas than the quorum responded
+var ErrQuorum = domain.ErrQuorum
+
+// replicaRead response of a replica to a quorum read
+type replicaRead[T domain.NodeID] struct {
+	contact *domain.Contact[T]
+	// record held by replica, nil if it holds none
+	record *domain.Record[T]
+	err    error
+}
+
+// PutValue store a new version of value on the k closest nodes
+//
+// the version is one higher than the newest version held by the local
+// node or any of the replicas, concurrent writes of the same version
+// are resolved by domain.CompareRecords. the result reports how many
+// replicas stored the record, ErrQuorum is returned with the result
+// if fewer than the write quorum did
+func (n *Node[T]) PutValue(ctx context.Context, key, value []byte, opts domain.PutOptions) (*domain.PutResult[T], error) {
+	return n.put(ctx, domain.HashKey[T](key), false, opts, func(record *domain.Record[T]) {
+		record.Value = value
+	})
+}
+
+// PutMutable sign value with owner key and store it under the mutable
+// key of owner and salt like PutValue, replicas accept only versions
+// signed by owner with a higher sequence number. records are not
+// republished by the original publisher and expire unless put again
+func (n *Node[T]) PutMutable(ctx context.Context, owner ed25519.PrivateKey, salt, value []byte, opts domain.PutOptions) (*domain.PutResult[T], error) {
+
+	if len(salt) > domain.MaxSaltSize {
+		return nil, fmt.Errorf("salt exceeds %d bytes", domain.MaxSaltSize)
+	}
+
+	pub := owner.Public().(ed25519.PublicKey)
+
+	return n.put(ctx, domain.MutableKey[T](pub, salt), true, opts, func(record *domain.Record[T]) {
+		record.Value = value
+		record.Salt = salt
+		domain.SignRecord(owner, record)
+	})
+}
+
+// GetValue newest version of value held by the local node or a read
+// quorum of the k closest nodes, replicas that responded with an older
+// version or none are repaired with the newest version
+func (n *Node[T]) GetValue(ctx context.Context, key []byte, opts domain.GetOptions) (*domain.Record[T], error) {
+	return n.get(ctx, domain.HashKey[T](key), opts)
+}
+
+// GetMutable newest version of the mutable record of owner and salt
+// like GetValue, records are verified against owner on retrieval
+func (n *Node[T]) GetMutable(ctx context.Context, owner ed25519.PublicKey, salt []byte, opts domain.GetOptions) (*domain.Record[T], error) {
+
+	record, err := n.get(ctx, domain.MutableKey[T](owner, salt), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !record.Mutable() || !bytes.Equal(record.PublicKey, owner) {
+		// unsigned record squatting the key
+		return nil, ErrNotFound
+	}
+
+	return record, nil
+}
+
+// put record built by build with the next version under key
+func (n *Node[T]) put(ctx context.Context, key T, mutable bool, opts domain.PutOptions, build func(record *domain.Record[T])) (*domain.PutResult[T], error) {
+
+	quorum := n.quorum(opts.Quorum)
+
+	contactSlice, err := n.replicas(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &domain.Record[T]{
+		Key:       key,
+		Publisher: n.ID,
+		Timestamp: time.Now(),
+		Seq:       n.nextSeq(key, mutable, n.readReplicas(ctx, contactSlice, key)),
+	}
+	build(record)
+
+	if err := n.values.Put(record, record.Timestamp.Add(n.recordTTL)); err != nil {
+		return nil, fmt.Errorf("failed to store record %v", err)
+	}
+
+	result := &domain.PutResult[T]{Record: record, Replicas: len(contactSlice)}
+
+	errs := n.storeReplicas(ctx, contactSlice, record)
+	for _, err := range errs {
+		if err == nil {
+			result.Stored++
+		}
+	}
+
+	if result.Stored < quorum {
+		return result, fmt.Errorf("failed to reach write quorum stored %d of %d %w", result.Stored, quorum, errors.Join(append([]error{ErrQuorum}, errs...)...))
+	}
+
+	return result, nil
+}
+
+// get newest record under key from a read quorum
+func (n *Node[T]) get(ctx context.Context, key T, opts domain.GetOptions) (*domain.Record[T], error) {
+
+	quorum := n.quorum(opts.Quorum)
+
+	local, err := n.values.Get(key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to get record %v", err)
+	}
+
+	contactSlice, err := n.replicas(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	reads := n.readReplicas(ctx, contactSlice, key)
+
+	newest := local
+	var responded int
+	for _, r := range reads {
+		if r.err != nil {
+			continue
+		}
+		responded++
+		if r.record != nil && newer(r.record, newest) {
+			newest = r.record
+		}
+	}
+
+	if responded < quorum {
+		return nil, fmt.Errorf("failed to reach read quorum responded %d of %d %w", responded, quorum, ErrQuorum)
+	}
+
+	if newest == nil {
+		return nil, ErrNotFound
+	}
+
+	if !opts.NoRepair {
+		n.repair(ctx, newest, local, reads)
+	}
+
+	return newest, nil
+}
+
+// quorum requested quorum or a majority of k
+func (n *Node[T]) quorum(q int) int {
+	if q > 0 {
+		return q
+	}
+	return n.replicationFactor/2 + 1
+}
+
+// replicas k closest nodes to key, none if no other nodes are known
+func (n *Node[T]) replicas(ctx context.Context, key T) ([]*domain.Contact[T], error) {
+
+	if n.transport == nil {
+		return nil, nil
+	}
+
+	contactSlice, err := n.Lookup(ctx, key)
+	if errors.Is(err, ErrNoContacts) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return contactSlice, nil
+}
+
+// nextSeq version following the newest local or replica version,
+// versions of unsigned records are ignored for mutable records
+func (n *Node[T]) nextSeq(key T, mutable bool, reads []*replicaRead[T]) uint64 {
+
+	var seq uint64
+	if local, err := n.values.Get(key); err == nil && (local.Mutable() || !mutable) {
+		seq = local.Seq
+	}
+
+	for _, r := range reads {
+		if r.err == nil && r.record != nil && (r.record.Mutable() || !mutable) {
+			seq = max(seq, r.record.Seq)
+		}
+	}
+
+	return seq + 1
+}
+
+// newer record replaces current, signed records take
+// precedence over unsigned records under the same key
+func newer[T domain.NodeID](record, current *domain.Record[T]) bool {
+	switch {
+	case current == nil:
+		return true
+	case record.Mutable() != current.Mutable():
+		return record.Mutable()
+	}
+	return domain.CompareRecords(record, current) > 0
+}
+
+// verifyRecord record returned for key by another node
+func verifyRecord[T domain.NodeID](record *domain.Record[T], key T) error {
+	if record.Key != key {
+		return errors.New("record of another key returned")
+	}
+	return domain.VerifyRecord(record)
+}
+
+// readReplicas ask every contact for its version of key in parallel
+func (n *Node[T]) readReplicas(ctx context.Context, contactSlice []*domain.Contact[T], key T) []*replicaRead[T] {
+
+	reads := make([]*replicaRead[T], len(contactSlice))
+
+	var wg sync.WaitGroup
+	for i, c := range contactSlice {
+		wg.Add(1)
+		go func(i int, c *domain.Contact[T]) {
+			defer wg.Done()
+
+			r := &replicaRead[T]{contact: c}
+			r.record, _, r.err = n.transport.FindValue(ctx, c, key)
+			if r.err == nil && r.record != nil {
+				if err := verifyRecord(r.record, key); err != nil {
+					r.record, r.err = nil, fmt.Errorf("replica %s returned invalid record %w", c.Address(), err)
+				}
+			}
+			reads[i] = r
+		}(i, c)
+	}
+	wg.Wait()
+
+	return reads
+}
+
+// storeReplicas store record on every contact in parallel,
+// returns the error of each contact in the same order
+func (n *Node[T]) storeReplicas(ctx context.Context, contactSlice []*domain.Contact[T], record *domain.Record[T]) []error {
+
+	errs := make([]error, len(contactSlice))
+
+	var wg sync.WaitGroup
+	for i, c := range contactSlice {
+		wg.Add(1)
+		go func(i int, c *domain.Contact[T]) {
+			defer wg.Done()
+			errs[i] = n.transport.Store(ctx, c, record)
+		}(i, c)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// repair store newest version on replicas that responded with an
+// older version or none, and in the local store if it is outdated
+func (n *Node[T]) repair(ctx context.Context, newest, local *domain.Record[T], reads []*replicaRead[T]) {
+
+	if local != nil && newer(newest, local) {
+		_ = n.values.Put(newest, n.expiry(newest))
+	}
+
+	stale := make([]*domain.Contact[T], 0, len(reads))
+	for _, r := range reads {
+		if r.err == nil && (r.record == nil || newer(newest, r.record)) {
+			stale = append(stale, r.contact)
+		}
+	}
+
+	_ = n.storeReplicas(ctx, stale, newest)
+}
@@ -0,0 +1,174 @@
+package dht_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structx/go-dpkg/domain"
+	"github.com/structx/go-dpkg/structs/dht"
+)
+
+func Test_PutGetValue(t *testing.T) {
+	t.Run("versions", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		_, _, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 32)
+
+		result, err := nodeSlice[0].PutValue(ctx, []byte("key"), []byte("v1"), domain.PutOptions{})
+		assert.NoError(err)
+		assert.Equal(uint64(1), result.Record.Seq)
+		assert.Equal(domain.DefaultReplicationFactor, result.Replicas)
+		assert.Equal(result.Replicas, result.Stored)
+
+		// another writer continues after the replicated version
+		result, err = nodeSlice[5].PutValue(ctx, []byte("key"), []byte("v2"), domain.PutOptions{})
+		assert.NoError(err)
+		assert.Equal(uint64(2), result.Record.Seq)
+
+		record, err := nodeSlice[9].GetValue(ctx, []byte("key"), domain.GetOptions{})
+		assert.NoError(err)
+		assert.Equal([]byte("v2"), record.Value)
+		assert.Equal(uint64(2), record.Seq)
+		assert.Equal(nodeSlice[5].ID, record.Publisher)
+
+		_, err = nodeSlice[9].GetValue(ctx, []byte("missing"), domain.GetOptions{})
+		assert.ErrorIs(err, dht.ErrNotFound)
+	})
+	t.Run("read_repair", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw, contactSlice, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 32)
+
+		key := domain.HashKey[domain.NodeID224]([]byte("key"))
+		_, err := nodeSlice[0].PutValue(ctx, []byte("key"), []byte("v1"), domain.PutOptions{})
+		assert.NoError(err)
+
+		// closest replica misses the second write
+		closest := closestIDs(key, contactSlice[1:], 1)[0]
+		var stale *dht.Node[domain.NodeID224]
+		for i, c := range contactSlice {
+			if c.ID == closest {
+				stale = nodeSlice[i]
+				nw.leave(c)
+				_, err = nodeSlice[0].PutValue(ctx, []byte("key"), []byte("v2"), domain.PutOptions{})
+				assert.NoError(err)
+				nw.mtx.Lock()
+				nw.nodes[c.Address()] = stale
+				nw.mtx.Unlock()
+			}
+		}
+
+		record, err := stale.FindRecord(ctx, key)
+		assert.NoError(err)
+		assert.Equal(uint64(1), record.Seq)
+
+		record, err = nodeSlice[0].GetValue(ctx, []byte("key"), domain.GetOptions{NoRepair: true})
+		assert.NoError(err)
+		assert.Equal(uint64(2), record.Seq)
+
+		record, err = stale.FindRecord(ctx, key)
+		assert.NoError(err)
+		assert.Equal(uint64(1), record.Seq)
+
+		_, err = nodeSlice[0].GetValue(ctx, []byte("key"), domain.GetOptions{})
+		assert.NoError(err)
+
+		record, err = stale.FindRecord(ctx, key)
+		assert.NoError(err)
+		assert.Equal(uint64(2), record.Seq)
+		assert.Equal([]byte("v2"), record.Value)
+	})
+	t.Run("quorum", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		_, _, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 32)
+		unreachable := domain.DefaultReplicationFactor + 1
+
+		result, err := nodeSlice[0].PutValue(ctx, []byte("key"), []byte("v1"), domain.PutOptions{Quorum: unreachable})
+		assert.ErrorIs(err, dht.ErrQuorum)
+		assert.Equal(domain.DefaultReplicationFactor, result.Stored)
+
+		_, err = nodeSlice[0].GetValue(ctx, []byte("key"), domain.GetOptions{Quorum: unreachable})
+		assert.ErrorIs(err, dht.ErrQuorum)
+
+		// single node never reaches a write quorum
+		n := newNetwork[domain.NodeID224]().join(ctx, newContact(0))
+		result, err = n.PutValue(ctx, []byte("key"), []byte("v1"), domain.PutOptions{})
+		assert.ErrorIs(err, dht.ErrQuorum)
+		assert.Equal(0, result.Replicas)
+	})
+}
+
+func Test_PutGetMutable(t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	_, contactSlice, nodeSlice := bootstrap[domain.NodeID224](ctx, t, 32)
+
+	pub, owner, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	salt := []byte("service")
+
+	result, err := nodeSlice[0].PutMutable(ctx, owner, salt, []byte("10.0.0.1:8080"), domain.PutOptions{})
+	assert.NoError(err)
+	assert.Equal(uint64(1), result.Record.Seq)
+	assert.Equal(domain.MutableKey[domain.NodeID224](pub, salt), result.Record.Key)
+
+	// owner may publish from any node
+	result, err = nodeSlice[3].PutMutable(ctx, owner, salt, []byte("10.0.0.2:8080"), domain.PutOptions{})
+	assert.NoError(err)
+	assert.Equal(uint64(2), result.Record.Seq)
+
+	record, err := nodeSlice[9].GetMutable(ctx, pub, salt, domain.GetOptions{})
+	assert.NoError(err)
+	assert.Equal([]byte("10.0.0.2:8080"), record.Value)
+	assert.NoError(domain.VerifyRecord(record))
+
+	// replicas refuse versions not signed by owner
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	forged := &domain.Record[domain.NodeID224]{Value: []byte("hijacked"), Salt: salt, Seq: 3, Timestamp: time.Now()}
+	domain.SignRecord(other, forged)
+	forged.Key = record.Key
+	forged.PublicKey = pub
+
+	closest := closestIDs(record.Key, contactSlice, 1)[0]
+	for i, c := range contactSlice {
+		if c.ID == closest {
+			assert.ErrorIs(nodeSlice[i].StoreRecord(ctx, forged), dht.ErrRejected)
+		}
+	}
+
+	_, err = nodeSlice[9].GetMutable(ctx, pub, []byte("other"), domain.GetOptions{})
+	assert.ErrorIs(err, dht.ErrNotFound)
+}
+
+func Test_CompareRecords(t *testing.T) {
+
+	assert := assert.New(t)
+
+	publisher := newContact(0).ID
+	a := &domain.Record[domain.NodeID224]{Seq: 2, Publisher: publisher}
+	b := &domain.Record[domain.NodeID224]{Seq: 1, Publisher: publisher}
+
+	assert.Equal(1, domain.CompareRecords(a, b))
+	assert.Equal(-1, domain.CompareRecords(b, a))
+
+	// equal versions are ordered by publisher then value
+	b.Seq = 2
+	b.Value = []byte("v")
+	assert.Equal(-1, domain.CompareRecords(a, b))
+	assert.Equal(0, domain.CompareRecords(a, a))
+}
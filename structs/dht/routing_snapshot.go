@@ -0,0 +1,156 @@
+package dht
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+const (
+	// DefaultSnapshotInterval interval the routing table is persisted
+	DefaultSnapshotInterval = time.Minute * 10
+	// restoreConcurrency parallel pings of restored contacts
+	restoreConcurrency = 16
+)
+
+// routingKey key of routing table snapshot in kv
+var routingKey = []byte("dht/routing")
+
+// routingSnapshot persisted routing table of a node
+type routingSnapshot[T domain.NodeID] struct {
+	// Self node id the buckets are placed relative to
+	Self    T                   `json:"self"`
+	SavedAt time.Time           `json:"saved_at"`
+	Buckets []bucketSnapshot[T] `json:"buckets"`
+}
+
+// bucketSnapshot contacts of a bucket ordered from least to most recently seen
+type bucketSnapshot[T domain.NodeID] struct {
+	Index    int                  `json:"index"`
+	Contacts []contactSnapshot[T] `json:"contacts"`
+}
+
+type contactSnapshot[T domain.NodeID] struct {
+	ID        T             `json:"id"`
+	IP        string        `json:"ip"`
+	Port      int           `json:"port"`
+	FirstSeen time.Time     `json:"first_seen"`
+	LastSeen  time.Time     `json:"last_seen"`
+	RTT       time.Duration `json:"rtt"`
+}
+
+// SaveRoutingTable persist contacts of the routing table,
+// an empty routing table keeps the previous snapshot
+func (n *Node[T]) SaveRoutingTable() error {
+
+	buckets := n.routingTable.Buckets()
+
+	snapshot := routingSnapshot[T]{
+		Self:    n.ID,
+		SavedAt: time.Now(),
+		Buckets: make([]bucketSnapshot[T], 0, len(buckets)),
+	}
+
+	for _, b := range buckets {
+		if len(b.Contacts) == 0 {
+			continue
+		}
+
+		bs := bucketSnapshot[T]{Index: b.Index, Contacts: make([]contactSnapshot[T], 0, len(b.Contacts))}
+		for _, info := range b.Contacts {
+			bs.Contacts = append(bs.Contacts, contactSnapshot[T]{
+				ID:        info.Contact.ID,
+				IP:        info.Contact.IP,
+				Port:      info.Contact.Port,
+				FirstSeen: info.FirstSeen,
+				LastSeen:  info.LastSeen,
+				RTT:       info.RTT,
+			})
+		}
+		snapshot.Buckets = append(snapshot.Buckets, bs)
+	}
+
+	if len(snapshot.Buckets) == 0 {
+		return nil
+	}
+
+	v, err := json.Marshal(&snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal routing table %v", err)
+	}
+
+	if err := n.kv.Put(routingKey, v); err != nil {
+		return fmt.Errorf("failed to persist routing table %v", err)
+	}
+
+	return nil
+}
+
+// RestoreRoutingTable ping contacts of the last snapshot and add those
+// that respond with their original first seen time, returns the number
+// of restored and saved contacts. contacts are placed in buckets
+// relative to the current node id which may differ from the snapshot
+func (n *Node[T]) RestoreRoutingTable(ctx context.Context) (int, int, error) {
+
+	if n.transport == nil {
+		return 0, 0, ErrMissingTransport
+	}
+
+	v, err := n.kv.Get(routingKey)
+	if errors.Is(err, domain.ErrKeyNotFound) || err == nil && len(v) == 0 {
+		// nothing saved yet
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, fmt.Errorf("failed to get routing table %v", err)
+	}
+
+	var snapshot routingSnapshot[T]
+	if err := json.Unmarshal(v, &snapshot); err != nil {
+		return 0, 0, fmt.Errorf("failed to unmarshal routing table %v", err)
+	}
+
+	infos := make([]domain.ContactInfo[T], 0)
+	for _, b := range snapshot.Buckets {
+		for _, cs := range b.Contacts {
+			if cs.ID == n.ID {
+				continue
+			}
+			infos = append(infos, domain.ContactInfo[T]{
+				Contact:   domain.Contact[T]{IP: cs.IP, Port: cs.Port, ID: cs.ID},
+				FirstSeen: cs.FirstSeen,
+				LastSeen:  cs.LastSeen,
+				RTT:       cs.RTT,
+			})
+		}
+	}
+
+	alive := make([]bool, len(infos))
+	sem := make(chan struct{}, restoreConcurrency)
+
+	var wg sync.WaitGroup
+	for i := range infos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			alive[i] = n.ping(ctx, &infos[i].Contact) == nil
+		}(i)
+	}
+	wg.Wait()
+
+	// restore in snapshot order so buckets keep
+	// their least to most recently seen order
+	var restored int
+	for i, info := range infos {
+		if alive[i] && n.routingTable.Restore(info) {
+			restored++
+		}
+	}
+
+	return restored, len(infos), nil
+}
@@ -0,0 +1,88 @@
+package dht_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structx/go-dpkg/domain"
+	"github.com/structx/go-dpkg/structs/dht"
+)
+
+func Test_RoutingTableSnapshot(t *testing.T) {
+	t.Run("restore", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw, contactSlice, _ := bootstrap[domain.NodeID224](ctx, t, 16)
+
+		kv := dht.NewMemoryKV()
+		c := contactOf[domain.NodeID224](100)
+		n := nw.join(ctx, c, dht.WithStore[domain.NodeID224](kv))
+		assert.NoError(n.Bootstrap(ctx, contactSlice[:1]))
+
+		before := n.Buckets()
+		assert.NoError(n.Close())
+
+		// restart without seeds
+		restarted := nw.join(ctx, c, dht.WithStore[domain.NodeID224](kv))
+		restored, saved, err := restarted.RestoreRoutingTable(ctx)
+		assert.NoError(err)
+		assert.Equal(n.RoutingTable().Len(), saved)
+		assert.Equal(saved, restored)
+
+		after := restarted.Buckets()
+		assert.Len(after, len(before))
+		for i := range before {
+			assert.Equal(before[i].Index, after[i].Index)
+			assert.Len(after[i].Contacts, len(before[i].Contacts))
+			for j := range before[i].Contacts {
+				assert.Equal(before[i].Contacts[j].Contact, after[i].Contacts[j].Contact)
+				assert.True(before[i].Contacts[j].FirstSeen.Equal(after[i].Contacts[j].FirstSeen))
+			}
+		}
+
+		assert.NoError(restarted.Bootstrap(ctx, nil))
+	})
+	t.Run("seed_fallback", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		nw, contactSlice, _ := bootstrap[domain.NodeID224](ctx, t, 16)
+
+		kv := dht.NewMemoryKV()
+		c := contactOf[domain.NodeID224](100)
+		n := nw.join(ctx, c, dht.WithStore[domain.NodeID224](kv))
+		assert.NoError(n.Bootstrap(ctx, contactSlice[:1]))
+		assert.NoError(n.Close())
+
+		// every saved contact left the network
+		for _, sc := range contactSlice {
+			nw.leave(sc)
+		}
+		seed := contactOf[domain.NodeID224](200)
+		nw.join(ctx, seed)
+
+		restarted := nw.join(ctx, c, dht.WithStore[domain.NodeID224](kv))
+		assert.ErrorIs(restarted.Bootstrap(ctx, nil), dht.ErrNoSeeds)
+		assert.NoError(restarted.Bootstrap(ctx, []*domain.Contact[domain.NodeID224]{seed}))
+		assert.Equal(1, restarted.RoutingTable().Len())
+	})
+	t.Run("empty", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		kv := dht.NewMemoryKV()
+		n := newNetwork[domain.NodeID224]().join(ctx, newContact(0), dht.WithStore[domain.NodeID224](kv))
+		assert.NoError(n.Close())
+
+		restored, saved, err := n.RestoreRoutingTable(ctx)
+		assert.NoError(err)
+		assert.Zero(restored)
+		assert.Zero(saved)
+	})
+}
@@ -0,0 +1,690 @@
+package dht
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"math/bits"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+const (
+	// staleThreshold consecutive failures before a contact
+	// is removed without a replacement
+	staleThreshold = 3
+	// DefaultBucketSubnetLimit contacts of the same subnet per bucket
+	DefaultBucketSubnetLimit = 2
+	// DefaultTableSubnetLimit contacts of the same subnet per routing table
+	DefaultTableSubnetLimit = 10
+)
+
+const (
+	// EvictedPing least recently seen contact failed to respond to a ping
+	EvictedPing = "ping"
+	// EvictedUnresponsive contact failed to respond to queries
+	EvictedUnresponsive = "unresponsive"
+	// EvictedBanned contact was banned for misbehaving
+	EvictedBanned = "banned"
+)
+
+type entry[T domain.NodeID] struct {
+	contact   *domain.Contact[T]
+	firstSeen time.Time
+	lastSeen  time.Time
+	failures  int
+	// rtt smoothed round trip time
+	rtt time.Duration
+}
+
+func (e *entry[T]) info() domain.ContactInfo[T] {
+	return domain.ContactInfo[T]{
+		Contact:   *e.contact,
+		FirstSeen: e.firstSeen,
+		LastSeen:  e.lastSeen,
+		RTT:       e.rtt,
+		Failures:  e.failures,
+	}
+}
+
+// kBucket contacts ordered from least recently seen (head)
+// to most recently seen (tail)
+type kBucket[T domain.NodeID] struct {
+	entries      []*entry[T]
+	replacements []*entry[T]
+	pinging      bool
+	// lastLookup time of the last lookup for an id in bucket range
+	lastLookup time.Time
+}
+
+func (b *kBucket[T]) index(nodeID T) int {
+	for i, e := range b.entries {
+		if e.contact.ID == nodeID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *kBucket[T]) replacementIndex(nodeID T) int {
+	for i, e := range b.replacements {
+		if e.contact.ID == nodeID {
+			return i
+		}
+	}
+	return -1
+}
+
+// RoutingTable kademlia routing table
+//
+// contacts are placed in one of the k-buckets by the length of the
+// common prefix they share with the local node id, each bucket holds
+// at most k contacts with a replacement cache of the same size.
+// the table has one bucket per bit of the node id width
+//
+// to make it expensive to eclipse a node the number of contacts
+// sharing a /24 (ipv4) or /48 (ipv6) subnet is limited per bucket
+// and per table, contacts known the longest are preferred over newcomers
+type RoutingTable[T domain.NodeID] struct {
+	self T
+	k    int
+
+	mtx     sync.RWMutex
+	buckets []*kBucket[T]
+
+	// bucketSubnetLimit contacts of a subnet per bucket, zero for no limit
+	bucketSubnetLimit int
+	// tableSubnetLimit contacts of a subnet per table, zero for no limit
+	tableSubnetLimit int
+	// subnets number of contacts in table per subnet
+	subnets map[string]int
+
+	// ping least recently seen contact before eviction
+	ping func(ctx context.Context, c *domain.Contact[T]) error
+	// syncPing ping during update instead of in the background
+	syncPing bool
+	// random source of random ids
+	random io.Reader
+	// onEvict called with the table locked and the reason
+	// a contact was removed, nil to ignore
+	onEvict func(reason string)
+}
+
+// NewRoutingTable constructor
+func NewRoutingTable[T domain.NodeID](self T, k int, ping func(ctx context.Context, c *domain.Contact[T]) error) *RoutingTable[T] {
+
+	rt := &RoutingTable[T]{
+		self:    self,
+		k:       k,
+		ping:    ping,
+		random:  rand.Reader,
+		buckets: make([]*kBucket[T], domain.Bits[T]()),
+
+		bucketSubnetLimit: DefaultBucketSubnetLimit,
+		tableSubnetLimit:  DefaultTableSubnetLimit,
+		subnets:           make(map[string]int),
+	}
+
+	now := time.Now()
+	for i := range rt.buckets {
+		rt.buckets[i] = &kBucket[T]{
+			entries:      make([]*entry[T], 0, k),
+			replacements: make([]*entry[T], 0),
+			lastLookup:   now,
+		}
+	}
+
+	return rt
+}
+
+// SetSubnetLimits set maximum number of contacts sharing a subnet
+// per bucket and per table, zero disables a limit
+func (rt *RoutingTable[T]) SetSubnetLimits(perBucket, perTable int) {
+
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	rt.bucketSubnetLimit = perBucket
+	rt.tableSubnetLimit = perTable
+}
+
+// Update contact seen, move known contacts to the tail of the bucket
+// and add new contacts while there is capacity left. when the bucket
+// is full the contact goes into the replacement cache and the least
+// recently seen contact is pinged, it is only evicted if it fails to respond.
+// new contacts exceeding the subnet limits are ignored
+func (rt *RoutingTable[T]) Update(ctx context.Context, c *domain.Contact[T]) {
+
+	if c == nil || c.ID == rt.self {
+		return
+	}
+
+	rt.mtx.Lock()
+	b := rt.buckets[rt.bucketIndex(c.ID)]
+	now := time.Now()
+
+	if i := b.index(c.ID); i >= 0 {
+		e := b.entries[i]
+		if subnet(e.contact.IP) != subnet(c.IP) && !rt.admit(b, c.IP) {
+			// new address exceeds the limits of its subnet
+			rt.mtx.Unlock()
+			return
+		}
+		rt.count(e.contact.IP, -1)
+		rt.count(c.IP, 1)
+		b.entries = append(append(b.entries[:i], b.entries[i+1:]...), &entry[T]{contact: c, firstSeen: e.firstSeen, lastSeen: now, rtt: e.rtt})
+		rt.mtx.Unlock()
+		return
+	}
+
+	if !rt.admit(b, c.IP) {
+		rt.mtx.Unlock()
+		return
+	}
+
+	if len(b.entries) < rt.k {
+		b.entries = append(b.entries, &entry[T]{contact: c, firstSeen: now, lastSeen: now})
+		rt.count(c.IP, 1)
+		rt.mtx.Unlock()
+		return
+	}
+
+	// bucket full keep contact in replacement cache
+	firstSeen := now
+	if i := b.replacementIndex(c.ID); i >= 0 {
+		firstSeen = b.replacements[i].firstSeen
+		b.replacements = append(b.replacements[:i], b.replacements[i+1:]...)
+	}
+	b.replacements = append(b.replacements, &entry[T]{contact: c, firstSeen: firstSeen, lastSeen: now})
+	if len(b.replacements) > rt.k {
+		// prefer long lived replacements over newcomers
+		youngest := 0
+		for i, e := range b.replacements {
+			if e.firstSeen.After(b.replacements[youngest].firstSeen) {
+				youngest = i
+			}
+		}
+		b.replacements = append(b.replacements[:youngest], b.replacements[youngest+1:]...)
+	}
+
+	if rt.ping == nil || b.pinging {
+		rt.mtx.Unlock()
+		return
+	}
+
+	b.pinging = true
+	head := b.entries[0].contact
+	rt.mtx.Unlock()
+
+	if rt.syncPing {
+		rt.checkHead(ctx, b, head)
+		return
+	}
+
+	go rt.checkHead(context.WithoutCancel(ctx), b, head)
+}
+
+// Restore add responsive contact of a previous run as most recently
+// seen, the first seen time and rtt are kept so long lived contacts
+// remain preferred. contacts are only added while their bucket has
+// capacity and the subnet limits allow
+func (rt *RoutingTable[T]) Restore(info domain.ContactInfo[T]) bool {
+
+	c := info.Contact
+	if c.ID == rt.self {
+		return false
+	}
+
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	b := rt.buckets[rt.bucketIndex(c.ID)]
+	if b.index(c.ID) >= 0 || len(b.entries) >= rt.k || !rt.admit(b, c.IP) {
+		return false
+	}
+
+	b.entries = append(b.entries, &entry[T]{contact: &c, firstSeen: info.FirstSeen, lastSeen: time.Now(), rtt: info.RTT})
+	rt.count(c.IP, 1)
+
+	return true
+}
+
+// checkHead ping least recently seen contact, move to tail if alive
+// otherwise evict and promote a replacement
+func (rt *RoutingTable[T]) checkHead(ctx context.Context, b *kBucket[T], head *domain.Contact[T]) {
+
+	err := rt.ping(ctx, head)
+
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	b.pinging = false
+
+	i := b.index(head.ID)
+	if i < 0 {
+		return
+	}
+
+	if err == nil {
+		e := b.entries[i]
+		e.lastSeen = time.Now()
+		e.failures = 0
+		b.entries = append(append(b.entries[:i], b.entries[i+1:]...), e)
+		return
+	}
+
+	rt.remove(b, i)
+	rt.evicted(EvictedPing)
+	rt.promote(b)
+}
+
+// RecordRTT update smoothed round trip time of contact
+// with a new sample, weighting the sample 1/8 like tcp
+func (rt *RoutingTable[T]) RecordRTT(nodeID T, rtt time.Duration) {
+
+	if nodeID == rt.self || rtt <= 0 {
+		return
+	}
+
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	b := rt.buckets[rt.bucketIndex(nodeID)]
+	i := b.index(nodeID)
+	if i < 0 {
+		return
+	}
+
+	e := b.entries[i]
+	if e.rtt == 0 {
+		e.rtt = rtt
+		return
+	}
+	e.rtt += (rtt - e.rtt) / 8
+}
+
+// RTT smoothed round trip time of contact, zero if unknown
+func (rt *RoutingTable[T]) RTT(nodeID T) time.Duration {
+
+	if nodeID == rt.self {
+		return 0
+	}
+
+	rt.mtx.RLock()
+	defer rt.mtx.RUnlock()
+
+	b := rt.buckets[rt.bucketIndex(nodeID)]
+	if i := b.index(nodeID); i >= 0 {
+		return b.entries[i].rtt
+	}
+
+	return 0
+}
+
+// Fail contact did not respond, replace with most recent
+// replacement or remove once it has failed repeatedly
+func (rt *RoutingTable[T]) Fail(nodeID T) {
+
+	if nodeID == rt.self {
+		return
+	}
+
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	b := rt.buckets[rt.bucketIndex(nodeID)]
+	i := b.index(nodeID)
+	if i < 0 {
+		if j := b.replacementIndex(nodeID); j >= 0 {
+			b.replacements = append(b.replacements[:j], b.replacements[j+1:]...)
+		}
+		return
+	}
+
+	b.entries[i].failures++
+	if len(b.replacements) == 0 && b.entries[i].failures < staleThreshold {
+		return
+	}
+
+	rt.remove(b, i)
+	rt.evicted(EvictedUnresponsive)
+	rt.promote(b)
+}
+
+// Remove contact from bucket and replacements, e.g. once it got
+// banned, the longest known replacement takes its place
+func (rt *RoutingTable[T]) Remove(nodeID T) {
+
+	if nodeID == rt.self {
+		return
+	}
+
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	b := rt.buckets[rt.bucketIndex(nodeID)]
+	if j := b.replacementIndex(nodeID); j >= 0 {
+		b.replacements = append(b.replacements[:j], b.replacements[j+1:]...)
+	}
+
+	i := b.index(nodeID)
+	if i < 0 {
+		return
+	}
+
+	rt.remove(b, i)
+	rt.evicted(EvictedBanned)
+	rt.promote(b)
+}
+
+// Closest contacts sorted by xor distance to node id
+func (rt *RoutingTable[T]) Closest(nodeID T, count int) []*domain.Contact[T] {
+
+	rt.mtx.RLock()
+	contactSlice := make([]*domain.Contact[T], 0, rt.k)
+	for _, b := range rt.buckets {
+		for _, e := range b.entries {
+			contactSlice = append(contactSlice, e.contact)
+		}
+	}
+	rt.mtx.RUnlock()
+
+	sort.Slice(contactSlice, func(i, j int) bool {
+		return domain.Compare(
+			domain.Distance(nodeID, contactSlice[i].ID),
+			domain.Distance(nodeID, contactSlice[j].ID),
+		) < 0
+	})
+
+	if len(contactSlice) > count {
+		contactSlice = contactSlice[:count]
+	}
+
+	return contactSlice
+}
+
+// Touch mark bucket node id belongs in as recently looked up
+func (rt *RoutingTable[T]) Touch(nodeID T) {
+
+	if nodeID == rt.self {
+		return
+	}
+
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	rt.buckets[rt.bucketIndex(nodeID)].lastLookup = time.Now()
+}
+
+// Stale indexes of buckets without a lookup within interval, buckets
+// deeper than the deepest non empty bucket are never stale since
+// lookups in their range end at the same contacts
+func (rt *RoutingTable[T]) Stale(interval time.Duration) []int {
+
+	rt.mtx.RLock()
+	defer rt.mtx.RUnlock()
+
+	deepest := -1
+	for i, b := range rt.buckets {
+		if len(b.entries) > 0 {
+			deepest = i
+		}
+	}
+
+	now := time.Now()
+	stale := make([]int, 0)
+	for i := 0; i <= deepest; i++ {
+		if now.Sub(rt.buckets[i].lastLookup) >= interval {
+			stale = append(stale, i)
+		}
+	}
+
+	return stale
+}
+
+// RandomID random node id in range of bucket at index
+func (rt *RoutingTable[T]) RandomID(index int) T {
+
+	b := make([]byte, len(rt.self))
+	_, _ = io.ReadFull(rt.random, b)
+	nodeID, _ := domain.NodeIDFromBytes[T](b)
+
+	bucketID := rt.BucketID(index)
+
+	// keep shared prefix and flipped bit of bucket id
+	byteIndex, bitIndex := index/8, uint(index%8)
+	for i := 0; i < byteIndex; i++ {
+		nodeID[i] = bucketID[i]
+	}
+
+	mask := byte(0xFF) << (7 - bitIndex)
+	nodeID[byteIndex] = (bucketID[byteIndex] & mask) | (nodeID[byteIndex] &^ mask)
+
+	return nodeID
+}
+
+// Bucket contacts in bucket at index ordered from least to most recently seen
+func (rt *RoutingTable[T]) Bucket(index int) []*domain.Contact[T] {
+
+	if index < 0 || index >= len(rt.buckets) {
+		return []*domain.Contact[T]{}
+	}
+
+	rt.mtx.RLock()
+	defer rt.mtx.RUnlock()
+
+	b := rt.buckets[index]
+	contactSlice := make([]*domain.Contact[T], 0, len(b.entries))
+	for _, e := range b.entries {
+		contactSlice = append(contactSlice, e.contact)
+	}
+
+	return contactSlice
+}
+
+// Replacements contacts in replacement cache of bucket at index
+func (rt *RoutingTable[T]) Replacements(index int) []*domain.Contact[T] {
+
+	if index < 0 || index >= len(rt.buckets) {
+		return []*domain.Contact[T]{}
+	}
+
+	rt.mtx.RLock()
+	defer rt.mtx.RUnlock()
+
+	b := rt.buckets[index]
+	contactSlice := make([]*domain.Contact[T], 0, len(b.replacements))
+	for _, e := range b.replacements {
+		contactSlice = append(contactSlice, e.contact)
+	}
+
+	return contactSlice
+}
+
+// Buckets entries of non empty buckets ordered by index
+func (rt *RoutingTable[T]) Buckets() []domain.BucketInfo[T] {
+
+	rt.mtx.RLock()
+	defer rt.mtx.RUnlock()
+
+	buckets := make([]domain.BucketInfo[T], 0)
+	for i, b := range rt.buckets {
+		if len(b.entries) == 0 && len(b.replacements) == 0 {
+			continue
+		}
+
+		info := domain.BucketInfo[T]{
+			Index:        i,
+			LastLookup:   b.lastLookup,
+			Contacts:     make([]domain.ContactInfo[T], 0, len(b.entries)),
+			Replacements: make([]domain.ContactInfo[T], 0, len(b.replacements)),
+		}
+		for _, e := range b.entries {
+			info.Contacts = append(info.Contacts, e.info())
+		}
+		for _, e := range b.replacements {
+			info.Replacements = append(info.Replacements, e.info())
+		}
+		buckets = append(buckets, info)
+	}
+
+	return buckets
+}
+
+// Len number of contacts in routing table
+func (rt *RoutingTable[T]) Len() int {
+
+	rt.mtx.RLock()
+	defer rt.mtx.RUnlock()
+
+	var l int
+	for _, b := range rt.buckets {
+		l += len(b.entries)
+	}
+
+	return l
+}
+
+// BucketIndex index of bucket node id belongs in,
+// -1 for the local node id
+func (rt *RoutingTable[T]) BucketIndex(nodeID T) int {
+	if nodeID == rt.self {
+		return -1
+	}
+	return rt.bucketIndex(nodeID)
+}
+
+// BucketID lowest node id covered by bucket at index
+func (rt *RoutingTable[T]) BucketID(index int) T {
+
+	var bucketID T
+
+	// keep shared prefix and flip the following bit
+	for i := 0; i < index/8; i++ {
+		bucketID[i] = rt.self[i]
+	}
+
+	byteIndex, bitIndex := index/8, uint(index%8)
+	mask := byte(0xFF) << (8 - bitIndex)
+	bucketID[byteIndex] = (rt.self[byteIndex] & mask) | (^rt.self[byteIndex] & (0x80 >> bitIndex))
+
+	return bucketID
+}
+
+// admit check contact address is within the subnet limits
+func (rt *RoutingTable[T]) admit(b *kBucket[T], ip string) bool {
+
+	key := subnet(ip)
+	if key == "" {
+		return true
+	}
+
+	if rt.tableSubnetLimit > 0 && rt.subnets[key] >= rt.tableSubnetLimit {
+		return false
+	}
+
+	if rt.bucketSubnetLimit > 0 {
+		var count int
+		for _, e := range b.entries {
+			if subnet(e.contact.IP) == key {
+				count++
+			}
+		}
+		if count >= rt.bucketSubnetLimit {
+			return false
+		}
+	}
+
+	return true
+}
+
+// evicted report reason a contact was removed
+func (rt *RoutingTable[T]) evicted(reason string) {
+	if rt.onEvict != nil {
+		rt.onEvict(reason)
+	}
+}
+
+// remove entry at index from bucket
+func (rt *RoutingTable[T]) remove(b *kBucket[T], i int) {
+
+	rt.count(b.entries[i].contact.IP, -1)
+	b.entries = append(b.entries[:i], b.entries[i+1:]...)
+}
+
+// promote longest known replacement within the subnet limits into bucket
+func (rt *RoutingTable[T]) promote(b *kBucket[T]) bool {
+
+	oldest := -1
+	for i, e := range b.replacements {
+		if !rt.admit(b, e.contact.IP) {
+			continue
+		}
+		if oldest < 0 || e.firstSeen.Before(b.replacements[oldest].firstSeen) {
+			oldest = i
+		}
+	}
+
+	if oldest < 0 {
+		return false
+	}
+
+	e := b.replacements[oldest]
+	b.replacements = append(b.replacements[:oldest], b.replacements[oldest+1:]...)
+	b.entries = append(b.entries, e)
+	rt.count(e.contact.IP, 1)
+
+	return true
+}
+
+// count add delta to number of contacts in subnet of address
+func (rt *RoutingTable[T]) count(ip string, delta int) {
+
+	key := subnet(ip)
+	if key == "" {
+		return
+	}
+
+	if rt.subnets[key] += delta; rt.subnets[key] <= 0 {
+		delete(rt.subnets, key)
+	}
+}
+
+func (rt *RoutingTable[T]) bucketIndex(nodeID T) int {
+	return commonPrefixLen(rt.self, nodeID)
+}
+
+// commonPrefixLen number of leading bits shared by both node ids
+func commonPrefixLen[T domain.NodeID](a, b T) int {
+	for i := 0; i < len(a); i++ {
+		if x := a[i] ^ b[i]; x != 0 {
+			return i*8 + bits.LeadingZeros8(x)
+		}
+	}
+	return len(a) * 8
+}
+
+// subnet /24 prefix of ipv4 and /48 prefix of ipv6 addresses,
+// empty for loopback addresses which are not limited
+func subnet(host string) string {
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		// not an ip address, limit per host
+		return host
+	}
+
+	if ip.IsLoopback() {
+		return ""
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, 32)).String()
+	}
+
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
@@ -0,0 +1,359 @@
+package dht_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structx/go-dpkg/domain"
+	"github.com/structx/go-dpkg/structs/dht"
+)
+
+// contactInBucket contact sharing exactly index leading bits with the zero id
+func contactInBucket(index int, i byte) *domain.Contact[domain.NodeID224] {
+
+	var nodeID domain.NodeID224
+	nodeID[index/8] = 0x80 >> uint(index%8)
+	nodeID[len(nodeID)-1] = i + 1
+
+	return &domain.Contact[domain.NodeID224]{
+		IP:   fmt.Sprintf("10.0.%d.1", i+1),
+		Port: 50051,
+		ID:   nodeID,
+	}
+}
+
+type pinger struct {
+	mtx    sync.Mutex
+	alive  bool
+	pinged []domain.NodeID224
+}
+
+func (p *pinger) ping(_ context.Context, c *domain.Contact[domain.NodeID224]) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.pinged = append(p.pinged, c.ID)
+	if !p.alive {
+		return errors.New("timeout")
+	}
+	return nil
+}
+
+func Test_RoutingTableUpdate(t *testing.T) {
+	t.Run("bucket_index", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, domain.DefaultReplicationFactor, nil)
+		for _, index := range []int{0, 7, 8, 100, 223} {
+			c := contactInBucket(index, 0)
+			rt.Update(ctx, c)
+
+			assert.Equal(index, rt.BucketIndex(c.ID))
+			assert.Equal([]*domain.Contact[domain.NodeID224]{c}, rt.Bucket(index))
+			assert.Equal(index, rt.BucketIndex(rt.BucketID(index)))
+		}
+
+		rt.Update(ctx, &domain.Contact[domain.NodeID224]{ID: domain.NodeID224{}})
+		assert.Equal(5, rt.Len())
+	})
+	t.Run("move_to_tail", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, domain.DefaultReplicationFactor, nil)
+		c0, c1, c2 := contactInBucket(3, 0), contactInBucket(3, 1), contactInBucket(3, 2)
+		rt.Update(ctx, c0)
+		rt.Update(ctx, c1)
+		rt.Update(ctx, c2)
+		rt.Update(ctx, c0)
+
+		assert.Equal([]*domain.Contact[domain.NodeID224]{c1, c2, c0}, rt.Bucket(3))
+	})
+	t.Run("full_alive", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		p := &pinger{alive: true}
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, domain.DefaultReplicationFactor, p.ping)
+		c0, c1, c2, c3 := contactInBucket(3, 0), contactInBucket(3, 1), contactInBucket(3, 2), contactInBucket(3, 3)
+		rt.Update(ctx, c0)
+		rt.Update(ctx, c1)
+		rt.Update(ctx, c2)
+		rt.Update(ctx, c3)
+
+		// least recently seen contact answered and moved to tail
+		assert.Eventually(func() bool {
+			b := rt.Bucket(3)
+			return len(b) == 3 && b[2] == c0
+		}, time.Second, time.Millisecond*10)
+		assert.Equal([]*domain.Contact[domain.NodeID224]{c3}, rt.Replacements(3))
+	})
+	t.Run("full_unresponsive", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		p := &pinger{alive: false}
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, domain.DefaultReplicationFactor, p.ping)
+		c0, c1, c2, c3 := contactInBucket(3, 0), contactInBucket(3, 1), contactInBucket(3, 2), contactInBucket(3, 3)
+		rt.Update(ctx, c0)
+		rt.Update(ctx, c1)
+		rt.Update(ctx, c2)
+		rt.Update(ctx, c3)
+
+		// least recently seen contact evicted for replacement
+		assert.Eventually(func() bool {
+			b := rt.Bucket(3)
+			return len(b) == 3 && b[2] == c3
+		}, time.Second, time.Millisecond*10)
+		assert.Equal([]*domain.Contact[domain.NodeID224]{c1, c2, c3}, rt.Bucket(3))
+		assert.Empty(rt.Replacements(3))
+	})
+	t.Run("replacement_cache_limit", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, 2, nil)
+		for i := byte(0); i < 6; i++ {
+			rt.Update(ctx, contactInBucket(5, i))
+		}
+
+		// newcomers are dropped in favour of longer known replacements
+		assert.Len(rt.Bucket(5), 2)
+		assert.Equal([]*domain.Contact[domain.NodeID224]{contactInBucket(5, 2), contactInBucket(5, 3)}, rt.Replacements(5))
+	})
+}
+
+func Test_RoutingTableSubnetLimits(t *testing.T) {
+	t.Run("bucket", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, 8, nil)
+		for i := byte(0); i < 4; i++ {
+			c := contactInBucket(3, i)
+			c.IP = fmt.Sprintf("192.0.2.%d", i+1)
+			rt.Update(ctx, c)
+		}
+		rt.Update(ctx, contactInBucket(3, 4))
+
+		// third contact of the same /24 is ignored
+		assert.Len(rt.Bucket(3), 3)
+		assert.Empty(rt.Replacements(3))
+	})
+	t.Run("table", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, 8, nil)
+		for index := 0; index < 20; index++ {
+			c := contactInBucket(index, 0)
+			c.IP = fmt.Sprintf("192.0.2.%d", index+1)
+			rt.Update(ctx, c)
+		}
+		assert.Equal(dht.DefaultTableSubnetLimit, rt.Len())
+
+		rt.SetSubnetLimits(0, 0)
+		c := contactInBucket(20, 0)
+		c.IP = "192.0.2.100"
+		rt.Update(ctx, c)
+		assert.Equal(dht.DefaultTableSubnetLimit+1, rt.Len())
+	})
+	t.Run("ipv6_and_loopback", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, 8, nil)
+		hosts := []string{"2001:db8:1:1::1", "2001:db8:1:2::1", "2001:db8:1:3::1", "2001:db8:2::1", "127.0.0.1", "127.0.0.2", "127.0.0.3"}
+		for i, host := range hosts {
+			c := contactInBucket(3, byte(i))
+			c.IP = host
+			rt.Update(ctx, c)
+		}
+
+		// third address of 2001:db8:1::/48 is ignored
+		assert.Len(rt.Bucket(3), len(hosts)-1)
+	})
+	t.Run("promote_within_limits", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, 2, nil)
+		c0, c1, c2, c3 := contactInBucket(3, 0), contactInBucket(3, 1), contactInBucket(3, 2), contactInBucket(3, 3)
+		c1.IP, c2.IP = "192.0.2.1", "192.0.2.2"
+		rt.Update(ctx, c0)
+		rt.Update(ctx, c1)
+		rt.Update(ctx, c2)
+		rt.Update(ctx, c3)
+		assert.Equal([]*domain.Contact[domain.NodeID224]{c2, c3}, rt.Replacements(3))
+
+		// c2 was seen first but shares 192.0.2.0/24 with c1
+		rt.SetSubnetLimits(1, 0)
+		rt.Fail(c0.ID)
+		assert.Equal([]*domain.Contact[domain.NodeID224]{c1, c3}, rt.Bucket(3))
+	})
+	t.Run("changed_ip", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, 8, nil)
+		for i := byte(0); i < 2; i++ {
+			c := contactInBucket(3, i)
+			c.IP = fmt.Sprintf("192.0.2.%d", i+1)
+			rt.Update(ctx, c)
+		}
+		c := contactInBucket(3, 2)
+		rt.Update(ctx, c)
+
+		// moving into a full subnet is rejected
+		moved := contactInBucket(3, 2)
+		moved.IP = "192.0.2.3"
+		rt.Update(ctx, moved)
+		assert.Equal(c, rt.Bucket(3)[2])
+
+		// moving within a subnet or into one with room is not
+		moved.IP = "10.0.3.2"
+		rt.Update(ctx, moved)
+		assert.Equal("10.0.3.2", rt.Bucket(3)[2].IP)
+
+		moved = contactInBucket(3, 2)
+		moved.IP = "198.51.100.1"
+		rt.Update(ctx, moved)
+		assert.Equal("198.51.100.1", rt.Bucket(3)[2].IP)
+	})
+}
+
+func Test_RoutingTableFail(t *testing.T) {
+	t.Run("replace", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, 2, nil)
+		c0, c1, c2 := contactInBucket(1, 0), contactInBucket(1, 1), contactInBucket(1, 2)
+		rt.Update(ctx, c0)
+		rt.Update(ctx, c1)
+		rt.Update(ctx, c2)
+
+		rt.Fail(c0.ID)
+		assert.Equal([]*domain.Contact[domain.NodeID224]{c1, c2}, rt.Bucket(1))
+	})
+	t.Run("stale", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, 2, nil)
+		c := contactInBucket(1, 0)
+		rt.Update(ctx, c)
+
+		rt.Fail(c.ID)
+		rt.Fail(c.ID)
+		assert.Len(rt.Bucket(1), 1)
+
+		rt.Fail(c.ID)
+		assert.Empty(rt.Bucket(1))
+	})
+	t.Run("remove", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, 2, nil)
+		c0, c1, c2, c3 := contactInBucket(1, 0), contactInBucket(1, 1), contactInBucket(1, 2), contactInBucket(1, 3)
+		rt.Update(ctx, c0)
+		rt.Update(ctx, c1)
+		rt.Update(ctx, c2)
+		rt.Update(ctx, c3)
+
+		// removed without failing first and replaced at once
+		rt.Remove(c0.ID)
+		assert.Equal([]*domain.Contact[domain.NodeID224]{c1, c2}, rt.Bucket(1))
+
+		rt.Remove(c3.ID)
+		assert.Empty(rt.Replacements(1))
+	})
+}
+
+func Test_RoutingTableSelf(t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	self := domain.HashKey[domain.NodeID224]([]byte("self"))
+	rt := dht.NewRoutingTable[domain.NodeID224](self, 2, nil)
+	rt.Update(ctx, newContact(0))
+
+	// the own id has no bucket and is ignored
+	assert.NotPanics(func() {
+		rt.Update(ctx, &domain.Contact[domain.NodeID224]{IP: "10.0.0.1", Port: 50051, ID: self})
+		rt.RecordRTT(self, time.Millisecond)
+		assert.Zero(rt.RTT(self))
+		rt.Fail(self)
+		rt.Remove(self)
+		rt.Touch(self)
+	})
+	assert.Equal(1, rt.Len())
+	assert.Equal(-1, rt.BucketIndex(self))
+}
+
+func Test_RoutingTableClosest(t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	self := domain.HashKey[domain.NodeID224]([]byte("self"))
+	rt := dht.NewRoutingTable[domain.NodeID224](self, domain.DefaultReplicationFactor, nil)
+
+	for i := 0; i < 100; i++ {
+		rt.Update(ctx, newContact(i))
+	}
+
+	target := domain.HashKey[domain.NodeID224]([]byte("target"))
+	all := rt.Closest(target, rt.Len())
+	closest := rt.Closest(target, domain.DefaultReplicationFactor)
+	assert.Equal(all[:domain.DefaultReplicationFactor], closest)
+
+	for i := 1; i < len(all); i++ {
+		a := domain.Distance(target, all[i-1].ID)
+		b := domain.Distance(target, all[i].ID)
+		assert.Less(string(a[:]), string(b[:]))
+	}
+}
+
+func Test_RoutingTableRefresh(t *testing.T) {
+
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	rt := dht.NewRoutingTable[domain.NodeID224](domain.NodeID224{}, domain.DefaultReplicationFactor, nil)
+	rt.Update(ctx, contactInBucket(4, 0))
+
+	for _, index := range []int{0, 3, 9, 100, 223} {
+		assert.Equal(index, rt.BucketIndex(rt.RandomID(index)))
+	}
+
+	// buckets up to the deepest non empty bucket
+	assert.Equal([]int{0, 1, 2, 3, 4}, rt.Stale(0))
+	assert.Empty(rt.Stale(time.Hour))
+
+	time.Sleep(time.Millisecond * 20)
+	rt.Touch(rt.RandomID(2))
+	assert.Equal([]int{0, 1, 3, 4}, rt.Stale(time.Millisecond*10))
+}
@@ -0,0 +1,98 @@
+package sim
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report outcome of a simulation
+type Report struct {
+	// Nodes live nodes at the end of the simulation
+	Nodes int
+	// JoinFailures nodes that could not reach any seed
+	JoinFailures int
+
+	// Lookups number of lookups
+	Lookups int
+	// Succeeded lookups that found the closest live node
+	Succeeded int
+	// SuccessRate fraction of lookups that succeeded
+	SuccessRate float64
+	// Hops lookups by number of sequential queries to the closest contact
+	Hops map[int]int
+	// MeanHops average hops of lookups that returned contacts
+	MeanHops float64
+	// MeanLatency average virtual time of lookups that returned contacts
+	MeanLatency time.Duration
+
+	// Contacts average number of contacts per routing table
+	Contacts float64
+	// BucketFill average fraction of k used by buckets
+	// up to the deepest non empty bucket
+	BucketFill float64
+	// StaleContacts fraction of contacts of nodes that left
+	StaleContacts float64
+
+	// Messages number of messages sent including responses
+	Messages int64
+	// Dropped messages lost to packet loss
+	Dropped int64
+	// MeanBytes average bytes sent and received per live node
+	MeanBytes int64
+	// MaxBytes most bytes sent and received by a live node
+	MaxBytes int64
+
+	hopTotal       int
+	latencyTotal   time.Duration
+	bandwidthTotal int64
+}
+
+func newReport() *Report {
+	return &Report{
+		Hops: make(map[int]int),
+	}
+}
+
+// finish averages over lookups and live nodes
+func (r *Report) finish(nodes int) {
+
+	if r.Lookups > 0 {
+		r.SuccessRate = float64(r.Succeeded) / float64(r.Lookups)
+	}
+
+	var answered int
+	for _, count := range r.Hops {
+		answered += count
+	}
+
+	if answered > 0 {
+		r.MeanHops = float64(r.hopTotal) / float64(answered)
+		r.MeanLatency = r.latencyTotal / time.Duration(answered)
+	}
+
+	if nodes > 0 {
+		r.MeanBytes = r.bandwidthTotal / int64(nodes)
+	}
+}
+
+// String summary of report
+func (r *Report) String() string {
+
+	hops := make([]int, 0, len(r.Hops))
+	for h := range r.Hops {
+		hops = append(hops, h)
+	}
+	sort.Ints(hops)
+
+	histogram := make([]string, 0, len(hops))
+	for _, h := range hops {
+		histogram = append(histogram, fmt.Sprintf("%d:%d", h, r.Hops[h]))
+	}
+
+	return fmt.Sprintf("nodes=%d join_failures=%d lookups=%d success=%.3f hops=%.2f [%s] latency=%v "+
+		"contacts=%.1f bucket_fill=%.3f stale=%.3f messages=%d dropped=%d bytes_mean=%d bytes_max=%d",
+		r.Nodes, r.JoinFailures, r.Lookups, r.SuccessRate, r.MeanHops, strings.Join(histogram, " "), r.MeanLatency,
+		r.Contacts, r.BucketFill, r.StaleContacts, r.Messages, r.Dropped, r.MeanBytes, r.MaxBytes)
+}
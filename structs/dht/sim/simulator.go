@@ -0,0 +1,380 @@
+// Package sim deterministic in-memory simulation of a dht network
+//
+// every node runs the regular structs/dht node against an in-memory
+// transport. latency is tracked in virtual time instead of delaying
+// messages, packet loss and churn are derived from the seed so runs
+// with the same configuration produce the same report
+package sim
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/structx/go-dpkg/domain"
+	"github.com/structx/go-dpkg/structs/dht"
+)
+
+const (
+	// DefaultTimeout virtual time after which a lost message fails
+	DefaultTimeout = time.Second
+	// simPort port of every simulated node
+	simPort = 50051
+	// joinAttempts seeds tried before a joining node gives up
+	joinAttempts = 3
+)
+
+// Config simulation parameters
+type Config struct {
+	// Seed of every random decision in the simulation
+	Seed int64
+	// Nodes number of nodes joining before the first round
+	Nodes int
+	// Rounds number of churn and lookup rounds
+	Rounds int
+	// Lookups per round from random nodes to random targets
+	Lookups int
+	// Latency minimum one way latency of a link
+	Latency time.Duration
+	// Jitter maximum additional one way latency of a link
+	Jitter time.Duration
+	// Timeout virtual time after which a lost message fails
+	Timeout time.Duration
+	// Loss probability a message is dropped
+	Loss float64
+	// Churn fraction of nodes replaced by new nodes every round
+	Churn float64
+	// ReplicationFactor bucket size k of every node
+	ReplicationFactor int
+	// Concurrency parallel queries alpha of every node
+	Concurrency int
+}
+
+// Simulator network of simulated nodes over node ids of width T
+type Simulator[T domain.NodeID] struct {
+	cfg Config
+	rng *rand.Rand
+
+	// live nodes in join order
+	live []*simNode[T]
+	// nodes every node that tried to join by address
+	nodes map[string]*simNode[T]
+	// joined number of nodes that joined, used for addresses
+	joined int
+
+	net *network[T]
+}
+
+// simNode node with bandwidth counters
+type simNode[T domain.NodeID] struct {
+	*dht.Node[T]
+	contact *domain.Contact[T]
+	alive   bool
+
+	sent     int64
+	received int64
+}
+
+// New constructor
+func New[T domain.NodeID](cfg Config) (*Simulator[T], error) {
+
+	if cfg.Nodes < 1 {
+		return nil, errors.New("simulation requires at least one node")
+	}
+
+	if cfg.Loss < 0 || cfg.Loss >= 1 {
+		return nil, fmt.Errorf("invalid loss %v", cfg.Loss)
+	}
+
+	if cfg.Churn < 0 || cfg.Churn >= 1 {
+		return nil, fmt.Errorf("invalid churn %v", cfg.Churn)
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	if cfg.ReplicationFactor <= 0 {
+		cfg.ReplicationFactor = domain.DefaultReplicationFactor
+	}
+
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = domain.Concurrent
+	}
+
+	s := &Simulator[T]{
+		cfg:   cfg,
+		rng:   rand.New(rand.NewSource(cfg.Seed)),
+		live:  make([]*simNode[T], 0, cfg.Nodes),
+		nodes: make(map[string]*simNode[T], cfg.Nodes),
+	}
+	s.net = newNetwork(s)
+
+	return s, nil
+}
+
+// Run join nodes followed by rounds of churn and lookups,
+// returns report of lookups and routing tables at the end
+func (s *Simulator[T]) Run(ctx context.Context) (*Report, error) {
+
+	r := newReport()
+
+	for i := 0; i < s.cfg.Nodes; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := s.Join(ctx); err != nil {
+			// node could not reach any seed
+			r.JoinFailures++
+		}
+	}
+
+	for round := 0; round < s.cfg.Rounds; round++ {
+
+		if err := s.churn(ctx, r); err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < s.cfg.Lookups; i++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			s.lookup(ctx, r)
+		}
+	}
+
+	s.fill(r)
+
+	return r, nil
+}
+
+// Join add a new node bootstrapping through a random live node,
+// seeds that fail to respond are retried with another live node
+func (s *Simulator[T]) Join(ctx context.Context) error {
+
+	n := s.newNode(ctx)
+	s.nodes[n.contact.Address()] = n
+
+	if len(s.live) == 0 {
+		s.live = append(s.live, n)
+		return nil
+	}
+
+	var errs []error
+	for i := 0; i < joinAttempts; i++ {
+
+		seed := s.live[s.rng.Intn(len(s.live))].contact
+
+		var err error
+		s.net.do(ctx, func(ctx context.Context) {
+			err = n.Bootstrap(ctx, []*domain.Contact[T]{seed})
+		})
+		if err == nil {
+			s.live = append(s.live, n)
+			return nil
+		}
+		errs = append(errs, err)
+	}
+
+	n.alive = false
+
+	return errors.Join(errs...)
+}
+
+// Leave remove live node at index without notifying other nodes
+func (s *Simulator[T]) Leave(index int) {
+
+	n := s.live[index]
+	n.alive = false
+
+	s.live = append(s.live[:index], s.live[index+1:]...)
+}
+
+// Len number of live nodes
+func (s *Simulator[T]) Len() int {
+	return len(s.live)
+}
+
+// churn replace fraction of live nodes with new nodes
+func (s *Simulator[T]) churn(ctx context.Context, r *Report) error {
+
+	if s.cfg.Churn == 0 {
+		return nil
+	}
+
+	leaving := int(float64(len(s.live)) * s.cfg.Churn)
+	for i := 0; i < leaving && len(s.live) > 1; i++ {
+		s.Leave(s.rng.Intn(len(s.live)))
+	}
+
+	for i := 0; i < leaving; i++ {
+		if err := s.Join(ctx); err != nil {
+			// node could not reach any seed
+			r.JoinFailures++
+		}
+	}
+
+	return nil
+}
+
+// lookup random target from random live node and compare
+// the closest contact found with the closest live node
+func (s *Simulator[T]) lookup(ctx context.Context, r *Report) {
+
+	n := s.live[s.rng.Intn(len(s.live))]
+	target := s.randomID()
+
+	var (
+		result []*domain.Contact[T]
+		err    error
+	)
+	t := s.net.do(ctx, func(ctx context.Context) {
+		result, err = n.Lookup(ctx, target)
+	})
+
+	r.Lookups++
+	if err != nil || len(result) == 0 {
+		return
+	}
+
+	if result[0].ID == s.closest(target, n.ID) {
+		r.Succeeded++
+	}
+
+	hops := t.depth(result[0].ID) + 1
+	r.Hops[hops]++
+	r.hopTotal += hops
+	r.latencyTotal += t.elapsed()
+}
+
+// closest live node to target other than self
+func (s *Simulator[T]) closest(target, self T) T {
+
+	var best T
+	found := false
+	for _, n := range s.live {
+		if n.ID == self {
+			continue
+		}
+		if !found || domain.Compare(domain.Distance(target, n.ID), domain.Distance(target, best)) < 0 {
+			best, found = n.ID, true
+		}
+	}
+
+	return best
+}
+
+// fill routing table and bandwidth statistics of live nodes
+func (s *Simulator[T]) fill(r *Report) {
+
+	r.Nodes = len(s.live)
+
+	alive := make(map[T]struct{}, len(s.live))
+	for _, n := range s.live {
+		alive[n.ID] = struct{}{}
+	}
+
+	var contacts, stale int
+	var bucketFill float64
+	for _, n := range s.live {
+
+		rt := n.RoutingTable()
+
+		deepest := -1
+		sizes := make([]int, domain.Bits[T]())
+		for i := range sizes {
+			for _, c := range rt.Bucket(i) {
+				sizes[i]++
+				contacts++
+				if _, ok := alive[c.ID]; !ok {
+					stale++
+				}
+			}
+			if sizes[i] > 0 {
+				deepest = i
+			}
+		}
+
+		var fill float64
+		for i := 0; i <= deepest; i++ {
+			fill += float64(sizes[i]) / float64(s.cfg.ReplicationFactor)
+		}
+		if deepest >= 0 {
+			bucketFill += fill / float64(deepest+1)
+		}
+
+		bytes := n.sent + n.received
+		r.bandwidthTotal += bytes
+		if bytes > r.MaxBytes {
+			r.MaxBytes = bytes
+		}
+	}
+
+	r.Messages, r.Dropped = s.net.messages, s.net.dropped
+	r.Contacts = float64(contacts) / float64(len(s.live))
+	r.BucketFill = bucketFill / float64(len(s.live))
+	if contacts > 0 {
+		r.StaleContacts = float64(stale) / float64(contacts)
+	}
+	r.finish(len(s.live))
+}
+
+func (s *Simulator[T]) newNode(ctx context.Context) *simNode[T] {
+
+	i := s.joined
+	s.joined++
+
+	// node keys are derived from the seed like every other decision
+	keySeed := make([]byte, ed25519.SeedSize)
+	_, _ = s.rng.Read(keySeed)
+	pub := ed25519.NewKeyFromSeed(keySeed).Public().(ed25519.PublicKey)
+
+	// every node in its own /24 subnet
+	ip := fmt.Sprintf("%d.%d.%d.1", 10+(i>>16)&0xFF, (i>>8)&0xFF, i&0xFF)
+
+	n := &simNode[T]{alive: true}
+	n.Node = dht.NewNode[T](ctx, ip, simPort, s.cfg.ReplicationFactor, pub,
+		dht.WithTransport[T](&transport[T]{net: s.net, self: n}),
+		dht.WithConcurrency[T](s.cfg.Concurrency),
+		dht.WithRandom[T](rand.New(rand.NewSource(s.rng.Int63()))),
+		dht.WithSyncEviction[T](),
+		dht.WithXOROrder[T](),
+	)
+	n.contact = &domain.Contact[T]{
+		IP:   ip,
+		Port: simPort,
+		ID:   n.ID,
+	}
+
+	return n
+}
+
+// randomID seeded random node id
+func (s *Simulator[T]) randomID() T {
+
+	var nodeID T
+	var b [8]byte
+	for i := 0; i < len(nodeID); i++ {
+		if i%8 == 0 {
+			binary.BigEndian.PutUint64(b[:], s.rng.Uint64())
+		}
+		nodeID[i] = b[i%8]
+	}
+
+	return nodeID
+}
+
+// sortObservations deterministic order of routing table updates
+func sortObservations[T domain.NodeID](o []observation[T]) {
+	sort.Slice(o, func(i, j int) bool {
+		if c := domain.Compare(o[i].node.ID, o[j].node.ID); c != 0 {
+			return c < 0
+		}
+		return domain.Compare(o[i].contact.ID, o[j].contact.ID) < 0
+	})
+}
@@ -0,0 +1,148 @@
+package sim_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structx/go-dpkg/domain"
+	"github.com/structx/go-dpkg/structs/dht/sim"
+)
+
+func run(t *testing.T, cfg sim.Config) *sim.Report {
+
+	s, err := sim.New[domain.NodeID224](cfg)
+	assert.NoError(t, err)
+
+	r, err := s.Run(context.TODO())
+	assert.NoError(t, err)
+	t.Log(r)
+
+	return r
+}
+
+func Test_Run(t *testing.T) {
+	t.Run("stable", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		r := run(t, sim.Config{
+			Seed:              1,
+			Nodes:             500,
+			Rounds:            2,
+			Lookups:           100,
+			Latency:           time.Millisecond * 20,
+			Jitter:            time.Millisecond * 80,
+			ReplicationFactor: 8,
+		})
+
+		assert.Equal(500, r.Nodes)
+		assert.Zero(r.JoinFailures)
+		assert.Equal(200, r.Lookups)
+		assert.GreaterOrEqual(r.SuccessRate, 0.95)
+		assert.Greater(r.MeanHops, 1.0)
+		assert.GreaterOrEqual(r.MeanLatency, time.Millisecond*40)
+		assert.Greater(r.Contacts, 8.0)
+		assert.Zero(r.StaleContacts)
+		assert.Zero(r.Dropped)
+		assert.Greater(r.MeanBytes, int64(0))
+	})
+	t.Run("loss_and_churn", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		r := run(t, sim.Config{
+			Seed:              2,
+			Nodes:             500,
+			Rounds:            4,
+			Lookups:           50,
+			Loss:              0.05,
+			Churn:             0.1,
+			ReplicationFactor: 8,
+		})
+
+		assert.Greater(r.Dropped, int64(0))
+		assert.Greater(r.StaleContacts, 0.0)
+		assert.GreaterOrEqual(r.SuccessRate, 0.8)
+	})
+	t.Run("wide_ids", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		s, err := sim.New[domain.NodeID512](sim.Config{Seed: 3, Nodes: 200, Rounds: 1, Lookups: 50, ReplicationFactor: 8})
+		assert.NoError(err)
+
+		r, err := s.Run(context.TODO())
+		assert.NoError(err)
+		assert.GreaterOrEqual(r.SuccessRate, 0.95)
+	})
+	t.Run("invalid", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		_, err := sim.New[domain.NodeID224](sim.Config{})
+		assert.Error(err)
+
+		_, err = sim.New[domain.NodeID224](sim.Config{Nodes: 1, Loss: 1})
+		assert.Error(err)
+	})
+}
+
+func Test_Deterministic(t *testing.T) {
+
+	assert := assert.New(t)
+
+	cfg := sim.Config{
+		Seed:              4,
+		Nodes:             300,
+		Rounds:            3,
+		Lookups:           50,
+		Latency:           time.Millisecond * 10,
+		Jitter:            time.Millisecond * 40,
+		Loss:              0.05,
+		Churn:             0.1,
+		ReplicationFactor: 8,
+	}
+
+	assert.Equal(run(t, cfg), run(t, cfg))
+
+	other := cfg
+	other.Seed++
+	assert.NotEqual(run(t, cfg), run(t, other))
+}
+
+// BenchmarkRun thousands of nodes with loss and churn,
+// run with -bench Run -benchtime 1x
+func BenchmarkRun(b *testing.B) {
+
+	cfg := sim.Config{
+		Seed:              5,
+		Nodes:             2000,
+		Rounds:            2,
+		Lookups:           100,
+		Latency:           time.Millisecond * 20,
+		Jitter:            time.Millisecond * 80,
+		Loss:              0.01,
+		Churn:             0.05,
+		ReplicationFactor: 20,
+	}
+
+	for i := 0; i < b.N; i++ {
+
+		s, err := sim.New[domain.NodeID224](cfg)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		r, err := s.Run(context.TODO())
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.ReportMetric(r.SuccessRate, "success")
+		b.ReportMetric(r.MeanHops, "hops")
+		b.ReportMetric(float64(r.MeanBytes), "bytes/node")
+	}
+}
@@ -0,0 +1,440 @@
+package sim
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+var (
+	// ErrTimeout message or response was lost
+	ErrTimeout = errors.New("simulated message timed out")
+)
+
+const (
+	// senderSize signed sender of every message without node id
+	senderSize = ed25519.PublicKeySize + ed25519.SignatureSize + 12
+	// addressSize ipv4 address and port of a contact
+	addressSize = 6
+	// statusSize store response status
+	statusSize = 2
+	// timestampSize record publication time
+	timestampSize = 12
+	// seqSize record version
+	seqSize = 8
+	// ttlSize provider record lifetime
+	ttlSize = 8
+)
+
+// kind of simulated message
+type kind byte
+
+const (
+	ping kind = iota + 1
+	store
+	findNode
+	findValue
+	addProvider
+	getProviders
+	// response flag of kind
+	response kind = 0x80
+)
+
+// observation node received a message from contact,
+// applied to the routing table once the operation finished
+type observation[T domain.NodeID] struct {
+	node    *simNode[T]
+	contact *domain.Contact[T]
+}
+
+type attemptKey[T domain.NodeID] struct {
+	from, to, key T
+	kind          kind
+}
+
+// network delivers messages between simulated nodes
+//
+// routing table updates of receiving nodes are deferred until the
+// running operation finished and applied in node id order, so the
+// outcome does not depend on the order parallel queries are answered
+type network[T domain.NodeID] struct {
+	s *Simulator[T]
+
+	mtx      sync.Mutex
+	op       uint64
+	attempts map[attemptKey[T]]int
+	pending  []observation[T]
+
+	messages int64
+	dropped  int64
+}
+
+func newNetwork[T domain.NodeID](s *Simulator[T]) *network[T] {
+	return &network[T]{
+		s:        s,
+		attempts: make(map[attemptKey[T]]int),
+	}
+}
+
+type traceKey struct{}
+
+// do run operation with a new trace and apply
+// deferred routing table updates afterwards
+func (nw *network[T]) do(ctx context.Context, f func(ctx context.Context)) *trace[T] {
+
+	nw.mtx.Lock()
+	nw.op++
+	clear(nw.attempts)
+	nw.mtx.Unlock()
+
+	t := newTrace[T]()
+	f(context.WithValue(ctx, traceKey{}, t))
+
+	nw.settle(ctx)
+
+	return t
+}
+
+// settle apply deferred routing table updates until none are left,
+// updates may ping contacts which defers further updates
+func (nw *network[T]) settle(ctx context.Context) {
+	for {
+		nw.mtx.Lock()
+		pending := nw.pending
+		nw.pending = nil
+		nw.mtx.Unlock()
+
+		if len(pending) == 0 {
+			return
+		}
+
+		sortObservations(pending)
+		for _, o := range pending {
+			if o.node.alive {
+				o.node.AddOrUpdateRoutingTable(ctx, o.contact)
+			}
+		}
+	}
+}
+
+// send request from node to contact, returns receiving node
+// and round trip time once the response size is known
+func (nw *network[T]) send(ctx context.Context, from *simNode[T], c *domain.Contact[T], k kind, key T, size int) (*simNode[T], hop, error) {
+
+	t := traceFrom[T](ctx)
+	h := t.start(c.ID)
+
+	nw.mtx.Lock()
+	defer nw.mtx.Unlock()
+
+	nw.messages++
+	from.sent += int64(size)
+
+	to, ok := nw.s.nodes[c.Address()]
+	if !ok || !to.alive || nw.lost(from.ID, c.ID, key, k) {
+		if ok && to.alive {
+			nw.dropped++
+		}
+		t.finish(h.at + nw.s.cfg.Timeout)
+		return nil, h, ErrTimeout
+	}
+
+	to.received += int64(size)
+	nw.pending = append(nw.pending, observation[T]{node: to, contact: from.contact})
+
+	return to, h, nil
+}
+
+// reply response of size from node to requesting node, contacts
+// in the response are learned one hop after the responder
+func (nw *network[T]) reply(ctx context.Context, from, to *simNode[T], h hop, k kind, key T, size int, contactSlice []*domain.Contact[T]) error {
+
+	t := traceFrom[T](ctx)
+	rtt := 2 * nw.latency(from.ID, to.ID)
+
+	nw.mtx.Lock()
+	defer nw.mtx.Unlock()
+
+	nw.messages++
+	from.sent += int64(size)
+
+	if !to.alive || nw.lost(from.ID, to.ID, key, k|response) {
+		nw.dropped++
+		t.finish(h.at + nw.s.cfg.Timeout)
+		return ErrTimeout
+	}
+
+	to.received += int64(size)
+
+	t.learn(contactSlice, hop{depth: h.depth + 1, at: h.at + rtt})
+	t.finish(h.at + rtt)
+
+	return nil
+}
+
+// lost decide if message is dropped from seed, operation and message
+func (nw *network[T]) lost(from, to, key T, k kind) bool {
+
+	if nw.s.cfg.Loss == 0 {
+		return false
+	}
+
+	ak := attemptKey[T]{from: from, to: to, key: key, kind: k}
+	attempt := nw.attempts[ak]
+	nw.attempts[ak]++
+
+	h := fnv.New64a()
+	var b [8]byte
+	for _, v := range []uint64{uint64(nw.s.cfg.Seed), nw.op, uint64(attempt), uint64(k)} {
+		binary.BigEndian.PutUint64(b[:], v)
+		_, _ = h.Write(b[:])
+	}
+	_, _ = h.Write(domain.Bytes(from))
+	_, _ = h.Write(domain.Bytes(to))
+	_, _ = h.Write(domain.Bytes(key))
+
+	return float64(h.Sum64()>>11)/(1<<53) < nw.s.cfg.Loss
+}
+
+// latency one way latency of link between two nodes
+func (nw *network[T]) latency(a, b T) time.Duration {
+
+	if nw.s.cfg.Jitter <= 0 {
+		return nw.s.cfg.Latency
+	}
+
+	if domain.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+
+	h := fnv.New64a()
+	var s [8]byte
+	binary.BigEndian.PutUint64(s[:], uint64(nw.s.cfg.Seed))
+	_, _ = h.Write(s[:])
+	_, _ = h.Write(domain.Bytes(a))
+	_, _ = h.Write(domain.Bytes(b))
+
+	return nw.s.cfg.Latency + time.Duration(h.Sum64()%uint64(nw.s.cfg.Jitter))
+}
+
+// transport of a single simulated node
+type transport[T domain.NodeID] struct {
+	net  *network[T]
+	self *simNode[T]
+}
+
+// interface compliance
+var _ domain.Transport[domain.NodeID224] = (*transport[domain.NodeID224])(nil)
+
+func (t *transport[T]) Ping(ctx context.Context, c *domain.Contact[T]) (T, error) {
+
+	var zero T
+
+	to, h, err := t.net.send(ctx, t.self, c, ping, zero, senderSize+idSize[T]())
+	if err != nil {
+		return zero, err
+	}
+
+	if err := t.net.reply(ctx, to, t.self, h, ping, zero, senderSize+idSize[T](), nil); err != nil {
+		return zero, err
+	}
+
+	return to.ID, nil
+}
+
+func (t *transport[T]) Store(ctx context.Context, c *domain.Contact[T], record *domain.Record[T]) error {
+
+	to, h, err := t.net.send(ctx, t.self, c, store, record.Key, senderSize+idSize[T]()+recordSize(record))
+	if err != nil {
+		return err
+	}
+
+	storeErr := to.StoreRecord(ctx, record)
+
+	if err := t.net.reply(ctx, to, t.self, h, store, record.Key, statusSize, nil); err != nil {
+		return err
+	}
+
+	return storeErr
+}
+
+func (t *transport[T]) FindNode(ctx context.Context, c *domain.Contact[T], nodeID T) ([]*domain.Contact[T], error) {
+
+	to, h, err := t.net.send(ctx, t.self, c, findNode, nodeID, senderSize+2*idSize[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	contactSlice := to.ClosestContacts(nodeID, t.net.s.cfg.ReplicationFactor)
+
+	if err := t.net.reply(ctx, to, t.self, h, findNode, nodeID, contactsSize(contactSlice), contactSlice); err != nil {
+		return nil, err
+	}
+
+	return contactSlice, nil
+}
+
+func (t *transport[T]) FindValue(ctx context.Context, c *domain.Contact[T], key T) (*domain.Record[T], []*domain.Contact[T], error) {
+
+	to, h, err := t.net.send(ctx, t.self, c, findValue, key, senderSize+2*idSize[T]())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record, err := to.FindRecord(ctx, key)
+	if err == nil {
+		if err := t.net.reply(ctx, to, t.self, h, findValue, key, recordSize(record), nil); err != nil {
+			return nil, nil, err
+		}
+		return record, nil, nil
+	}
+
+	contactSlice := to.ClosestContacts(key, t.net.s.cfg.ReplicationFactor)
+
+	if err := t.net.reply(ctx, to, t.self, h, findValue, key, contactsSize(contactSlice), contactSlice); err != nil {
+		return nil, nil, err
+	}
+
+	return nil, contactSlice, nil
+}
+
+func (t *transport[T]) AddProvider(ctx context.Context, c *domain.Contact[T], provider *domain.Provider[T]) error {
+
+	to, h, err := t.net.send(ctx, t.self, c, addProvider, provider.Key, senderSize+2*idSize[T]()+providerSize[T]())
+	if err != nil {
+		return err
+	}
+
+	addErr := to.AddProvider(ctx, provider)
+
+	if err := t.net.reply(ctx, to, t.self, h, addProvider, provider.Key, statusSize, nil); err != nil {
+		return err
+	}
+
+	return addErr
+}
+
+func (t *transport[T]) GetProviders(ctx context.Context, c *domain.Contact[T], key T) ([]*domain.Provider[T], []*domain.Contact[T], error) {
+
+	to, h, err := t.net.send(ctx, t.self, c, getProviders, key, senderSize+2*idSize[T]())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	providers, err := to.Providers(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	contactSlice := to.ClosestContacts(key, t.net.s.cfg.ReplicationFactor)
+
+	size := len(providers)*providerSize[T]() + contactsSize(contactSlice)
+	if err := t.net.reply(ctx, to, t.self, h, getProviders, key, size, contactSlice); err != nil {
+		return nil, nil, err
+	}
+
+	return providers, contactSlice, nil
+}
+
+func idSize[T domain.NodeID]() int {
+	return domain.Bits[T]() / 8
+}
+
+func recordSize[T domain.NodeID](record *domain.Record[T]) int {
+	return 2*idSize[T]() + len(record.Value) + timestampSize + seqSize + len(record.PublicKey) + len(record.Salt) + len(record.Signature)
+}
+
+func providerSize[T domain.NodeID]() int {
+	return idSize[T]() + addressSize + ttlSize
+}
+
+func contactsSize[T domain.NodeID](contactSlice []*domain.Contact[T]) int {
+	return len(contactSlice) * (idSize[T]() + addressSize)
+}
+
+// hop position of a contact in the trace of an operation
+type hop struct {
+	// depth number of responses on the path to the contact
+	depth int
+	// at virtual time the contact was learned
+	at time.Duration
+}
+
+// trace contacts learned during an operation, the earliest
+// path is kept so parallel responses are order independent
+type trace[T domain.NodeID] struct {
+	mtx  sync.Mutex
+	hops map[T]hop
+	end  time.Duration
+}
+
+func newTrace[T domain.NodeID]() *trace[T] {
+	return &trace[T]{
+		hops: make(map[T]hop),
+	}
+}
+
+func traceFrom[T domain.NodeID](ctx context.Context) *trace[T] {
+	t, _ := ctx.Value(traceKey{}).(*trace[T])
+	return t
+}
+
+// start hop of contact about to be queried, contacts
+// not learned during the operation are known locally
+func (t *trace[T]) start(nodeID T) hop {
+	if t == nil {
+		return hop{}
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.hops[nodeID]
+}
+
+func (t *trace[T]) learn(contactSlice []*domain.Contact[T], h hop) {
+	if t == nil {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for _, c := range contactSlice {
+		known, ok := t.hops[c.ID]
+		if !ok || h.depth < known.depth || (h.depth == known.depth && h.at < known.at) {
+			t.hops[c.ID] = h
+		}
+	}
+}
+
+func (t *trace[T]) finish(at time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if at > t.end {
+		t.end = at
+	}
+}
+
+// depth of contact, zero if known before the operation
+func (t *trace[T]) depth(nodeID T) int {
+	return t.start(nodeID).depth
+}
+
+// elapsed virtual time until the last response or timeout
+func (t *trace[T]) elapsed() time.Duration {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.end
+}
@@ -0,0 +1,455 @@
+package dht
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+var (
+	// ErrNotFound no value stored under key
+	ErrNotFound = domain.ErrRecordNotFound
+	// ErrExpired record has already expired
+	ErrExpired = domain.ErrRecordExpired
+	// ErrOutdated newer record already stored under key
+	ErrOutdated = domain.ErrRecordOutdated
+	// ErrRejected record is invalid or may not replace the stored record
+	ErrRejected = domain.ErrRecordRejected
+)
+
+const (
+	// MaxValueSize bytes of a record value, a stored record still
+	// fits a fragmented udp response of the default packet size
+	MaxValueSize = 32 << 10
+	// MaxRecordsPerPublisher records of a single publisher kept in the store
+	MaxRecordsPerPublisher = 256
+	// MaxRecords records kept in the store, the publisher of
+	// unsigned records is not proven and bounds nothing
+	MaxRecords = 1 << 16
+)
+
+// valuePrefix key prefix of records persisted in kv
+var valuePrefix = []byte("dht/value/")
+
+// ValueStore dht records persisted in key value database
+type ValueStore[T domain.NodeID] struct {
+	mtx sync.Mutex
+	kv  domain.KV
+
+	// count and size of stored records, records persisted
+	// before the store was created are counted on first use
+	loaded bool
+	count  int
+	size   int
+	// publishers number of stored records by publisher
+	publishers map[T]int
+	// onChange receives count and size after every change
+	onChange func(count, size int)
+}
+
+// NewValueStore constructor
+func NewValueStore[T domain.NodeID](kv domain.KV) *ValueStore[T] {
+	return &ValueStore[T]{
+		kv:         kv,
+		publishers: make(map[T]int),
+	}
+}
+
+// Put record with expiry, existing records are only replaced
+// by the same or a newer version, see domain.CompareRecords.
+// mutable records must be signed and replaced by higher
+// sequence numbers of the same owner only. new records are
+// rejected once their publisher or the store is full
+func (vs *ValueStore[T]) Put(record *domain.Record[T], expires time.Time) error {
+
+	now := time.Now()
+	if !expires.After(now) {
+		return ErrExpired
+	}
+
+	if len(record.Value) > MaxValueSize {
+		return fmt.Errorf("%w value exceeds %d bytes", ErrRejected, MaxValueSize)
+	}
+
+	if err := domain.VerifyRecord(record); err != nil {
+		return fmt.Errorf("%w %w", ErrRejected, err)
+	}
+
+	vs.mtx.Lock()
+	defer vs.mtx.Unlock()
+
+	if err := vs.load(); err != nil {
+		return err
+	}
+
+	existing, err := vs.get(record.Key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if existing != nil {
+		if err := replaceable(&existing.Record, record); err != nil {
+			return err
+		}
+		if domain.CompareRecords(&existing.Record, record) == 0 && existing.Expires.After(expires) {
+			// same record with longer expiry
+			expires = existing.Expires
+		}
+	}
+
+	if existing == nil && vs.count >= MaxRecords {
+		return fmt.Errorf("%w store holds %d records", ErrRejected, MaxRecords)
+	}
+	if (existing == nil || existing.Publisher != record.Publisher) && vs.publishers[record.Publisher] >= MaxRecordsPerPublisher {
+		return fmt.Errorf("%w publisher holds %d records", ErrRejected, MaxRecordsPerPublisher)
+	}
+
+	if err := vs.put(&domain.StoredRecord[T]{
+		Record:   *record,
+		Expires:  expires,
+		StoredAt: now,
+	}); err != nil {
+		return err
+	}
+
+	if existing != nil {
+		vs.published(existing.Publisher, -1)
+		vs.published(record.Publisher, 1)
+		vs.changed(0, len(record.Value)-len(existing.Value))
+	} else {
+		vs.published(record.Publisher, 1)
+		vs.changed(1, len(record.Value))
+	}
+	return nil
+}
+
+// replaceable existing record may be replaced by record, signed
+// records take precedence over unsigned records under their key
+func replaceable[T domain.NodeID](existing, record *domain.Record[T]) error {
+	switch {
+	case existing.Mutable() && !record.Mutable():
+		return fmt.Errorf("%w unsigned record under mutable key", ErrRejected)
+	case !existing.Mutable() && record.Mutable():
+		return nil
+	case record.Mutable() && record.Seq == existing.Seq && !bytes.Equal(record.Signature, existing.Signature):
+		// conflicting write of the same version, first one wins
+		return ErrOutdated
+	case domain.CompareRecords(existing, record) > 0:
+		return ErrOutdated
+	}
+	return nil
+}
+
+// Get record by key
+func (vs *ValueStore[T]) Get(key T) (*domain.Record[T], error) {
+
+	vs.mtx.Lock()
+	defer vs.mtx.Unlock()
+
+	sr, err := vs.get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sr.Expires.After(time.Now()) {
+		return nil, ErrNotFound
+	}
+
+	record := sr.Record
+	return &record, nil
+}
+
+// Delete record by key
+func (vs *ValueStore[T]) Delete(key T) error {
+
+	vs.mtx.Lock()
+	defer vs.mtx.Unlock()
+
+	if err := vs.load(); err != nil {
+		return err
+	}
+
+	existing, err := vs.get(key)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := vs.kv.Delete(recordKey(key)); err != nil {
+		return fmt.Errorf("failed to delete record %v", err)
+	}
+
+	vs.published(existing.Publisher, -1)
+	vs.changed(-1, -len(existing.Value))
+	return nil
+}
+
+// Expire remove records expired before now,
+// returns number of removed records
+func (vs *ValueStore[T]) Expire(ctx context.Context, now time.Time) (int, error) {
+
+	records, err := vs.Records(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	vs.mtx.Lock()
+	defer vs.mtx.Unlock()
+
+	if err := vs.load(); err != nil {
+		return 0, err
+	}
+
+	var removed int
+	for _, sr := range records {
+		if sr.Expires.After(now) {
+			continue
+		}
+
+		// record may have been renewed since it was read
+		sr, err := vs.get(sr.Key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		} else if err != nil {
+			return removed, err
+		} else if sr.Expires.After(now) {
+			continue
+		}
+
+		if err := vs.kv.Delete(recordKey(sr.Key)); err != nil {
+			return removed, fmt.Errorf("failed to delete expired record %v", err)
+		}
+		vs.published(sr.Publisher, -1)
+		vs.changed(-1, -len(sr.Value))
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Records all persisted records sorted by key including
+// expired records that have not been removed yet
+func (vs *ValueStore[T]) Records(ctx context.Context) ([]*domain.StoredRecord[T], error) {
+
+	vs.mtx.Lock()
+	defer vs.mtx.Unlock()
+
+	return vs.records(ctx)
+}
+
+// Stats number of stored records and bytes of their values
+// including expired records that have not been removed yet
+func (vs *ValueStore[T]) Stats() (int, int, error) {
+
+	vs.mtx.Lock()
+	defer vs.mtx.Unlock()
+
+	if err := vs.load(); err != nil {
+		return 0, 0, err
+	}
+	return vs.count, vs.size, nil
+}
+
+// load count records persisted before the store was created
+func (vs *ValueStore[T]) load() error {
+
+	if vs.loaded {
+		return nil
+	}
+
+	records, err := vs.records(context.Background())
+	if err != nil {
+		return err
+	}
+
+	vs.loaded = true
+	for _, sr := range records {
+		vs.count++
+		vs.size += len(sr.Value)
+		vs.publishers[sr.Publisher]++
+	}
+
+	if vs.onChange != nil {
+		vs.onChange(vs.count, vs.size)
+	}
+	return nil
+}
+
+// changed adjust count and size of stored records
+func (vs *ValueStore[T]) changed(count, size int) {
+
+	vs.count += count
+	vs.size += size
+
+	if vs.onChange != nil {
+		vs.onChange(vs.count, vs.size)
+	}
+}
+
+// published adjust number of stored records of publisher
+func (vs *ValueStore[T]) published(publisher T, count int) {
+	vs.publishers[publisher] += count
+	if vs.publishers[publisher] <= 0 {
+		delete(vs.publishers, publisher)
+	}
+}
+
+func (vs *ValueStore[T]) records(ctx context.Context) ([]*domain.StoredRecord[T], error) {
+
+	it, err := vs.kv.Iterator(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator %v", err)
+	}
+
+	keys := make([][]byte, 0)
+	for it.Next() {
+		if bytes.HasPrefix(it.Key(), valuePrefix) {
+			keys = append(keys, bytes.Clone(it.Key()))
+		}
+	}
+
+	if err := it.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close iterator %v", err)
+	}
+
+	records := make([]*domain.StoredRecord[T], 0, len(keys))
+	for _, k := range keys {
+
+		key, err := domain.NodeIDFromBytes[T](k[len(valuePrefix):])
+		if err != nil {
+			// record of another id width
+			continue
+		}
+
+		sr, err := vs.get(key)
+		if err != nil {
+			// removed while iterating
+			continue
+		}
+		records = append(records, sr)
+	}
+
+	return records, nil
+}
+
+func (vs *ValueStore[T]) get(key T) (*domain.StoredRecord[T], error) {
+
+	v, err := vs.kv.Get(recordKey(key))
+	if errors.Is(err, domain.ErrKeyNotFound) || err == nil && len(v) == 0 {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get record %v", err)
+	}
+
+	var sr domain.StoredRecord[T]
+	if err := json.Unmarshal(v, &sr); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal record %v", err)
+	}
+
+	return &sr, nil
+}
+
+func (vs *ValueStore[T]) put(sr *domain.StoredRecord[T]) error {
+
+	v, err := json.Marshal(sr)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record %v", err)
+	}
+
+	if err := vs.kv.Put(recordKey(sr.Key), v); err != nil {
+		return fmt.Errorf("failed to persist record %v", err)
+	}
+
+	return nil
+}
+
+func recordKey[T domain.NodeID](key T) []byte {
+	return append(bytes.Clone(valuePrefix), domain.Bytes(key)...)
+}
+
+// memoryKV in memory key value database used when
+// no persistent database is configured
+type memoryKV struct {
+	mtx sync.RWMutex
+	m   map[string][]byte
+}
+
+// interface compliance
+var _ domain.KV = (*memoryKV)(nil)
+
+// NewMemoryKV constructor
+func NewMemoryKV() domain.KV {
+	return &memoryKV{
+		m: make(map[string][]byte),
+	}
+}
+
+func (m *memoryKV) Get(key []byte) ([]byte, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	v, ok := m.m[string(key)]
+	if !ok {
+		return []byte{}, domain.ErrKeyNotFound
+	}
+	return bytes.Clone(v), nil
+}
+
+func (m *memoryKV) Put(key, value []byte) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.m[string(key)] = bytes.Clone(value)
+	return nil
+}
+
+func (m *memoryKV) Delete(key []byte) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	delete(m.m, string(key))
+	return nil
+}
+
+func (m *memoryKV) Iterator(_ context.Context) (domain.KvIterator, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	keys := make([]string, 0, len(m.m))
+	for k := range m.m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return &memoryIterator{keys: keys, i: -1}, nil
+}
+
+func (m *memoryKV) Close() error {
+	return nil
+}
+
+type memoryIterator struct {
+	keys []string
+	i    int
+}
+
+func (mi *memoryIterator) Next() bool {
+	mi.i++
+	return mi.i < len(mi.keys)
+}
+
+func (mi *memoryIterator) Key() []byte {
+	return []byte(mi.keys[mi.i])
+}
+
+func (mi *memoryIterator) Close() error {
+	return nil
+}
@@ -0,0 +1,220 @@
+package dht_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structx/go-dpkg/domain"
+	"github.com/structx/go-dpkg/structs/dht"
+)
+
+func Test_ValueStore(t *testing.T) {
+	t.Run("newer_timestamp", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		vs := dht.NewValueStore[domain.NodeID224](dht.NewMemoryKV())
+		key := domain.HashKey[domain.NodeID224]([]byte("key"))
+		now := time.Now()
+
+		assert.NoError(vs.Put(&domain.Record[domain.NodeID224]{Key: key, Value: []byte("v2"), Timestamp: now}, now.Add(time.Hour)))
+		assert.ErrorIs(vs.Put(&domain.Record[domain.NodeID224]{Key: key, Value: []byte("v1"), Timestamp: now.Add(-time.Minute)}, now.Add(time.Hour)), dht.ErrOutdated)
+
+		record, err := vs.Get(key)
+		assert.NoError(err)
+		assert.Equal([]byte("v2"), record.Value)
+	})
+	t.Run("higher_seq", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		vs := dht.NewValueStore[domain.NodeID224](dht.NewMemoryKV())
+		key := domain.HashKey[domain.NodeID224]([]byte("key"))
+		now := time.Now()
+
+		assert.NoError(vs.Put(&domain.Record[domain.NodeID224]{Key: key, Value: []byte("v2"), Seq: 2, Timestamp: now.Add(-time.Minute)}, now.Add(time.Hour)))
+		assert.ErrorIs(vs.Put(&domain.Record[domain.NodeID224]{Key: key, Value: []byte("v1"), Seq: 1, Timestamp: now}, now.Add(time.Hour)), dht.ErrOutdated)
+
+		record, err := vs.Get(key)
+		assert.NoError(err)
+		assert.Equal([]byte("v2"), record.Value)
+	})
+	t.Run("mutable", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		_, owner, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(err)
+
+		vs := dht.NewValueStore[domain.NodeID224](dht.NewMemoryKV())
+		now := time.Now()
+
+		signed := func(seq uint64, value string) *domain.Record[domain.NodeID224] {
+			record := &domain.Record[domain.NodeID224]{Value: []byte(value), Salt: []byte("salt"), Seq: seq, Timestamp: now}
+			domain.SignRecord(owner, record)
+			return record
+		}
+
+		v1 := signed(1, "v1")
+		assert.NoError(vs.Put(v1, now.Add(time.Hour)))
+
+		// same version refreshes, conflicting or lower versions are refused
+		assert.NoError(vs.Put(signed(1, "v1"), now.Add(time.Hour)))
+		assert.ErrorIs(vs.Put(signed(1, "other"), now.Add(time.Hour)), dht.ErrOutdated)
+		assert.NoError(vs.Put(signed(2, "v2"), now.Add(time.Hour)))
+		assert.ErrorIs(vs.Put(v1, now.Add(time.Hour)), dht.ErrOutdated)
+
+		tampered := signed(3, "v3")
+		tampered.Value = []byte("tampered")
+		assert.ErrorIs(vs.Put(tampered, now.Add(time.Hour)), dht.ErrRejected)
+
+		unsigned := &domain.Record[domain.NodeID224]{Key: v1.Key, Value: []byte("squat"), Seq: 10, Timestamp: now}
+		assert.ErrorIs(vs.Put(unsigned, now.Add(time.Hour)), dht.ErrRejected)
+
+		record, err := vs.Get(v1.Key)
+		assert.NoError(err)
+		assert.Equal([]byte("v2"), record.Value)
+		assert.NoError(domain.VerifyRecord(record))
+	})
+	t.Run("expired", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		vs := dht.NewValueStore[domain.NodeID224](dht.NewMemoryKV())
+		now := time.Now()
+		k1, k2 := domain.HashKey[domain.NodeID224]([]byte("k1")), domain.HashKey[domain.NodeID224]([]byte("k2"))
+
+		assert.ErrorIs(vs.Put(&domain.Record[domain.NodeID224]{Key: k1, Timestamp: now}, now.Add(-time.Second)), dht.ErrExpired)
+
+		assert.NoError(vs.Put(&domain.Record[domain.NodeID224]{Key: k1, Timestamp: now}, now.Add(time.Minute)))
+		assert.NoError(vs.Put(&domain.Record[domain.NodeID224]{Key: k2, Timestamp: now}, now.Add(time.Hour)))
+
+		removed, err := vs.Expire(ctx, now.Add(time.Minute*2))
+		assert.NoError(err)
+		assert.Equal(1, removed)
+
+		_, err = vs.Get(k1)
+		assert.ErrorIs(err, dht.ErrNotFound)
+		_, err = vs.Get(k2)
+		assert.NoError(err)
+	})
+	t.Run("stats", func(t *testing.T) {
+
+		assert := assert.New(t)
+		ctx := context.TODO()
+
+		kv := dht.NewMemoryKV()
+		vs := dht.NewValueStore[domain.NodeID224](kv)
+		now := time.Now()
+		k1, k2 := domain.HashKey[domain.NodeID224]([]byte("k1")), domain.HashKey[domain.NodeID224]([]byte("k2"))
+
+		assert.NoError(vs.Put(&domain.Record[domain.NodeID224]{Key: k1, Value: []byte("v1"), Timestamp: now}, now.Add(time.Minute)))
+		assert.NoError(vs.Put(&domain.Record[domain.NodeID224]{Key: k1, Value: []byte("v1v1"), Timestamp: now.Add(time.Second)}, now.Add(time.Minute)))
+		assert.NoError(vs.Put(&domain.Record[domain.NodeID224]{Key: k2, Value: []byte("v2"), Timestamp: now}, now.Add(time.Hour)))
+
+		count, size, err := vs.Stats()
+		assert.NoError(err)
+		assert.Equal(2, count)
+		assert.Equal(6, size)
+
+		// records persisted by another store are counted
+		count, size, err = dht.NewValueStore[domain.NodeID224](kv).Stats()
+		assert.NoError(err)
+		assert.Equal(2, count)
+		assert.Equal(6, size)
+
+		_, err = vs.Expire(ctx, now.Add(time.Minute*2))
+		assert.NoError(err)
+		assert.NoError(vs.Delete(k1))
+
+		count, size, err = vs.Stats()
+		assert.NoError(err)
+		assert.Equal(1, count)
+		assert.Equal(2, size)
+
+		assert.NoError(vs.Delete(k2))
+		count, size, err = vs.Stats()
+		assert.NoError(err)
+		assert.Equal(0, count)
+		assert.Equal(0, size)
+	})
+	t.Run("limits", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		vs := dht.NewValueStore[domain.NodeID224](dht.NewMemoryKV())
+		publisher := domain.HashKey[domain.NodeID224]([]byte("publisher"))
+		now := time.Now()
+
+		record := func(i int) *domain.Record[domain.NodeID224] {
+			return &domain.Record[domain.NodeID224]{
+				Key:       domain.HashKey[domain.NodeID224]([]byte(fmt.Sprintf("k%d", i))),
+				Value:     []byte("value"),
+				Publisher: publisher,
+				Timestamp: now,
+			}
+		}
+
+		large := record(-1)
+		large.Value = make([]byte, dht.MaxValueSize+1)
+		assert.ErrorIs(vs.Put(large, now.Add(time.Hour)), dht.ErrRejected)
+
+		for i := range dht.MaxRecordsPerPublisher {
+			assert.NoError(vs.Put(record(i), now.Add(time.Hour)))
+		}
+		assert.ErrorIs(vs.Put(record(dht.MaxRecordsPerPublisher), now.Add(time.Hour)), dht.ErrRejected)
+
+		// stored records of a full publisher are still renewed
+		renewed := record(0)
+		renewed.Timestamp = now.Add(time.Second)
+		assert.NoError(vs.Put(renewed, now.Add(time.Hour)))
+
+		// other publishers are not affected
+		other := record(dht.MaxRecordsPerPublisher)
+		other.Publisher = domain.HashKey[domain.NodeID224]([]byte("other"))
+		assert.NoError(vs.Put(other, now.Add(time.Hour)))
+
+		assert.NoError(vs.Delete(record(0).Key))
+		assert.NoError(vs.Put(record(dht.MaxRecordsPerPublisher+1), now.Add(time.Hour)))
+	})
+	t.Run("kv_failure", func(t *testing.T) {
+
+		assert := assert.New(t)
+
+		kv := &failingKV{KV: dht.NewMemoryKV()}
+		vs := dht.NewValueStore[domain.NodeID224](kv)
+		key := domain.HashKey[domain.NodeID224]([]byte("key"))
+		now := time.Now()
+
+		_, err := vs.Get(key)
+		assert.ErrorIs(err, dht.ErrNotFound)
+
+		// storage failures are not reported as missing records
+		kv.err = errors.New("disk failure")
+		_, err = vs.Get(key)
+		assert.Error(err)
+		assert.NotErrorIs(err, dht.ErrNotFound)
+		assert.Error(vs.Put(&domain.Record[domain.NodeID224]{Key: key, Timestamp: now}, now.Add(time.Hour)))
+	})
+}
+
+// failingKV key value database failing reads with err
+type failingKV struct {
+	domain.KV
+	err error
+}
+
+func (f *failingKV) Get(key []byte) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.KV.Get(key)
+}
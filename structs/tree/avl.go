@@ -0,0 +1,67 @@
+package tree
+
+import (
+	"sync"
+
+	"github.com/structx/go-dpkg/domain"
+)
+
+// AVL self balancing binary search tree ordered by the bytes of its keys,
+// the heights of the subtrees of every node differ by at most one
+type AVL struct {
+	*workers
+
+	mtx sync.RWMutex
+	m   *Map[[28]byte, interface{}]
+}
+
+// NewAVLWithDefault constructor with default values
+func NewAVLWithDefault() *AVL {
+	a := &AVL{m: NewMap[[28]byte, interface{}](compare)}
+	a.workers = newWorkers(a, domain.Concurrent)
+	return a
+}
+
+// Len number of keys in tree
+func (a *AVL) Len() int {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.m.Len()
+}
+
+// Height number of nodes on the longest path from root to leaf
+func (a *AVL) Height() int {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.m.height()
+}
+
+// insert key, the value of an existing key is replaced
+func (a *AVL) insert(key [28]byte, value interface{}) {
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.m.Set(key, value)
+}
+
+// search result of key, empty if key is not in tree
+func (a *AVL) search(key [28]byte) SearchResult {
+
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	value, ok := a.m.Get(key)
+	if !ok {
+		return SearchResult{}
+	}
+	return SearchResult{key: key, value: value}
+}
+
+func (a *AVL) delete(key [28]byte) {
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.m.Delete(key)
+}
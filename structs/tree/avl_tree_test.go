@@ -0,0 +1,171 @@
+package tree_test
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structx/go-dpkg/structs/tree"
+)
+
+// sequentialKey key of i with the leading bytes shared like masked bucket ids
+func sequentialKey(i int) [28]byte {
+	var k [28]byte
+	binary.BigEndian.PutUint64(k[20:], uint64(i))
+	return k
+}
+
+// maxHeight upper bound of the height of an avl tree with n keys
+func maxHeight(n int) int {
+	return int(1.4405 * math.Log2(float64(n)+2))
+}
+
+func Test_AVLSequentialKeys(t *testing.T) {
+
+	ctx := context.TODO()
+	assert := assert.New(t)
+
+	avl := tree.NewAVLWithDefault()
+	avl.Run(ctx)
+	defer func() { assert.NoError(avl.Close()) }()
+
+	for i := range 1024 {
+		avl.Insert(ctx, sequentialKey(i), i)
+	}
+
+	assert.Equal(1024, avl.Len())
+	assert.LessOrEqual(avl.Height(), 11)
+
+	for i := range 1024 {
+		assert.Equal(i, avl.Search(ctx, sequentialKey(i)).GetValue())
+	}
+
+	// deleting half of the keys keeps the tree balanced
+	for i := 0; i < 1024; i += 2 {
+		avl.Delete(ctx, sequentialKey(i))
+	}
+
+	assert.Equal(512, avl.Len())
+	assert.LessOrEqual(avl.Height(), maxHeight(512))
+
+	for i := range 1024 {
+		result := avl.Search(ctx, sequentialKey(i))
+		if i%2 == 0 {
+			assert.Nil(result.GetValue())
+		} else {
+			assert.Equal(i, result.GetValue())
+		}
+	}
+}
+
+// Test_AVLInvariants random inserts and deletes keep the contents of the
+// tree equal to a map and its height within the bound of avl trees
+func Test_AVLInvariants(t *testing.T) {
+
+	ctx := context.TODO()
+	assert := assert.New(t)
+
+	property := func(keys []uint8, deletes []bool) bool {
+
+		avl := tree.NewAVLWithDefault()
+		avl.Run(ctx)
+		defer func() { assert.NoError(avl.Close()) }()
+
+		expected := map[int]int{}
+		for i, k := range keys {
+
+			key := sequentialKey(int(k))
+			if i < len(deletes) && deletes[i] {
+				avl.Delete(ctx, key)
+				delete(expected, int(k))
+				continue
+			}
+
+			avl.Insert(ctx, key, i)
+			expected[int(k)] = i
+
+			if avl.Len() != len(expected) || avl.Height() > maxHeight(len(expected)) {
+				return false
+			}
+		}
+
+		if avl.Len() != len(expected) || avl.Height() > maxHeight(len(expected)) {
+			return false
+		}
+
+		for k := range 256 {
+			v, ok := expected[k]
+			result := avl.Search(ctx, sequentialKey(k))
+			if ok && result.GetValue() != v || !ok && result.GetValue() != nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	assert.NoError(quick.Check(property, &quick.Config{MaxCount: 200}))
+}
+
+func benchmarkSequentialInsert(b *testing.B, insert func(context.Context, [28]byte, interface{})) {
+
+	ctx := context.TODO()
+
+	for i := 0; i < b.N; i++ {
+		insert(ctx, sequentialKey(i), i)
+	}
+}
+
+func benchmarkSequentialSearch(b *testing.B, insert func(context.Context, [28]byte, interface{}), search func(context.Context, [28]byte) *tree.SearchResult) {
+
+	ctx := context.TODO()
+
+	for i := range 2048 {
+		insert(ctx, sequentialKey(i), i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		search(ctx, sequentialKey(i%2048))
+	}
+}
+
+// BenchmarkBSTInsert degrades to a linked list with sequential keys
+func BenchmarkBSTInsert(b *testing.B) {
+
+	bst := tree.NewBSTWithDefault()
+	bst.Run(context.TODO())
+	defer func() { _ = bst.Close() }()
+
+	benchmarkSequentialInsert(b, bst.Insert)
+}
+
+func BenchmarkAVLInsert(b *testing.B) {
+
+	avl := tree.NewAVLWithDefault()
+	avl.Run(context.TODO())
+	defer func() { _ = avl.Close() }()
+
+	benchmarkSequentialInsert(b, avl.Insert)
+}
+
+func BenchmarkBSTSearch(b *testing.B) {
+
+	bst := tree.NewBSTWithDefault()
+	bst.Run(context.TODO())
+	defer func() { _ = bst.Close() }()
+
+	benchmarkSequentialSearch(b, bst.Insert, bst.Search)
+}
+
+func BenchmarkAVLSearch(b *testing.B) {
+
+	avl := tree.NewAVLWithDefault()
+	avl.Run(context.TODO())
+	defer func() { _ = avl.Close() }()
+
+	benchmarkSequentialSearch(b, avl.Insert, avl.Search)
+}
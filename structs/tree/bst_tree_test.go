@@ -66,3 +66,69 @@ func Test_Delete(t *testing.T) {
 	result := bst.Search(ctx, k1)
 	assert.Equal("1", result.GetValue())
 }
+
+func Test_FullWidthKeys(t *testing.T) {
+
+	ctx := context.TODO()
+	assert := assert.New(t)
+
+	bst := tree.NewBSTWithDefault()
+	bst.Run(ctx)
+	defer func() { assert.NoError(bst.Close()) }()
+
+	// keys differing only in bytes beyond the first eight
+	var a, b [28]byte
+	a[20], b[27] = 1, 1
+
+	bst.Insert(ctx, a, "a")
+	bst.Insert(ctx, b, "b")
+
+	result := bst.Search(ctx, a)
+	assert.Equal(a, result.GetKey())
+	assert.Equal("a", result.GetValue())
+
+	result = bst.Search(ctx, b)
+	assert.Equal(b, result.GetKey())
+	assert.Equal("b", result.GetValue())
+
+	// existing keys are updated in place
+	bst.Insert(ctx, a, "c")
+	assert.Equal("c", bst.Search(ctx, a).GetValue())
+
+	bst.Delete(ctx, a)
+	assert.Nil(bst.Search(ctx, a).GetValue())
+	assert.Equal("b", bst.Search(ctx, b).GetValue())
+}
+
+func Test_DeleteNodes(t *testing.T) {
+
+	ctx := context.TODO()
+	assert := assert.New(t)
+
+	bst := tree.NewBSTWithDefault()
+	bst.Run(ctx)
+	defer func() { assert.NoError(bst.Close()) }()
+
+	key := func(b byte) [28]byte {
+		var k [28]byte
+		k[0] = b
+		return k
+	}
+
+	// root with two children, left only, right only and leaf nodes
+	for _, b := range []byte{50, 30, 70, 20, 60, 80, 65} {
+		bst.Insert(ctx, key(b), b)
+	}
+
+	remaining := map[byte]bool{50: true, 30: true, 70: true, 20: true, 60: true, 80: true, 65: true}
+	for _, b := range []byte{50, 30, 60, 42, 80, 20, 70, 65} {
+
+		bst.Delete(ctx, key(b))
+		delete(remaining, b)
+
+		for k := range remaining {
+			assert.Equal(k, bst.Search(ctx, key(k)).GetValue())
+		}
+		assert.Nil(bst.Search(ctx, key(b)).GetValue())
+	}
+}
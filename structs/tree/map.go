@@ -0,0 +1,336 @@
+package tree
+
+import (
+	"cmp"
+)
+
+type mapNode[K, V any] struct {
+	key         K
+	value       V
+	height      int
+	left, right *mapNode[K, V]
+}
+
+// Map ordered map of keys to values balanced as avl tree, like builtin
+// maps it is not safe for concurrent use
+type Map[K, V any] struct {
+	root *mapNode[K, V]
+	size int
+	cmp  func(a, b K) int
+}
+
+// NewMap constructor ordering keys by cmp, which returns a negative number
+// if a is less than b, a positive number if a is greater and zero if equal
+func NewMap[K, V any](cmp func(a, b K) int) *Map[K, V] {
+	return &Map[K, V]{cmp: cmp}
+}
+
+// NewOrderedMap constructor ordering keys by their natural order
+func NewOrderedMap[K cmp.Ordered, V any]() *Map[K, V] {
+	return NewMap[K, V](cmp.Compare[K])
+}
+
+// Len number of keys in map
+func (m *Map[K, V]) Len() int {
+	return m.size
+}
+
+// Get value of key
+func (m *Map[K, V]) Get(key K) (V, bool) {
+
+	current := m.root
+	for current != nil {
+		switch c := m.cmp(key, current.key); {
+		case c < 0:
+			current = current.left
+		case c > 0:
+			current = current.right
+		default:
+			return current.value, true
+		}
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set value of key, the value of an existing key is replaced
+func (m *Map[K, V]) Set(key K, value V) {
+	m.root = m.set(m.root, key, value)
+}
+
+func (m *Map[K, V]) set(n *mapNode[K, V], key K, value V) *mapNode[K, V] {
+
+	if n == nil {
+		m.size++
+		return &mapNode[K, V]{key: key, value: value, height: 1}
+	}
+
+	switch c := m.cmp(key, n.key); {
+	case c < 0:
+		n.left = m.set(n.left, key, value)
+	case c > 0:
+		n.right = m.set(n.right, key, value)
+	default:
+		n.value = value
+		return n
+	}
+
+	return rebalance(n)
+}
+
+// Delete key from map, false if key is not in map
+func (m *Map[K, V]) Delete(key K) bool {
+
+	size := m.size
+	m.root = m.delete(m.root, key)
+	return m.size < size
+}
+
+func (m *Map[K, V]) delete(n *mapNode[K, V], key K) *mapNode[K, V] {
+
+	if n == nil {
+		// node not found
+		return nil
+	}
+
+	switch c := m.cmp(key, n.key); {
+	case c < 0:
+		n.left = m.delete(n.left, key)
+	case c > 0:
+		n.right = m.delete(n.right, key)
+	default:
+
+		m.size--
+
+		// node with at most one child is replaced by it
+		if n.left == nil {
+			return n.right
+		} else if n.right == nil {
+			return n.left
+		}
+
+		// node with two children is replaced by its in order successor
+		var successor *mapNode[K, V]
+		n.right, successor = deleteMin(n.right)
+		successor.left, successor.right = n.left, n.right
+		n = successor
+	}
+
+	return rebalance(n)
+}
+
+// Min smallest key of map and its value
+func (m *Map[K, V]) Min() (K, V, bool) {
+
+	if m.root == nil {
+		return none[K, V]()
+	}
+
+	n := m.root
+	for n.left != nil {
+		n = n.left
+	}
+	return n.key, n.value, true
+}
+
+// Max greatest key of map and its value
+func (m *Map[K, V]) Max() (K, V, bool) {
+
+	if m.root == nil {
+		return none[K, V]()
+	}
+
+	n := m.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.value, true
+}
+
+// Floor greatest key less than or equal to key and its value
+func (m *Map[K, V]) Floor(key K) (K, V, bool) {
+
+	var floor *mapNode[K, V]
+
+	current := m.root
+	for current != nil {
+		switch c := m.cmp(key, current.key); {
+		case c < 0:
+			current = current.left
+		case c > 0:
+			floor, current = current, current.right
+		default:
+			return current.key, current.value, true
+		}
+	}
+
+	if floor == nil {
+		return none[K, V]()
+	}
+	return floor.key, floor.value, true
+}
+
+// Ceiling smallest key greater than or equal to key and its value
+func (m *Map[K, V]) Ceiling(key K) (K, V, bool) {
+
+	var ceiling *mapNode[K, V]
+
+	current := m.root
+	for current != nil {
+		switch c := m.cmp(key, current.key); {
+		case c < 0:
+			ceiling, current = current, current.left
+		case c > 0:
+			current = current.right
+		default:
+			return current.key, current.value, true
+		}
+	}
+
+	if ceiling == nil {
+		return none[K, V]()
+	}
+	return ceiling.key, ceiling.value, true
+}
+
+// Ascend call fn for every key in ascending order until fn returns false
+func (m *Map[K, V]) Ascend(fn func(key K, value V) bool) {
+	m.ascend(m.root, nil, nil, fn)
+}
+
+// AscendRange call fn for keys in [greaterOrEqual, lessThan) in ascending
+// order until fn returns false
+func (m *Map[K, V]) AscendRange(greaterOrEqual, lessThan K, fn func(key K, value V) bool) {
+	m.ascend(m.root, &greaterOrEqual, &lessThan, fn)
+}
+
+// Descend call fn for every key in descending order until fn returns false
+func (m *Map[K, V]) Descend(fn func(key K, value V) bool) {
+	m.descend(m.root, nil, nil, fn)
+}
+
+// DescendRange call fn for keys in (greaterThan, lessOrEqual] in descending
+// order until fn returns false
+func (m *Map[K, V]) DescendRange(lessOrEqual, greaterThan K, fn func(key K, value V) bool) {
+	m.descend(m.root, &lessOrEqual, &greaterThan, fn)
+}
+
+// ascend in order traversal of keys in [lower, upper), nil bounds are
+// unbounded, false once fn stopped the iteration
+func (m *Map[K, V]) ascend(n *mapNode[K, V], lower, upper *K, fn func(K, V) bool) bool {
+
+	if n == nil {
+		return true
+	}
+
+	// subtrees outside of bounds are skipped
+	if lower != nil && m.cmp(n.key, *lower) < 0 {
+		return m.ascend(n.right, lower, upper, fn)
+	}
+	if upper != nil && m.cmp(n.key, *upper) >= 0 {
+		return m.ascend(n.left, lower, upper, fn)
+	}
+
+	return m.ascend(n.left, lower, upper, fn) && fn(n.key, n.value) && m.ascend(n.right, lower, upper, fn)
+}
+
+// descend reverse order traversal of keys in (lower, upper], nil bounds are
+// unbounded, false once fn stopped the iteration
+func (m *Map[K, V]) descend(n *mapNode[K, V], upper, lower *K, fn func(K, V) bool) bool {
+
+	if n == nil {
+		return true
+	}
+
+	// subtrees outside of bounds are skipped
+	if upper != nil && m.cmp(n.key, *upper) > 0 {
+		return m.descend(n.left, upper, lower, fn)
+	}
+	if lower != nil && m.cmp(n.key, *lower) <= 0 {
+		return m.descend(n.right, upper, lower, fn)
+	}
+
+	return m.descend(n.right, upper, lower, fn) && fn(n.key, n.value) && m.descend(n.left, upper, lower, fn)
+}
+
+// height number of nodes on the longest path from root to leaf
+func (m *Map[K, V]) height() int {
+	return height(m.root)
+}
+
+func none[K, V any]() (K, V, bool) {
+	var (
+		key   K
+		value V
+	)
+	return key, value, false
+}
+
+// deleteMin remove smallest node of subtree n, returns new subtree and removed node
+func deleteMin[K, V any](n *mapNode[K, V]) (*mapNode[K, V], *mapNode[K, V]) {
+
+	if n.left == nil {
+		return n.right, n
+	}
+
+	var smallest *mapNode[K, V]
+	n.left, smallest = deleteMin(n.left)
+	return rebalance(n), smallest
+}
+
+// rebalance restore height and balance of n after one of its subtrees changed by one level
+func rebalance[K, V any](n *mapNode[K, V]) *mapNode[K, V] {
+
+	update(n)
+
+	switch balance(n) {
+	case 2:
+		if balance(n.left) < 0 {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	case -2:
+		if balance(n.right) > 0 {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	default:
+		return n
+	}
+}
+
+func rotateLeft[K, V any](n *mapNode[K, V]) *mapNode[K, V] {
+
+	r := n.right
+	n.right, r.left = r.left, n
+
+	update(n)
+	update(r)
+	return r
+}
+
+func rotateRight[K, V any](n *mapNode[K, V]) *mapNode[K, V] {
+
+	l := n.left
+	n.left, l.right = l.right, n
+
+	update(n)
+	update(l)
+	return l
+}
+
+func update[K, V any](n *mapNode[K, V]) {
+	n.height = 1 + max(height(n.left), height(n.right))
+}
+
+func balance[K, V any](n *mapNode[K, V]) int {
+	return height(n.left) - height(n.right)
+}
+
+func height[K, V any](n *mapNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
@@ -0,0 +1,212 @@
+package tree_test
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structx/go-dpkg/structs/tree"
+)
+
+// collect keys visited by iteration, stopping after limit keys
+func collect(limit int) (*[]int, func(int, string) bool) {
+	var keys []int
+	return &keys, func(k int, _ string) bool {
+		keys = append(keys, k)
+		return len(keys) < limit
+	}
+}
+
+func Test_Map(t *testing.T) {
+
+	assert := assert.New(t)
+
+	m := tree.NewOrderedMap[int, string]()
+
+	_, _, ok := m.Min()
+	assert.False(ok)
+
+	for _, k := range []int{50, 30, 70, 20, 60, 80, 65} {
+		m.Set(k, "v")
+	}
+	m.Set(60, "sixty")
+
+	assert.Equal(7, m.Len())
+
+	v, ok := m.Get(60)
+	assert.True(ok)
+	assert.Equal("sixty", v)
+
+	_, ok = m.Get(61)
+	assert.False(ok)
+
+	assert.True(m.Delete(50))
+	assert.False(m.Delete(50))
+	assert.Equal(6, m.Len())
+
+	k, _, ok := m.Min()
+	assert.True(ok)
+	assert.Equal(20, k)
+
+	k, _, ok = m.Max()
+	assert.True(ok)
+	assert.Equal(80, k)
+
+	k, v, ok = m.Floor(64)
+	assert.True(ok)
+	assert.Equal(60, k)
+	assert.Equal("sixty", v)
+
+	k, _, ok = m.Floor(65)
+	assert.True(ok)
+	assert.Equal(65, k)
+
+	_, _, ok = m.Floor(19)
+	assert.False(ok)
+
+	k, _, ok = m.Ceiling(31)
+	assert.True(ok)
+	assert.Equal(60, k)
+
+	_, _, ok = m.Ceiling(81)
+	assert.False(ok)
+}
+
+func Test_MapIteration(t *testing.T) {
+
+	assert := assert.New(t)
+
+	m := tree.NewOrderedMap[int, string]()
+	for k := range 10 {
+		m.Set(k*10, "v")
+	}
+
+	keys, fn := collect(100)
+	m.Ascend(fn)
+	assert.Equal([]int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90}, *keys)
+
+	keys, fn = collect(100)
+	m.Descend(fn)
+	assert.Equal([]int{90, 80, 70, 60, 50, 40, 30, 20, 10, 0}, *keys)
+
+	// lower bounds are inclusive and upper bounds exclusive when ascending
+	keys, fn = collect(100)
+	m.AscendRange(20, 60, fn)
+	assert.Equal([]int{20, 30, 40, 50}, *keys)
+
+	// and the other way around when descending
+	keys, fn = collect(100)
+	m.DescendRange(60, 20, fn)
+	assert.Equal([]int{60, 50, 40, 30}, *keys)
+
+	keys, fn = collect(100)
+	m.AscendRange(15, 16, fn)
+	assert.Empty(*keys)
+
+	// callbacks stop iteration early
+	keys, fn = collect(3)
+	m.Ascend(fn)
+	assert.Equal([]int{0, 10, 20}, *keys)
+
+	keys, fn = collect(2)
+	m.DescendRange(75, 0, fn)
+	assert.Equal([]int{70, 60}, *keys)
+}
+
+func Test_MapComparator(t *testing.T) {
+
+	assert := assert.New(t)
+
+	// keys ordered by a comparator of their bytes
+	m := tree.NewMap[[]byte, int](bytes.Compare)
+	m.Set([]byte("b"), 2)
+	m.Set([]byte("a"), 1)
+	m.Set([]byte("c"), 3)
+
+	v, ok := m.Get([]byte("a"))
+	assert.True(ok)
+	assert.Equal(1, v)
+
+	var values []int
+	m.Descend(func(_ []byte, v int) bool {
+		values = append(values, v)
+		return true
+	})
+	assert.Equal([]int{3, 2, 1}, values)
+}
+
+// Test_MapInvariants random sets and deletes keep the map equal to a
+// sorted set of its keys
+func Test_MapInvariants(t *testing.T) {
+
+	property := func(keys []int8, deletes []bool, from, to int8) bool {
+
+		m := tree.NewOrderedMap[int, string]()
+		expected := map[int]bool{}
+
+		for i, k := range keys {
+			if i < len(deletes) && deletes[i] {
+				if m.Delete(int(k)) != expected[int(k)] {
+					return false
+				}
+				delete(expected, int(k))
+				continue
+			}
+			m.Set(int(k), "v")
+			expected[int(k)] = true
+		}
+
+		var sorted []int
+		for k := range expected {
+			sorted = append(sorted, k)
+		}
+		sort.Ints(sorted)
+
+		if m.Len() != len(sorted) {
+			return false
+		}
+
+		var ascending, descending []int
+		m.AscendRange(int(from), int(to), func(k int, _ string) bool {
+			ascending = append(ascending, k)
+			return true
+		})
+		m.DescendRange(int(to), int(from), func(k int, _ string) bool {
+			descending = append(descending, k)
+			return true
+		})
+
+		var inRange, inReverseRange []int
+		for _, k := range sorted {
+			if k >= int(from) && k < int(to) {
+				inRange = append(inRange, k)
+			}
+			if k > int(from) && k <= int(to) {
+				inReverseRange = append([]int{k}, inReverseRange...)
+			}
+		}
+
+		if !assert.ObjectsAreEqual(inRange, ascending) || !assert.ObjectsAreEqual(inReverseRange, descending) {
+			return false
+		}
+
+		// floor and ceiling agree with the sorted keys
+		i := sort.SearchInts(sorted, int(from))
+		floor, _, ok := m.Floor(int(from))
+		if i < len(sorted) && sorted[i] == int(from) {
+			if !ok || floor != int(from) {
+				return false
+			}
+		} else if i == 0 && ok || i > 0 && (!ok || floor != sorted[i-1]) {
+			return false
+		}
+
+		ceiling, _, ok := m.Ceiling(int(from))
+		return i == len(sorted) && !ok || i < len(sorted) && ok && ceiling == sorted[i]
+	}
+
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 500}))
+}
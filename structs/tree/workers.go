@@ -0,0 +1,321 @@
+package tree
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// opEnum operation type
+type opEnum int
+
+const (
+	// insert
+	insertOp opEnum = iota
+	// search
+	searchOp
+	// delete
+	deleteOp
+)
+
+// bst operation
+type op interface {
+	// getter operation type
+	getType() opEnum
+	// getter operation node key
+	getKey() [28]byte
+	// getter operation node value
+	getValue() interface{}
+}
+
+type searchParams struct {
+	key [28]byte
+	op  opEnum
+	ch  chan SearchResult
+}
+
+// SearchResult operation search result
+type SearchResult struct {
+	key   [28]byte
+	value interface{}
+}
+
+// GetKey getter search result key
+func (sr *SearchResult) GetKey() [28]byte {
+	return sr.key
+}
+
+// GetValue getter search result value
+func (sr *SearchResult) GetValue() interface{} {
+	return sr.value
+}
+
+func (sp *searchParams) getType() opEnum {
+	return sp.op
+}
+
+func (sp *searchParams) getKey() [28]byte {
+	return sp.key
+}
+
+// not implemented
+func (sp *searchParams) getValue() interface{} {
+	return nil
+}
+
+type insertParams struct {
+	op    opEnum
+	key   [28]byte
+	value interface{}
+	ch    chan struct{}
+}
+
+func (ip *insertParams) getType() opEnum {
+	return ip.op
+}
+
+func (ip *insertParams) getKey() [28]byte {
+	return ip.key
+}
+
+func (ip *insertParams) getValue() interface{} {
+	return ip.value
+}
+
+type deleteParams struct {
+	op  opEnum
+	key [28]byte
+	ch  chan struct{}
+}
+
+func (dp *deleteParams) getType() opEnum {
+	return dp.op
+}
+
+func (dp *deleteParams) getKey() [28]byte {
+	return dp.key
+}
+
+// not implemented
+func (dp *deleteParams) getValue() interface{} {
+	return nil
+}
+
+// store tree operations executed by workers
+type store interface {
+	// insert key, replacing the value of an existing key
+	insert(key [28]byte, value interface{})
+	// search result of key, empty if key is not in tree
+	search(key [28]byte) SearchResult
+	// delete key from tree
+	delete(key [28]byte)
+}
+
+// workers execute insert, search and delete requests on store
+type workers struct {
+	s store
+
+	// concurrency
+	cc       int
+	errGroup *errgroup.Group
+	baseCtx  context.Context
+	ch       chan op
+}
+
+func newWorkers(s store, cc int) *workers {
+	return &workers{
+		s:  s,
+		cc: cc,
+		ch: nil, // intentionally set nil
+	}
+}
+
+// Run create and start workers
+func (w *workers) Run(ctx context.Context) {
+
+	w.ch = make(chan op)
+
+	w.errGroup, w.baseCtx = errgroup.WithContext(ctx)
+	for i := 0; i < w.cc; i++ {
+		w.errGroup.Go(func() error {
+			err := w.worker(ctx, w.ch)
+			if err != nil {
+				return fmt.Errorf("failed worker %v", err)
+			}
+			return nil
+		})
+	}
+
+}
+
+// Close channels and wait for err group finish
+func (w *workers) Close() error {
+	w.baseCtx.Done()
+	close(w.ch)
+	return w.errGroup.Wait()
+}
+
+// Insert into tree
+func (w *workers) Insert(ctx context.Context, key [28]byte, value interface{}) {
+
+	p := &insertParams{
+		op:    insertOp,
+		key:   key,
+		value: value,
+		ch:    make(chan struct{}),
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// wait for acknowledgement
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.ch:
+				wg.Done()
+				return
+			}
+		}
+	}()
+
+	// send request
+	go func() {
+		w.ch <- p
+	}()
+
+	// wait for acknowledge received
+	wg.Wait()
+}
+
+// Search on tree
+func (w *workers) Search(ctx context.Context, key [28]byte) *SearchResult {
+
+	p := &searchParams{
+		key: key,
+		op:  searchOp,
+		ch:  make(chan SearchResult, 1),
+	}
+	defer close(p.ch)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	var output SearchResult
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case result, ok := <-p.ch:
+				if !ok {
+					return
+				}
+				output = result
+				wg.Done()
+				return
+			}
+		}
+	}()
+
+	// send request
+	go func() {
+		w.ch <- p
+	}()
+
+	// wait for result
+	wg.Wait()
+
+	return &output
+}
+
+// Delete key in tree
+func (w *workers) Delete(ctx context.Context, key [28]byte) {
+
+	p := &deleteParams{
+		key: key,
+		op:  deleteOp,
+		ch:  make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// wait for acknowledgement
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.ch:
+				wg.Done()
+				return
+			}
+		}
+	}()
+
+	// send request
+	go func() {
+		w.ch <- p
+	}()
+
+	// wait for acknowledge received
+	wg.Wait()
+}
+
+func (w *workers) worker(ctx context.Context, ch <-chan op) error {
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case op, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			switch op.getType() {
+			case insertOp:
+
+				p, ok := op.(*insertParams)
+				if !ok {
+					return errors.New("unable to cast operation as insert params")
+				}
+
+				w.s.insert(p.key, p.value)
+
+				// acknowledge operation completion
+				p.ch <- struct{}{}
+
+			case searchOp:
+				p, ok := op.(*searchParams)
+				if !ok {
+					return errors.New("unable to cast operation as search params")
+				}
+
+				p.ch <- w.s.search(p.key)
+
+			case deleteOp:
+				p, ok := op.(*deleteParams)
+				if !ok {
+					return errors.New("unable to cast operation as delete params")
+				}
+
+				w.s.delete(p.key)
+
+				// acknowledge operation completion
+				p.ch <- struct{}{}
+
+			default:
+				// unsuported operation ignore
+				continue
+			}
+		}
+	}
+
+}
@@ -1,16 +1,44 @@
 package encode
 
-import "golang.org/x/crypto/sha3"
+import (
+	"hash"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Key fixed width key of a supported sha3 digest size
+type Key interface {
+	~[28]byte | ~[32]byte | ~[48]byte | ~[64]byte
+}
 
 // HashKey use sha3 to hash key
 func HashKey(key []byte) [28]byte {
+	return Hash[[28]byte](key)
+}
+
+// Hash use sha3 with digest size matching width of T to hash key
+func Hash[T Key](key []byte) T {
+
+	var result T
+
+	var h hash.Hash
+	switch len(result) {
+	case 28:
+		h = sha3.New224()
+	case 32:
+		h = sha3.New256()
+	case 48:
+		h = sha3.New384()
+	default:
+		h = sha3.New512()
+	}
 
-	h := sha3.New224()
 	h.Write(key)
 	hash := h.Sum(nil)
 
-	var result [28]byte
-	copy(result[:], hash[:28])
+	for i := 0; i < len(result); i++ {
+		result[i] = hash[i]
+	}
 
 	return result
 }
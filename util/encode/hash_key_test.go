@@ -0,0 +1,22 @@
+package encode_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/structx/go-dpkg/util/encode"
+)
+
+func Test_Hash(t *testing.T) {
+
+	assert := assert.New(t)
+	key := []byte("hello world")
+
+	assert.Equal(sha3.Sum224(key), encode.HashKey(key))
+	assert.Equal(sha3.Sum224(key), encode.Hash[[28]byte](key))
+	assert.Equal(sha3.Sum256(key), encode.Hash[[32]byte](key))
+	assert.Equal(sha3.Sum384(key), encode.Hash[[48]byte](key))
+	assert.Equal(sha3.Sum512(key), encode.Hash[[64]byte](key))
+}
@@ -2,12 +2,12 @@
 package encode
 
 // MaskFromPrefix created zero filled mask shifting prefix
-func MaskFromPrefix(nodeID [28]byte, level int) [28]byte {
+func MaskFromPrefix[T Key](nodeID T, level int) T {
 
-	var mask [28]byte
+	var mask T
 
 	// initialize mask zero filled
-	for i := range mask {
+	for i := 0; i < len(mask); i++ {
 		mask[i] = 0x00
 	}
 
@@ -15,7 +15,7 @@ func MaskFromPrefix(nodeID [28]byte, level int) [28]byte {
 	prefixLength := uint(8) * uint(level)
 
 	// create prefix mask by shifting left
-	for i := range mask {
+	for i := 0; i < len(mask); i++ {
 		// invert the index to create a left shift based on prefix length
 		shift := uint(len(mask)) - uint(i) - 1
 		if shift < prefixLength {
@@ -25,9 +25,9 @@ func MaskFromPrefix(nodeID [28]byte, level int) [28]byte {
 		}
 	}
 
-	result := [28]byte{}
+	var result T
 	// perform bitwise AND to extract prefix
-	for i := range result {
+	for i := 0; i < len(result); i++ {
 		result[i] = nodeID[i] & mask[i]
 	}
 